@@ -10,6 +10,7 @@ import (
 	"os/signal"
 	"sync"
 	"syscall"
+	_ "time/tzdata"
 
 	"github.com/joho/godotenv"
 )
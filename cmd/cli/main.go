@@ -7,6 +7,7 @@ import (
 	"fundaNotifier/internal/pkg/config"
 	"fundaNotifier/internal/pkg/logger"
 	"sync"
+	_ "time/tzdata"
 
 	"github.com/joho/godotenv"
 )
@@ -2,9 +2,17 @@ package app
 
 import (
 	"context"
+	"fundaNotifier/internal/domain/blacklist"
+	"fundaNotifier/internal/domain/dnd_windows"
 	"fundaNotifier/internal/domain/listings"
+	"fundaNotifier/internal/domain/outbox"
+	"fundaNotifier/internal/domain/preferences"
+	"fundaNotifier/internal/domain/scoring"
 	"fundaNotifier/internal/domain/search_queries"
 	"fundaNotifier/internal/domain/sessions"
+	"fundaNotifier/internal/domain/urgent_rules"
+	"fundaNotifier/internal/domain/viewings"
+	"fundaNotifier/internal/domain/watched_listings"
 	"fundaNotifier/internal/infrastructure"
 	"fundaNotifier/internal/infrastructure/repository/mysql"
 	"fundaNotifier/internal/integration"
@@ -15,20 +23,36 @@ import (
 )
 
 type Domain struct {
-	Listings      *listings.Service
-	SearchQueries *search_queries.Service
-	Sessions      *sessions.Service
+	Listings        *listings.Service
+	SearchQueries   *search_queries.Service
+	Sessions        *sessions.Service
+	DNDWindows      *dnd_windows.Service
+	Outbox          *outbox.Service
+	UrgentRules     *urgent_rules.Service
+	WatchedListings *watched_listings.Service
+	Blacklist       *blacklist.Service
+	Viewings        *viewings.Service
+	Scoring         *scoring.Service
+	Preferences     *preferences.Service
 }
 type App struct {
-	Config            *config.Config
-	Infra             *infrastructure.Infrastructure
-	Integration       *integration.Integration
-	Domain            *Domain
-	Log               *zerolog.Logger
-	Wg                *sync.WaitGroup
-	ListingsRepo      *mysql.ListingsRepository
-	SearchQueriesRepo *mysql.SearchQueriesRepository
-	SessionsRepo      *mysql.SessionsRepository
+	Config              *config.Config
+	Infra               *infrastructure.Infrastructure
+	Integration         *integration.Integration
+	Domain              *Domain
+	Log                 *zerolog.Logger
+	Wg                  *sync.WaitGroup
+	ListingsRepo        *mysql.ListingsRepository
+	SearchQueriesRepo   *mysql.SearchQueriesRepository
+	SessionsRepo        *mysql.SessionsRepository
+	DNDWindowsRepo      *mysql.DNDWindowsRepository
+	OutboxRepo          *mysql.OutboxRepository
+	UrgentRulesRepo     *mysql.UrgentRulesRepository
+	WatchedListingsRepo *mysql.WatchedListingsRepository
+	BlacklistRepo       *mysql.BlacklistRepository
+	ViewingsRepo        *mysql.ViewingsRepository
+	ScoringRepo         *mysql.ScoringRepository
+	PreferencesRepo     *mysql.PreferencesRepository
 }
 
 func New(ctx context.Context, cfg *config.Config, wg *sync.WaitGroup, log *zerolog.Logger) *App {
@@ -53,7 +77,23 @@ func (a *App) Setup() {
 	a.ListingsRepo = mysql.NewListingsRepository(a.Infra.MySqlRepo)
 	a.SearchQueriesRepo = mysql.NewSearchQueriesRepository(a.Infra.MySqlRepo)
 	a.SessionsRepo = mysql.NewSessionsRepository(a.Infra.MySqlRepo)
-	a.Domain.Listings = listings.NewService(a.ListingsRepo, a.Integration.FundaAPIClient, a.Log)
-	a.Domain.SearchQueries = search_queries.NewService(a.SearchQueriesRepo, a.Log)
-	a.Domain.Sessions = sessions.NewService(a.SessionsRepo, a.Domain.Listings, a.Domain.SearchQueries, a.Log)
+	a.DNDWindowsRepo = mysql.NewDNDWindowsRepository(a.Infra.MySqlRepo)
+	a.OutboxRepo = mysql.NewOutboxRepository(a.Infra.MySqlRepo)
+	a.UrgentRulesRepo = mysql.NewUrgentRulesRepository(a.Infra.MySqlRepo)
+	a.WatchedListingsRepo = mysql.NewWatchedListingsRepository(a.Infra.MySqlRepo)
+	a.BlacklistRepo = mysql.NewBlacklistRepository(a.Infra.MySqlRepo)
+	a.ViewingsRepo = mysql.NewViewingsRepository(a.Infra.MySqlRepo)
+	a.ScoringRepo = mysql.NewScoringRepository(a.Infra.MySqlRepo)
+	a.PreferencesRepo = mysql.NewPreferencesRepository(a.Infra.MySqlRepo)
+	a.Domain.Viewings = viewings.NewService(a.ViewingsRepo, &a.Config.Viewings, a.Log)
+	a.Domain.Listings = listings.NewService(a.ListingsRepo, a.Integration.FundaAPIClient, a.Domain.Viewings, &a.Config.Listings, a.Log)
+	a.Domain.SearchQueries = search_queries.NewService(a.SearchQueriesRepo, a.Domain.Listings, a.Log)
+	a.Domain.DNDWindows = dnd_windows.NewService(a.DNDWindowsRepo, a.Log)
+	a.Domain.Outbox = outbox.NewService(a.OutboxRepo, a.Log)
+	a.Domain.UrgentRules = urgent_rules.NewService(a.UrgentRulesRepo, a.Log)
+	a.Domain.WatchedListings = watched_listings.NewService(a.WatchedListingsRepo, a.Domain.Listings, &a.Config.Watched, a.Log)
+	a.Domain.Blacklist = blacklist.NewService(a.BlacklistRepo, a.Log)
+	a.Domain.Scoring = scoring.NewService(a.ScoringRepo, a.Log)
+	a.Domain.Preferences = preferences.NewService(a.PreferencesRepo, a.Log)
+	a.Domain.Sessions = sessions.NewService(a.SessionsRepo, a.Domain.Listings, a.Domain.SearchQueries, a.Domain.DNDWindows, a.Domain.Outbox, a.Domain.UrgentRules, a.Domain.WatchedListings, a.Domain.Blacklist, a.Domain.Viewings, a.Domain.Scoring, a.Domain.Preferences, a.Log)
 }
@@ -13,7 +13,7 @@ type Bot struct {
 }
 
 func New(app *app.App) *Bot {
-	bot := tgbot.NewTelegramBot(&app.Config.TelegramBot, app.Log, app.Domain.Listings, app.Domain.Sessions, app.Domain.SearchQueries)
+	bot := tgbot.NewTelegramBot(&app.Config.TelegramBot, app.Log, app.Domain.Listings, app.Domain.Sessions, app.Domain.SearchQueries, app.Domain.DNDWindows, app.Domain.Outbox, app.Domain.UrgentRules, app.Domain.WatchedListings, app.Domain.Blacklist, app.Domain.Viewings, app.Domain.Scoring, app.Domain.Preferences)
 	botInstance := &Bot{
 		App: app,
 		bot: bot,
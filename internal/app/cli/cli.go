@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"fundaNotifier/internal/app"
 	"fundaNotifier/internal/app/cli/commands/manager"
+	"fundaNotifier/internal/app/cli/commands/search"
 	"fundaNotifier/internal/app/cli/commands/storage"
 	"os"
 
@@ -20,10 +21,14 @@ func New(app *app.App) *Cli {
 	commandMigrate := storage.NewMigrateCommand(app.Log, app.Infra.MySqlRepo)
 	commandSendMessage := manager.NewSendMessageCommand(app.Log, app.Domain.Sessions)
 	commandShowSessions := manager.NewShowSessionsCommand(app.Log, app.Domain.Sessions)
+	commandBuildQuery := search.NewBuildQueryCommand(app.Log)
+	commandParseQuery := search.NewParseQueryCommand(app.Log)
 	commands := []*urfave.Command{
 		commandMigrate.Describe(),
 		commandSendMessage.Describe(),
 		commandShowSessions.Describe(),
+		commandBuildQuery.Describe(),
+		commandParseQuery.Describe(),
 	}
 
 	cliApp := &urfave.App{
@@ -56,12 +56,12 @@ func (t *ShowSessionsCommand) Execute(ctx *cli.Context) error {
 	}
 
 	table := tablewriter.NewWriter(os.Stdout)
-	header := []string{"UserID", "ChatID", "Sync Interval", "Regions", "Cities", "Last Sync Ts", "IsActive"}
+	header := []string{"UserID", "ChatID", "Sync Interval", "Schedule", "Timezone", "Regions", "Cities", "Last Sync Ts", "IsActive"}
 	table.SetHeader(header)
 	for idx := range selectedSessions {
 		var row []string
 		pollingInterval := time.Duration(selectedSessions[idx].UpdateIntervalSeconds) * time.Second
-		row = append(row, selectedSessions[idx].UserID, strconv.FormatInt(selectedSessions[idx].ChatID, 10), pollingInterval.String(), selectedSessions[idx].RegionsRaw, selectedSessions[idx].CitiesRaw, selectedSessions[idx].LastSyncedAt.Format(time.RFC3339), strconv.FormatBool(selectedSessions[idx].IsActive))
+		row = append(row, selectedSessions[idx].UserID, strconv.FormatInt(selectedSessions[idx].ChatID, 10), pollingInterval.String(), selectedSessions[idx].Schedule, selectedSessions[idx].Timezone, selectedSessions[idx].RegionsRaw, selectedSessions[idx].CitiesRaw, selectedSessions[idx].LastSyncedAt.Format(time.RFC3339), strconv.FormatBool(selectedSessions[idx].IsActive))
 		table.Append(row)
 	}
 	table.SetAutoWrapText(false)
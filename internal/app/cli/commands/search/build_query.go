@@ -0,0 +1,86 @@
+package search
+
+import (
+	"fmt"
+	"fundaNotifier/internal/pkg/funda"
+
+	"github.com/rs/zerolog"
+	"github.com/urfave/cli/v2"
+)
+
+type BuildQueryCommand struct {
+	log *zerolog.Logger
+}
+
+func NewBuildQueryCommand(logger *zerolog.Logger) *BuildQueryCommand {
+	return &BuildQueryCommand{
+		log: logger,
+	}
+}
+
+func (t *BuildQueryCommand) Describe() *cli.Command {
+	return &cli.Command{
+		Category: "search",
+		Name:     "search:buildQuery",
+		Usage:    "Build a Funda search URL from structured parameters",
+		Action:   t.Execute,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "offering",
+				Usage: "Either `huur` (rent) or `koop` (buy)",
+				Value: funda.OfferingRent,
+			},
+			&cli.StringSliceFlag{
+				Name:  "area",
+				Usage: "Area(s) to search in, e.g. `utrecht` (repeat the flag for several areas)",
+				Value: cli.NewStringSlice("nl"),
+			},
+			&cli.IntFlag{
+				Name:  "priceMin",
+				Usage: "Minimal price, 0 means open",
+			},
+			&cli.IntFlag{
+				Name:  "priceMax",
+				Usage: "Maximal price, 0 means open",
+			},
+			&cli.StringSliceFlag{
+				Name:  "objectType",
+				Usage: "Object type(s), e.g. `apartment` (repeat the flag for several types)",
+			},
+			&cli.IntFlag{
+				Name:  "floorAreaMin",
+				Usage: "Minimal floor area in square meters",
+			},
+			&cli.IntFlag{
+				Name:  "floorAreaMax",
+				Usage: "Maximal floor area in square meters, 0 means open",
+			},
+			&cli.IntFlag{
+				Name:  "publicationDate",
+				Usage: "Listings published within the given number of days (1, 3, 5, 10 or 30)",
+			},
+		},
+	}
+}
+
+func (t *BuildQueryCommand) Execute(ctx *cli.Context) error {
+	query := funda.SearchQuery{
+		Offering:        ctx.String("offering"),
+		Areas:           ctx.StringSlice("area"),
+		PriceMin:        ctx.Int("priceMin"),
+		PriceMax:        ctx.Int("priceMax"),
+		ObjectTypes:     ctx.StringSlice("objectType"),
+		FloorAreaMin:    ctx.Int("floorAreaMin"),
+		FloorAreaMax:    ctx.Int("floorAreaMax"),
+		PublicationDate: ctx.Int("publicationDate"),
+	}
+
+	URL, err := query.Build()
+	if err != nil {
+		t.log.Error().Err(err).Msg("failed to execute CLI command")
+		return fmt.Errorf("failed to execute CLI command: %w", err)
+	}
+
+	fmt.Println(URL)
+	return nil
+}
@@ -0,0 +1,68 @@
+package search
+
+import (
+	"fmt"
+	"fundaNotifier/internal/pkg/funda"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/rs/zerolog"
+	"github.com/urfave/cli/v2"
+)
+
+type ParseQueryCommand struct {
+	log *zerolog.Logger
+}
+
+func NewParseQueryCommand(logger *zerolog.Logger) *ParseQueryCommand {
+	return &ParseQueryCommand{
+		log: logger,
+	}
+}
+
+func (t *ParseQueryCommand) Describe() *cli.Command {
+	return &cli.Command{
+		Category: "search",
+		Name:     "search:parseQuery",
+		Usage:    "Parse a Funda search URL into structured parameters",
+		Action:   t.Execute,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     "url",
+				Usage:    "Funda search URL copied from browser",
+				Required: true,
+			},
+		},
+	}
+}
+
+func (t *ParseQueryCommand) Execute(ctx *cli.Context) error {
+	query, warnings, err := funda.Inspect(ctx.String("url"))
+	if err != nil {
+		t.log.Error().Err(err).Msg("failed to execute CLI command")
+		return fmt.Errorf("failed to execute CLI command: %w", err)
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Parameter", "Value"})
+	table.Append([]string{"offering", query.Offering})
+	table.Append([]string{"area", strings.Join(query.Areas, ", ")})
+	table.Append([]string{"priceMin", strconv.Itoa(query.PriceMin)})
+	table.Append([]string{"priceMax", strconv.Itoa(query.PriceMax)})
+	table.Append([]string{"objectType", strings.Join(query.ObjectTypes, ", ")})
+	table.Append([]string{"floorAreaMin", strconv.Itoa(query.FloorAreaMin)})
+	table.Append([]string{"floorAreaMax", strconv.Itoa(query.FloorAreaMax)})
+	table.Append([]string{"publicationDate", strconv.Itoa(query.PublicationDate)})
+	for key := range query.Extra {
+		table.Append([]string{key + " (unmanaged)", strings.Join(query.Extra[key], ", ")})
+	}
+	table.Render()
+
+	for idx := range warnings {
+		fmt.Println("warning: " + warnings[idx])
+	}
+
+	return nil
+}
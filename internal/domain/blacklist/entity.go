@@ -0,0 +1,117 @@
+package blacklist
+
+import (
+	"fundaNotifier/internal/domain/listings"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	KindStreet   = "street"
+	KindPostcode = "postcode"
+	KindKeyword  = "keyword"
+	KindAgent    = "agent"
+)
+
+var Kinds = []string{KindStreet, KindPostcode, KindKeyword, KindAgent}
+
+// HiddenListing is a listing permanently suppressed by the user.
+type HiddenListing struct {
+	UserID    string
+	ObjectID  string
+	Name      string
+	URL       string
+	CreatedAt time.Time
+}
+
+type HiddenListings []HiddenListing
+
+// Rule suppresses every listing matching its value, see Match for the semantics of each kind.
+type Rule struct {
+	UserID    string
+	Kind      string
+	Value     string
+	CreatedAt time.Time
+}
+
+// NormalizeRuleValue brings a rule value to the form it is stored and matched in, postcodes lose spaces.
+func NormalizeRuleValue(kind, value string) string {
+	value = strings.Join(strings.Fields(strings.ToLower(value)), " ")
+	if kind == KindPostcode {
+		value = strings.ReplaceAll(value, " ", "")
+	}
+	return value
+}
+
+func IsValidKind(kind string) bool {
+	return slices.Contains(Kinds, kind)
+}
+
+// Match reports whether a listing falls under the rule: streets, keywords and agents match as case-insensitive
+// substrings of the street address, of the name and description, and of the agent respectively, while postcodes match
+// as prefixes so that "1012" covers the whole postcode area.
+func (r *Rule) Match(listing *listings.Listing) bool {
+	switch r.Kind {
+	case KindStreet:
+		return strings.Contains(strings.ToLower(listing.Address.StreetAddress), r.Value)
+	case KindPostcode:
+		postcode := NormalizeRuleValue(KindPostcode, listing.Address.PostalCode)
+		return postcode != "" && strings.HasPrefix(postcode, r.Value)
+	case KindKeyword:
+		return strings.Contains(strings.ToLower(listing.Name), r.Value) || strings.Contains(strings.ToLower(listing.Description), r.Value)
+	case KindAgent:
+		return strings.Contains(strings.ToLower(listing.Agent), r.Value)
+	default:
+		return false
+	}
+}
+
+type Rules []Rule
+
+// Filter holds everything a user has hidden, it is built once and applied to as many listings as needed.
+type Filter struct {
+	hiddenObjectIDs map[string]struct{}
+	rules           Rules
+}
+
+func NewFilter(hiddenListings HiddenListings, rules Rules) *Filter {
+	filter := Filter{
+		hiddenObjectIDs: make(map[string]struct{}, len(hiddenListings)),
+		rules:           rules,
+	}
+	for idx := range hiddenListings {
+		filter.hiddenObjectIDs[hiddenListings[idx].ObjectID] = struct{}{}
+	}
+	return &filter
+}
+
+func (f *Filter) IsExcluded(listing *listings.Listing) bool {
+	if f == nil {
+		return false
+	}
+	if _, ok := f.hiddenObjectIDs[listing.ObjectID]; ok {
+		return true
+	}
+	for idx := range f.rules {
+		if f.rules[idx].Match(listing) {
+			return true
+		}
+	}
+	return false
+}
+
+// Apply returns listings which are neither hidden nor blacklisted, a nil filter lets everything through.
+func (f *Filter) Apply(l listings.Listings) listings.Listings {
+	if f == nil || len(l) == 0 {
+		return l
+	}
+
+	result := make(listings.Listings, 0, len(l))
+	for idx := range l {
+		if !f.IsExcluded(&l[idx]) {
+			result = append(result, l[idx])
+		}
+	}
+	return result
+}
@@ -0,0 +1,17 @@
+package blacklist
+
+import (
+	"context"
+	"fundaNotifier/internal/domain"
+)
+
+type Repository interface {
+	UpsertHiddenListing(ctx context.Context, listing *HiddenListing) error
+	MGetHiddenListingByUserID(ctx context.Context, userID string) (HiddenListings, error)
+	DeleteHiddenListingByUserIDAndObjectID(ctx context.Context, userID, objectID string) error
+	MDeleteHiddenListingByUserIDTx(ctx context.Context, tx domain.Tx, userID string) error
+	InsertRule(ctx context.Context, rule *Rule) error
+	MGetRuleByUserID(ctx context.Context, userID string) (Rules, error)
+	DeleteRuleByUserIDAndKindAndValue(ctx context.Context, userID, kind, value string) error
+	MDeleteRuleByUserIDTx(ctx context.Context, tx domain.Tx, userID string) error
+}
@@ -0,0 +1,131 @@
+package blacklist
+
+import (
+	"context"
+	"fmt"
+	"fundaNotifier/internal/domain"
+	"fundaNotifier/internal/domain/listings"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+type Service struct {
+	repository Repository
+	log        *zerolog.Logger
+}
+
+func NewService(
+	repository Repository,
+	log *zerolog.Logger,
+) *Service {
+	return &Service{
+		repository: repository,
+		log:        log,
+	}
+}
+
+func (s *Service) Hide(ctx context.Context, userID string, listing *listings.Listing) error {
+	hiddenListing := HiddenListing{
+		UserID:    userID,
+		ObjectID:  listing.ObjectID,
+		Name:      listing.Name,
+		URL:       listing.URL,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	err := s.repository.UpsertHiddenListing(ctx, &hiddenListing)
+	if err != nil {
+		s.log.Error().Err(err).Str("userID", userID).Str("objectID", listing.ObjectID).Msg("failed to hide listing")
+		return fmt.Errorf("failed to hide listing: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Service) Unhide(ctx context.Context, userID, objectID string) error {
+	err := s.repository.DeleteHiddenListingByUserIDAndObjectID(ctx, userID, objectID)
+	if err != nil {
+		s.log.Error().Err(err).Str("userID", userID).Str("objectID", objectID).Msg("failed to unhide listing")
+		return fmt.Errorf("failed to unhide listing: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Service) MGetHiddenListingByUserID(ctx context.Context, userID string) (HiddenListings, error) {
+	hiddenListings, err := s.repository.MGetHiddenListingByUserID(ctx, userID)
+	if err != nil {
+		s.log.Error().Err(err).Str("userID", userID).Msg("failed to get hidden listings")
+		return nil, fmt.Errorf("failed to get hidden listings: %w", err)
+	}
+
+	return hiddenListings, nil
+}
+
+// AddRule stores a blacklist rule, kind must be one of Kinds and value is normalized before being stored.
+func (s *Service) AddRule(ctx context.Context, userID, kind, value string) (*Rule, error) {
+	rule := Rule{
+		UserID:    userID,
+		Kind:      kind,
+		Value:     NormalizeRuleValue(kind, value),
+		CreatedAt: time.Now().UTC(),
+	}
+
+	err := s.repository.InsertRule(ctx, &rule)
+	if err != nil {
+		s.log.Error().Err(err).Str("userID", userID).Msg("failed to insert blacklist rule")
+		return nil, fmt.Errorf("failed to insert blacklist rule: %w", err)
+	}
+
+	return &rule, nil
+}
+
+func (s *Service) RemoveRule(ctx context.Context, userID, kind, value string) error {
+	err := s.repository.DeleteRuleByUserIDAndKindAndValue(ctx, userID, kind, NormalizeRuleValue(kind, value))
+	if err != nil {
+		s.log.Error().Err(err).Str("userID", userID).Msg("failed to delete blacklist rule")
+		return fmt.Errorf("failed to delete blacklist rule: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Service) MGetRuleByUserID(ctx context.Context, userID string) (Rules, error) {
+	rules, err := s.repository.MGetRuleByUserID(ctx, userID)
+	if err != nil {
+		s.log.Error().Err(err).Str("userID", userID).Msg("failed to get blacklist rules")
+		return nil, fmt.Errorf("failed to get blacklist rules: %w", err)
+	}
+
+	return rules, nil
+}
+
+// GetFilter collects hidden listings and blacklist rules of a user into a filter.
+func (s *Service) GetFilter(ctx context.Context, userID string) (*Filter, error) {
+	hiddenListings, err := s.MGetHiddenListingByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := s.MGetRuleByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewFilter(hiddenListings, rules), nil
+}
+
+func (s *Service) MDeleteByUserIDTx(ctx context.Context, tx domain.Tx, userID string) error {
+	if err := s.repository.MDeleteHiddenListingByUserIDTx(ctx, tx, userID); err != nil {
+		s.log.Error().Err(err).Str("userID", userID).Msg("failed to delete hidden listings")
+		return fmt.Errorf("failed to delete hidden listings: %w", err)
+	}
+
+	if err := s.repository.MDeleteRuleByUserIDTx(ctx, tx, userID); err != nil {
+		s.log.Error().Err(err).Str("userID", userID).Msg("failed to delete blacklist rules")
+		return fmt.Errorf("failed to delete blacklist rules: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,166 @@
+package dnd_windows
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var weekdayNames = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+var weekdayPresets = map[string][]time.Weekday{
+	"daily":    {time.Sunday, time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday},
+	"weekdays": {time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday},
+	"weekends": {time.Saturday, time.Sunday},
+}
+
+type Window struct {
+	UserID      string
+	Name        string
+	WeekdaysRaw string
+	Weekdays    []time.Weekday
+	Start       int
+	End         int
+}
+
+func (w *Window) ParseRawWeekdays() {
+	w.Weekdays = []time.Weekday{}
+	for _, day := range strings.Split(w.WeekdaysRaw, ",") {
+		number, err := strconv.Atoi(strings.TrimSpace(day))
+		if err != nil || number < int(time.Sunday) || number > int(time.Saturday) {
+			continue
+		}
+		if !slices.Contains(w.Weekdays, time.Weekday(number)) {
+			w.Weekdays = append(w.Weekdays, time.Weekday(number))
+		}
+	}
+	slices.Sort(w.Weekdays)
+
+	rawDays := make([]string, 0, len(w.Weekdays))
+	for idx := range w.Weekdays {
+		rawDays = append(rawDays, strconv.Itoa(int(w.Weekdays[idx])))
+	}
+	w.WeekdaysRaw = strings.Join(rawDays, ",")
+}
+
+// Contains reports whether ts (already converted to the user's location) falls within the window.
+// Weekdays refer to the day a window starts, so an overnight window set for Friday lasts until Saturday morning.
+func (w *Window) Contains(ts time.Time) bool {
+	minutes := ts.Hour()*60 + ts.Minute()
+	today := ts.Weekday()
+	yesterday := ts.AddDate(0, 0, -1).Weekday()
+
+	switch {
+	case w.Start == w.End:
+		return slices.Contains(w.Weekdays, today)
+	case w.Start < w.End:
+		return slices.Contains(w.Weekdays, today) && minutes >= w.Start && minutes < w.End
+	default:
+		return (slices.Contains(w.Weekdays, today) && minutes >= w.Start) ||
+			(slices.Contains(w.Weekdays, yesterday) && minutes < w.End)
+	}
+}
+
+func (w *Window) FormatWeekdays() string {
+	for _, preset := range []string{"daily", "weekdays", "weekends"} {
+		if slices.Equal(sortedWeekdays(weekdayPresets[preset]), w.Weekdays) {
+			return preset
+		}
+	}
+
+	days := make([]string, 0, len(w.Weekdays))
+	for idx := range w.Weekdays {
+		days = append(days, w.Weekdays[idx].String()[:3])
+	}
+	return strings.Join(days, ",")
+}
+
+type Windows []Window
+
+func (w *Windows) Contains(ts time.Time) bool {
+	if w == nil || len(*w) == 0 {
+		return false
+	}
+	for idx := range *w {
+		if (*w)[idx].Contains(ts) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseWeekdays parses a comma-separated list of weekday names and ranges (e.g. `mon-fri`, `sat,sun`) or a preset.
+func ParseWeekdays(str string) ([]time.Weekday, error) {
+	str = strings.ToLower(strings.TrimSpace(str))
+	if str == "" {
+		return sortedWeekdays(weekdayPresets["daily"]), nil
+	}
+	if preset, ok := weekdayPresets[str]; ok {
+		return sortedWeekdays(preset), nil
+	}
+
+	result := make([]time.Weekday, 0, len(weekdayNames))
+	for _, part := range strings.Split(str, ",") {
+		part = strings.TrimSpace(part)
+		bounds := strings.Split(part, "-")
+		if len(bounds) > 2 {
+			return nil, fmt.Errorf("invalid weekday range %q", part)
+		}
+
+		first, ok := weekdayNames[shortDayName(bounds[0])]
+		if !ok {
+			return nil, fmt.Errorf("invalid weekday %q", bounds[0])
+		}
+		last := first
+		if len(bounds) == 2 {
+			last, ok = weekdayNames[shortDayName(bounds[1])]
+			if !ok {
+				return nil, fmt.Errorf("invalid weekday %q", bounds[1])
+			}
+		}
+
+		// ranges may wrap around the week, e.g. `fri-mon`
+		for day := first; ; day = (day + 1) % 7 {
+			if !slices.Contains(result, day) {
+				result = append(result, day)
+			}
+			if day == last {
+				break
+			}
+		}
+	}
+
+	return sortedWeekdays(result), nil
+}
+
+func FormatRawWeekdays(weekdays []time.Weekday) string {
+	rawDays := make([]string, 0, len(weekdays))
+	for idx := range weekdays {
+		rawDays = append(rawDays, strconv.Itoa(int(weekdays[idx])))
+	}
+	return strings.Join(rawDays, ",")
+}
+
+func shortDayName(name string) string {
+	name = strings.TrimSpace(name)
+	if len(name) > 3 {
+		return name[:3]
+	}
+	return name
+}
+
+func sortedWeekdays(weekdays []time.Weekday) []time.Weekday {
+	result := slices.Clone(weekdays)
+	slices.Sort(result)
+	return result
+}
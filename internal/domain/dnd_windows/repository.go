@@ -0,0 +1,13 @@
+package dnd_windows
+
+import (
+	"context"
+	"fundaNotifier/internal/domain"
+)
+
+type Repository interface {
+	UpsertWindow(ctx context.Context, window *Window) error
+	MGetWindowByUserID(ctx context.Context, userID string) (Windows, error)
+	DeleteWindowByUserIDAndName(ctx context.Context, userID, name string) error
+	MDeleteWindowByUserIDTx(ctx context.Context, tx domain.Tx, userID string) error
+}
@@ -0,0 +1,85 @@
+package dnd_windows
+
+import (
+	"context"
+	"fmt"
+	"fundaNotifier/internal/domain"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+type Service struct {
+	repository Repository
+	log        *zerolog.Logger
+}
+
+func NewService(
+	repository Repository,
+	log *zerolog.Logger,
+) *Service {
+	return &Service{
+		repository: repository,
+		log:        log,
+	}
+}
+
+func (s *Service) AddWindow(ctx context.Context, userID, name string, weekdays []time.Weekday, start, end int) error {
+	window := Window{
+		UserID:      userID,
+		Name:        strings.ToLower(strings.TrimSpace(name)),
+		WeekdaysRaw: FormatRawWeekdays(weekdays),
+		Start:       start,
+		End:         end,
+	}
+	window.ParseRawWeekdays()
+
+	err := s.repository.UpsertWindow(ctx, &window)
+	if err != nil {
+		s.log.Error().Err(err).Str("userID", userID).Msg("failed to upsert DND window")
+		return fmt.Errorf("failed to upsert DND window: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Service) MGetWindowByUserID(ctx context.Context, userID string) (Windows, error) {
+	windows, err := s.repository.MGetWindowByUserID(ctx, userID)
+	if err != nil {
+		s.log.Error().Err(err).Str("userID", userID).Msg("failed to get DND windows")
+		return nil, fmt.Errorf("failed to get DND windows: %w", err)
+	}
+
+	return windows, nil
+}
+
+func (s *Service) RemoveWindow(ctx context.Context, userID, name string) error {
+	err := s.repository.DeleteWindowByUserIDAndName(ctx, userID, strings.ToLower(strings.TrimSpace(name)))
+	if err != nil {
+		s.log.Error().Err(err).Str("userID", userID).Msg("failed to delete DND window")
+		return fmt.Errorf("failed to delete DND window: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Service) MDeleteWindowByUserIDTx(ctx context.Context, tx domain.Tx, userID string) error {
+	err := s.repository.MDeleteWindowByUserIDTx(ctx, tx, userID)
+	if err != nil {
+		s.log.Error().Err(err).Str("userID", userID).Msg("failed to delete DND windows")
+		return fmt.Errorf("failed to delete DND windows: %w", err)
+	}
+
+	return nil
+}
+
+// IsWithinDND evaluates all DND windows of the user against ts, which must be in the user's location.
+func (s *Service) IsWithinDND(ctx context.Context, userID string, ts time.Time) (bool, error) {
+	windows, err := s.MGetWindowByUserID(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+
+	return windows.Contains(ts), nil
+}
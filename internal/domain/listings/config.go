@@ -0,0 +1,14 @@
+package listings
+
+import "time"
+
+type Config struct {
+	// MaxPages and MaxListings cap a single search query sync, zero disables a cap
+	MaxPages    int `env:"FUNDA_MAX_PAGES" env-default:"20"`
+	MaxListings int `env:"FUNDA_MAX_LISTINGS" env-default:"300"`
+	// MaxMissedSyncs is how many syncs in a row a listing of a query exceeding the caps may be missing from the results
+	// before it is deleted, such a listing may be beyond the cap rather than gone, zero deletes it right away
+	MaxMissedSyncs int `env:"FUNDA_MAX_MISSED_SYNCS" env-default:"10"`
+	// FavoritesCheckInterval is how often favorites are re-fetched to detect price and status changes
+	FavoritesCheckInterval time.Duration `env:"FUNDA_FAVORITES_CHECK_INTERVAL" env-default:"6h"`
+}
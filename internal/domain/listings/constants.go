@@ -3,4 +3,34 @@ package listings
 const (
 	defaultCapacity        = 50
 	defaultStartPageNumber = 1
+	previewItemsCount      = 5
 )
+
+const (
+	StatusAvailable                 = "available"
+	StatusUnderOption               = "under option"
+	StatusUnderOffer                = "under offer"
+	StatusSoldSubjectToConditions   = "sold subject to conditions"
+	StatusSold                      = "sold"
+	StatusRentedSubjectToConditions = "rented subject to conditions"
+	StatusRented                    = "rented"
+)
+
+// statusLabels maps labels shown on a listing page in both languages to a status, longer labels go first
+var statusLabels = []struct {
+	label  string
+	status string
+}{
+	{"verkocht onder voorbehoud", StatusSoldSubjectToConditions},
+	{"sold subject to conditions", StatusSoldSubjectToConditions},
+	{"verhuurd onder voorbehoud", StatusRentedSubjectToConditions},
+	{"rented subject to conditions", StatusRentedSubjectToConditions},
+	{"onder optie", StatusUnderOption},
+	{"under option", StatusUnderOption},
+	{"onder bod", StatusUnderOffer},
+	{"under offer", StatusUnderOffer},
+	{"verkocht", StatusSold},
+	{"sold", StatusSold},
+	{"verhuurd", StatusRented},
+	{"rented", StatusRented},
+}
@@ -1,28 +1,50 @@
 package listings
 
 import (
+	"encoding/json"
 	"slices"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
+	"unicode"
 
 	"github.com/google/uuid"
 )
 
 type Listing struct {
-	UUID        string    `json:"UUID"`
-	UserID      string    `json:"userId"`
-	Context     any       `json:"@context"`
-	Type        []string  `json:"@type"`
-	Name        string    `json:"name"`
-	URL         string    `json:"url"`
-	Description string    `json:"description"`
-	Address     Address   `json:"address"`
-	Offers      Offers    `json:"offers"`
-	Image       string    `json:"image"`
-	Photo       []Photo   `json:"photo"`
-	IsNew       bool      `json:"isNew"`
-	CreatedAt   time.Time `json:"createdAt"`
+	UUID           string    `json:"UUID"`
+	UserID         string    `json:"userId"`
+	QueryName      string    `json:"queryName"`
+	QueryVersion   int       `json:"queryVersion"`
+	ObjectID       string    `json:"objectId"`
+	Context        any       `json:"@context"`
+	Type           []string  `json:"@type"`
+	Name           string    `json:"name"`
+	URL            string    `json:"url"`
+	Description    string    `json:"description"`
+	Address        Address   `json:"address"`
+	Agent          string    `json:"-"`
+	LivingArea     int       `json:"-"`
+	Rooms          int       `json:"-"`
+	EnergyLabel    string    `json:"-"`
+	Geo            *Geo      `json:"geo"`
+	Latitude       float64   `json:"-"`
+	Longitude      float64   `json:"-"`
+	Offers         Offers    `json:"offers"`
+	Image          string    `json:"image"`
+	Photo          []Photo   `json:"photo"`
+	PhotosRaw      string    `json:"-"`
+	IsNew          bool      `json:"isNew"`
+	MissedSyncs    int       `json:"-"`
+	Status         string    `json:"-"`
+	Note           string    `json:"note"`
+	Rank           int       `json:"rank"`
+	IsGone         bool      `json:"isGone"`
+	CheckedAt      time.Time `json:"checkedAt"`
+	Stage          string    `json:"stage"`
+	StageChangedAt time.Time `json:"stageChangedAt"`
+	CreatedAt      time.Time `json:"createdAt"`
 }
 
 type Offers struct {
@@ -36,6 +58,58 @@ type Address struct {
 	StreetAddress   string `json:"streetAddress"`
 	AddressLocality string `json:"addressLocality"`
 	AddressRegion   string `json:"addressRegion"`
+	PostalCode      string `json:"postalCode"`
+}
+
+// Geo holds coordinates of a listing page, schema.org allows both numbers and strings for them.
+type Geo struct {
+	Type      string      `json:"@type"`
+	Latitude  json.Number `json:"latitude"`
+	Longitude json.Number `json:"longitude"`
+}
+
+// UnmarshalJSON leaves out coordinates that are neither numbers nor numeric strings instead of failing, so that a
+// malformed location drops only the location rather than the whole listing.
+func (g *Geo) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Type      string          `json:"@type"`
+		Latitude  json.RawMessage `json:"latitude"`
+		Longitude json.RawMessage `json:"longitude"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		*g = Geo{}
+		return nil
+	}
+
+	*g = Geo{Type: raw.Type, Latitude: parseCoordinate(raw.Latitude), Longitude: parseCoordinate(raw.Longitude)}
+	return nil
+}
+
+// parseCoordinate is empty when the value is not a number or a string holding one.
+func parseCoordinate(data json.RawMessage) json.Number {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		value = string(data)
+	}
+	value = strings.TrimSpace(value)
+	if _, err := strconv.ParseFloat(value, 64); err != nil {
+		return ""
+	}
+	return json.Number(value)
+}
+
+// HasLocation is false for listings stored before coordinates were parsed or whose page did not give them.
+func (l *Listing) HasLocation() bool {
+	return l.Latitude != 0 || l.Longitude != 0
+}
+
+// EnergyLabels are ordered from the worst to the best.
+var EnergyLabels = []string{"G", "F", "E", "D", "C", "B", "A", "A+", "A++", "A+++", "A++++"}
+
+// EnergyRank is the index of the energy label in EnergyLabels, it is false when the label is missing or unknown.
+func (l *Listing) EnergyRank() (int, bool) {
+	idx := slices.Index(EnergyLabels, l.EnergyLabel)
+	return idx, idx != -1
 }
 
 type Photo struct {
@@ -43,16 +117,40 @@ type Photo struct {
 	ContentURL string `json:"contentUrl"`
 }
 
+// photosSeparator joins photo URLs in PhotosRaw, URLs may contain commas but never line breaks
+const photosSeparator = "\n"
+
+// SetPhotos stores the gallery found in the listing page, falling back to its main image.
+func (l *Listing) SetPhotos() {
+	urls := make([]string, 0, len(l.Photo))
+	for idx := range l.Photo {
+		if l.Photo[idx].ContentURL != "" {
+			urls = append(urls, l.Photo[idx].ContentURL)
+		}
+	}
+	if len(urls) == 0 && l.Image != "" {
+		urls = append(urls, l.Image)
+	}
+	l.PhotosRaw = strings.Join(urls, photosSeparator)
+}
+
+func (l *Listing) PhotoURLs() []string {
+	if l.PhotosRaw == "" {
+		return nil
+	}
+	return strings.Split(l.PhotosRaw, photosSeparator)
+}
+
 type Listings []Listing
 
-func (l *Listings) MapByURL() map[string]Listing {
+func (l *Listings) MapByObjectID() map[string]Listing {
 	if l == nil || len(*l) == 0 {
 		return nil
 	}
 
 	result := make(map[string]Listing)
 	for idx := range *l {
-		result[(*l)[idx].URL] = (*l)[idx]
+		result[(*l)[idx].ObjectID] = (*l)[idx]
 	}
 	return result
 }
@@ -63,18 +161,43 @@ func (l *Listings) CompareAndGetRemovedListings(newListings Listings) (removedLi
 	}
 	removedListings = make(Listings, 0, len(*l))
 	leftoverListings = make(Listings, 0, len(*l))
-	currentMap := l.MapByURL()
-	newMap := newListings.MapByURL()
-	for url := range currentMap {
-		if _, ok := newMap[url]; !ok {
-			removedListings = append(removedListings, currentMap[url])
+	currentMap := l.MapByObjectID()
+	newMap := newListings.MapByObjectID()
+	for objectID := range currentMap {
+		if _, ok := newMap[objectID]; !ok {
+			removedListings = append(removedListings, currentMap[objectID])
 		} else {
-			leftoverListings = append(leftoverListings, currentMap[url])
+			// keep stored attributes, but take freshly listed URL in case its slug has changed, as well as attributes
+			// which were not parsed when the listing was stored
+			leftoverListing := currentMap[objectID]
+			leftoverListing.URL = newMap[objectID].URL
+			leftoverListing.Address.PostalCode = newMap[objectID].Address.PostalCode
+			leftoverListing.Agent = newMap[objectID].Agent
+			leftoverListing.LivingArea = newMap[objectID].LivingArea
+			leftoverListing.PhotosRaw = newMap[objectID].PhotosRaw
+			leftoverListing.Rooms = newMap[objectID].Rooms
+			leftoverListing.EnergyLabel = newMap[objectID].EnergyLabel
+			leftoverListing.Latitude = newMap[objectID].Latitude
+			leftoverListing.Longitude = newMap[objectID].Longitude
+			leftoverListings = append(leftoverListings, leftoverListing)
 		}
 	}
 	return removedListings, leftoverListings
 }
 
+// SplitByMissedSyncs tells listings missing from search results for maxMissedSyncs syncs in a row, including the current
+// one, apart from the ones which are missing for fewer syncs.
+func (l Listings) SplitByMissedSyncs(maxMissedSyncs int) (stale, missed Listings) {
+	for idx := range l {
+		if l[idx].MissedSyncs+1 >= maxMissedSyncs {
+			stale = append(stale, l[idx])
+		} else {
+			missed = append(missed, l[idx])
+		}
+	}
+	return stale, missed
+}
+
 func (l *Listings) FilterByRegionsAndCities(regions, cities []string) Listings {
 	if l == nil || len(*l) == 0 {
 		return nil
@@ -118,16 +241,99 @@ func (l *Listings) CompareAndGetAddedListings(currentListings Listings) Listings
 		return nil
 	}
 	addedListings := make(Listings, 0, len(*l))
-	currentMap := currentListings.MapByURL()
-	newMap := l.MapByURL()
-	for url := range newMap {
-		if _, ok := currentMap[url]; !ok {
-			addedListings = append(addedListings, newMap[url])
+	currentMap := currentListings.MapByObjectID()
+	newMap := l.MapByObjectID()
+	for objectID := range newMap {
+		if _, ok := currentMap[objectID]; !ok {
+			addedListings = append(addedListings, newMap[objectID])
 		}
 	}
 	return addedListings
 }
 
+// PricePoint is the price of a listing recorded when it is first seen and whenever it changes.
+type PricePoint struct {
+	UserID     string
+	ObjectID   string
+	Currency   string
+	Price      float64
+	RecordedAt time.Time
+}
+
+func NewPricePoint(listing *Listing, recordedAt time.Time) PricePoint {
+	return PricePoint{
+		UserID:     listing.UserID,
+		ObjectID:   listing.ObjectID,
+		Currency:   listing.Offers.PriceCurrency,
+		Price:      listing.Offers.Price,
+		RecordedAt: recordedAt,
+	}
+}
+
+type PricePoints []PricePoint
+
+// Pipeline stages of a favorite listing, in the order a home is usually pursued.
+const (
+	StageInterested = "interested"
+	StageContacted  = "contacted"
+	StageViewing    = "viewing"
+	StageApplied    = "applied"
+	StageRejected   = "rejected"
+	StageWon        = "won"
+)
+
+var Stages = []string{StageInterested, StageContacted, StageViewing, StageApplied, StageRejected, StageWon}
+
+func IsValidStage(stage string) bool {
+	return slices.Contains(Stages, stage)
+}
+
+// StageEvent records when a favorite listing entered a pipeline stage.
+type StageEvent struct {
+	UserID    string
+	ObjectID  string
+	Stage     string
+	CreatedAt time.Time
+}
+
+type StageEvents []StageEvent
+
+// Kinds of tracked listings which are re-checked for changes.
+const (
+	KindFavorite = "favorite"
+	KindWatched  = "watched"
+)
+
+// Change describes what happened to a tracked listing, either a favorite or a watched one, since the previous check,
+// Listing holds the current state. Changes from an unknown status are not reported since listings tracked before
+// monitoring have no status stored.
+type Change struct {
+	Kind      string
+	Listing   Listing
+	OldPrice  float64
+	OldStatus string
+}
+
+func (c *Change) IsPriceChanged() bool {
+	return !c.Listing.IsGone && c.OldPrice != c.Listing.Offers.Price
+}
+
+func (c *Change) IsStatusChanged() bool {
+	return !c.Listing.IsGone && c.OldStatus != "" && c.OldStatus != c.Listing.Status
+}
+
+type Changes []Change
+
+// SortByRank orders favorites by their personal rank.
+func (l *Listings) SortByRank() {
+	if l == nil || len(*l) == 0 {
+		return
+	}
+	sort.SliceStable(*l, func(i, j int) bool {
+		return (*l)[i].Rank < (*l)[j].Rank
+	})
+}
+
 func (l *Listings) Sort() {
 	if l == nil || len(*l) == 0 {
 		return
@@ -144,15 +350,51 @@ func (l *Listings) Sort() {
 	})
 }
 
-func (l *Listings) URLs() []string {
+// SortOldestFirst orders listings by the time they were first seen, the oldest first.
+func (l *Listings) SortOldestFirst() {
+	if l == nil || len(*l) == 0 {
+		return
+	}
+	sort.SliceStable(*l, func(i, j int) bool {
+		return (*l)[i].CreatedAt.Before((*l)[j].CreatedAt)
+	})
+}
+
+// SortByPrice orders listings from the cheapest, the newest go first among equally priced ones.
+func (l *Listings) SortByPrice() {
+	if l == nil || len(*l) == 0 {
+		return
+	}
+	sort.SliceStable(*l, func(i, j int) bool {
+		if (*l)[i].Offers.Price != (*l)[j].Offers.Price {
+			return (*l)[i].Offers.Price < (*l)[j].Offers.Price
+		}
+		return (*l)[i].CreatedAt.After((*l)[j].CreatedAt)
+	})
+}
+
+// SortByLivingArea orders listings from the largest, listings with unknown area go last.
+func (l *Listings) SortByLivingArea() {
+	if l == nil || len(*l) == 0 {
+		return
+	}
+	sort.SliceStable(*l, func(i, j int) bool {
+		if (*l)[i].LivingArea != (*l)[j].LivingArea {
+			return (*l)[i].LivingArea > (*l)[j].LivingArea
+		}
+		return (*l)[i].CreatedAt.After((*l)[j].CreatedAt)
+	})
+}
+
+func (l *Listings) ObjectIDs() []string {
 	if l == nil || len(*l) == 0 {
 		return nil
 	}
-	urls := make([]string, 0, len(*l))
+	objectIDs := make([]string, 0, len(*l))
 	for idx := range *l {
-		urls = append(urls, (*l)[idx].URL)
+		objectIDs = append(objectIDs, (*l)[idx].ObjectID)
 	}
-	return urls
+	return objectIDs
 }
 
 func (l *Listings) SetUserID(userID string) {
@@ -164,6 +406,41 @@ func (l *Listings) SetUserID(userID string) {
 	}
 }
 
+func (l *Listings) SetQueryName(queryName string) {
+	if l == nil || len(*l) == 0 {
+		return
+	}
+	for idx := range *l {
+		(*l)[idx].QueryName = queryName
+	}
+}
+
+// UniqueByObjectID drops listings found by more than one search query, keeping the first occurrence.
+func (l *Listings) UniqueByObjectID() Listings {
+	if l == nil || len(*l) == 0 {
+		return nil
+	}
+	encountered := make(map[string]bool)
+	result := make(Listings, 0, len(*l))
+	for idx := range *l {
+		if encountered[(*l)[idx].ObjectID] {
+			continue
+		}
+		encountered[(*l)[idx].ObjectID] = true
+		result = append(result, (*l)[idx])
+	}
+	return result
+}
+
+func (l *Listings) SetQueryVersion(queryVersion int) {
+	if l == nil || len(*l) == 0 {
+		return
+	}
+	for idx := range *l {
+		(*l)[idx].QueryVersion = queryVersion
+	}
+}
+
 func (l *Listings) GenerateUUIDs() {
 	if l == nil || len(*l) == 0 {
 		return
@@ -184,5 +461,82 @@ type ListingSearchList struct {
 	Type            []string      `json:"@type"`
 	Name            string        `json:"name"`
 	URL             string        `json:"url"`
+	NumberOfItems   int           `json:"numberOfItems"`
 	ItemListElement []ListingItem `json:"itemListElement"`
 }
+
+// SearchResult is the outcome of walking through search result pages.
+type SearchResult struct {
+	Items      []ListingItem
+	PagesCount int
+	// TotalCount is the number of matches reported by Funda, or the number of items found if it is not reported
+	TotalCount int
+	// IsTruncated is set when pagination was stopped by a cap before all matches were seen
+	IsTruncated bool
+}
+
+// snippets of full-text search matches wrap matched terms into control characters which never occur in listings, so
+// that they can be told apart from the text after it is escaped for display
+const (
+	SnippetOpen     = "\x02"
+	SnippetClose    = "\x03"
+	SnippetEllipsis = "…"
+	// SnippetTokens is the maximum number of tokens in a snippet
+	SnippetTokens = 16
+)
+
+// Match is a stored listing found by a full-text search along with an excerpt around the matched terms.
+type Match struct {
+	Listing Listing
+	Snippet string
+}
+
+// Matches are ordered by relevance, the best one first.
+type Matches []Match
+
+func (m *Matches) Listings() Listings {
+	result := make(Listings, 0, len(*m))
+	for idx := range *m {
+		result = append(result, (*m)[idx].Listing)
+	}
+	return result
+}
+
+// MatchQuery turns free-form search terms into an FTS5 query where every term must match as a prefix, terms are quoted
+// so that FTS5 operators typed by a user are searched for as words. It returns an empty string when no terms are left.
+func MatchQuery(terms string) string {
+	fields := strings.FieldsFunc(terms, func(r rune) bool {
+		return unicode.IsSpace(r) || r == '"'
+	})
+	for idx := range fields {
+		fields[idx] = `"` + fields[idx] + `"*`
+	}
+	return strings.Join(fields, " ")
+}
+
+// Update is the outcome of a single search query sync.
+type Update struct {
+	Added        Listings
+	Removed      Listings
+	Leftover     Listings
+	TotalCount   int
+	TrackedCount int
+	IsTruncated  bool
+}
+
+type Preview struct {
+	TotalCount            int
+	TrackedCount          int
+	IsTruncated           bool
+	PagesCount            int
+	Items                 []ListingItem
+	PaginationDuration    time.Duration
+	EstimatedSyncDuration time.Duration
+}
+
+// EstimateSyncDuration approximates a full sync: pagination is repeated, then details are requested one by one
+// with fundaAPIQueryInterval in between, each request taking roughly as long as a search page request.
+func (p *Preview) EstimateSyncDuration() {
+	requestDuration := p.PaginationDuration / time.Duration(p.PagesCount+1)
+	p.EstimatedSyncDuration = p.PaginationDuration + time.Duration(p.TrackedCount)*fundaAPIQueryInterval + requestDuration
+}
@@ -1,7 +1,23 @@
 package listings
 
-import "context"
+import (
+	"context"
+	"errors"
+	"net/http"
+)
 
 type FundaAPIClient interface {
 	GetHTMLContent(ctx context.Context, URL string) ([]byte, error)
 }
+
+// IsGone tells whether an error of fetching a listing means that it was taken off Funda, i.e. it responds with 404/410.
+// Redirects are not followed and are treated as transient errors like any other response, since a redirect does not
+// prove that a listing was removed.
+func IsGone(err error) bool {
+	var responseErr interface{ StatusCode() int }
+	if !errors.As(err, &responseErr) {
+		return false
+	}
+	code := responseErr.StatusCode()
+	return code == http.StatusNotFound || code == http.StatusGone
+}
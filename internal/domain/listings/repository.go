@@ -3,20 +3,43 @@ package listings
 import (
 	"context"
 	"fundaNotifier/internal/domain"
+	"time"
 )
 
 type Repository interface {
 	Begin(ctx context.Context) (domain.Tx, error)
 	MDeleteListingByUserIDTx(ctx context.Context, tx domain.Tx, userID string) error
 	MGetListingByUserID(ctx context.Context, userID string, showOnlyNew bool) (Listings, error)
-	MGetListingByUserIDTx(ctx context.Context, tx domain.Tx, userID string) (Listings, error)
+	MGetListingByUserIDAndQueryNameTx(ctx context.Context, tx domain.Tx, userID, queryName string) (Listings, error)
 	GetListingByUUID(ctx context.Context, UUID string) (*Listing, error)
+	GetListingByUserIDAndObjectID(ctx context.Context, userID, objectID string) (*Listing, error)
+	SearchListingByUserID(ctx context.Context, userID, match string) (Matches, error)
+	CountListingByUserIDAndQueryNameGroupByQueryVersion(ctx context.Context, userID, queryName string) (map[int]int, error)
 	MInsertListingTx(ctx context.Context, tx domain.Tx, listings Listings) error
 	MUpdateListingTx(ctx context.Context, tx domain.Tx, listings Listings) error
-	MDeleteListingByUserIDAndURLsTx(ctx context.Context, tx domain.Tx, userID string, URLs []string) error
+	MDeleteListingByUserIDAndQueryNameTx(ctx context.Context, tx domain.Tx, userID, queryName string) error
+	MDeleteListingByUserIDAndQueryNameAndObjectIDsTx(ctx context.Context, tx domain.Tx, userID, queryName string, objectIDs []string) error
+	MIncrementListingMissedSyncsByUserIDAndQueryNameAndObjectIDsTx(ctx context.Context, tx domain.Tx, userID, queryName string, objectIDs []string) error
 	InsertFavoriteListingTx(ctx context.Context, tx domain.Tx, listing *Listing) error
 	UpdateFavoriteListingTx(ctx context.Context, tx domain.Tx, listing *Listing) error
+	UpdateFavoriteListingStatus(ctx context.Context, listing *Listing) error
 	MGetFavoriteListingByUserIDTx(ctx context.Context, tx domain.Tx, userID string) (Listings, error)
 	MGetFavoriteListingByUserID(ctx context.Context, userID string) (Listings, error)
+	GetFavoriteListingByUserIDAndObjectID(ctx context.Context, userID, objectID string) (*Listing, error)
+	UpdateFavoriteListingNoteByUserIDAndObjectID(ctx context.Context, userID, objectID, note string) error
+	MUpdateFavoriteListingRankTx(ctx context.Context, tx domain.Tx, listings Listings) error
+	UpdateFavoriteListingStageTx(ctx context.Context, tx domain.Tx, listing *Listing) error
+	DeleteFavoriteListingByUserIDAndObjectIDTx(ctx context.Context, tx domain.Tx, userID, objectID string) error
 	MDeleteFavoriteListingByUserIDTx(ctx context.Context, tx domain.Tx, userID string) error
+	MInsertPricePointTx(ctx context.Context, tx domain.Tx, points PricePoints) error
+	InsertPricePoint(ctx context.Context, point *PricePoint) error
+	MGetPricePointByUserIDAndObjectID(ctx context.Context, userID, objectID string) (PricePoints, error)
+	MDeletePricePointByUserIDTx(ctx context.Context, tx domain.Tx, userID string) error
+	InsertStageEventTx(ctx context.Context, tx domain.Tx, event *StageEvent) error
+	MGetStageEventByUserIDAndObjectID(ctx context.Context, userID, objectID string) (StageEvents, error)
+	DeleteStageEventByUserIDAndObjectIDTx(ctx context.Context, tx domain.Tx, userID, objectID string) error
+	MDeleteStageEventByUserIDTx(ctx context.Context, tx domain.Tx, userID string) error
+	MInsertSeenListingTx(ctx context.Context, tx domain.Tx, userID string, objectIDs []string, seenAt time.Time) error
+	MGetSeenObjectIDByUserID(ctx context.Context, userID string) ([]string, error)
+	MDeleteSeenListingByUserIDTx(ctx context.Context, tx domain.Tx, userID string) error
 }
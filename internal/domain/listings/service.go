@@ -8,7 +8,10 @@ import (
 	"errors"
 	"fmt"
 	"fundaNotifier/internal/domain"
+	"fundaNotifier/internal/pkg/funda"
 	"net/url"
+	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -22,21 +25,39 @@ const (
 	fundaAPIQueryInterval = time.Millisecond * 500
 )
 
+var (
+	postalCodeRegexp  = regexp.MustCompile(`\b(\d{4})\s?([A-Z]{2})\b`)
+	livingAreaRegexp  = regexp.MustCompile(`(\d[\d.]*)\s*m²`)
+	roomsRegexp       = regexp.MustCompile(`(\d+)\s*kamers?`)
+	energyLabelRegexp = regexp.MustCompile(`^([A-G]\+*)`)
+)
+
+// ViewingsService cancels the viewing of a favorite along with the favorite itself.
+type ViewingsService interface {
+	DeleteViewingByUserIDAndObjectIDTx(ctx context.Context, tx domain.Tx, userID, objectID string) error
+}
+
 type Service struct {
-	repository     Repository
-	fundaAPIClient FundaAPIClient
-	log            *zerolog.Logger
+	repository      Repository
+	fundaAPIClient  FundaAPIClient
+	viewingsService ViewingsService
+	cfg             *Config
+	log             *zerolog.Logger
 }
 
 func NewService(
 	repository Repository,
 	fundaAPIClient FundaAPIClient,
+	viewingsService ViewingsService,
+	cfg *Config,
 	log *zerolog.Logger,
 ) *Service {
 	return &Service{
-		repository:     repository,
-		fundaAPIClient: fundaAPIClient,
-		log:            log,
+		repository:      repository,
+		fundaAPIClient:  fundaAPIClient,
+		viewingsService: viewingsService,
+		cfg:             cfg,
+		log:             log,
 	}
 }
 
@@ -50,10 +71,10 @@ func (s *Service) MDeleteListingByUserIDTx(ctx context.Context, tx domain.Tx, us
 	return nil
 }
 
-func (s *Service) MDeleteListingByUserIDAndURLsTx(ctx context.Context, tx domain.Tx, userID string, URLs []string) error {
-	err := s.repository.MDeleteListingByUserIDAndURLsTx(ctx, tx, userID, URLs)
+func (s *Service) MDeleteListingByUserIDAndQueryNameTx(ctx context.Context, tx domain.Tx, userID, queryName string) error {
+	err := s.repository.MDeleteListingByUserIDAndQueryNameTx(ctx, tx, userID, queryName)
 	if err != nil {
-		s.log.Error().Err(err).Str("userID", userID).Msg("failed to delete listings")
+		s.log.Error().Err(err).Str("userID", userID).Str("queryName", queryName).Msg("failed to delete listings")
 		return fmt.Errorf("failed to delete listings: %w", err)
 	}
 
@@ -70,18 +91,19 @@ func (s *Service) MGetListingByUserID(ctx context.Context, userID string, showOn
 	return listings, nil
 }
 
-func (s *Service) MGetListingByUserIDTx(ctx context.Context, tx domain.Tx, userID string) (Listings, error) {
-	listings, err := s.repository.MGetListingByUserIDTx(ctx, tx, userID)
+func (s *Service) GetListingByUUID(ctx context.Context, UUID string) (*Listing, error) {
+	listing, err := s.repository.GetListingByUUID(ctx, UUID)
 	if err != nil {
-		s.log.Error().Err(err).Msg("failed to get listings")
-		return nil, fmt.Errorf("failed to get listings: %w", err)
+		s.log.Error().Err(err).Msg("failed to get listing")
+		return nil, fmt.Errorf("failed to get listing: %w", err)
 	}
 
-	return listings, nil
+	return listing, nil
 }
 
-func (s *Service) GetListingByUUID(ctx context.Context, UUID string) (*Listing, error) {
-	listing, err := s.repository.GetListingByUUID(ctx, UUID)
+// GetListingByObjectID returns the earliest stored copy of a listing, it is found by as many queries as match it.
+func (s *Service) GetListingByObjectID(ctx context.Context, userID, objectID string) (*Listing, error) {
+	listing, err := s.repository.GetListingByUserIDAndObjectID(ctx, userID, objectID)
 	if err != nil {
 		s.log.Error().Err(err).Msg("failed to get listing")
 		return nil, fmt.Errorf("failed to get listing: %w", err)
@@ -90,6 +112,27 @@ func (s *Service) GetListingByUUID(ctx context.Context, UUID string) (*Listing,
 	return listing, nil
 }
 
+// SearchListings finds stored listings of a user by name, description and address, see MatchQuery for the syntax.
+func (s *Service) SearchListings(ctx context.Context, userID, terms string) (Matches, error) {
+	matches, err := s.repository.SearchListingByUserID(ctx, userID, MatchQuery(terms))
+	if err != nil {
+		s.log.Error().Err(err).Msg("failed to search listings")
+		return nil, fmt.Errorf("failed to search listings: %w", err)
+	}
+
+	return matches, nil
+}
+
+func (s *Service) CountListingByQueryVersion(ctx context.Context, userID, queryName string) (map[int]int, error) {
+	counts, err := s.repository.CountListingByUserIDAndQueryNameGroupByQueryVersion(ctx, userID, queryName)
+	if err != nil {
+		s.log.Error().Err(err).Str("userID", userID).Str("queryName", queryName).Msg("failed to count listings by query version")
+		return nil, fmt.Errorf("failed to count listings by query version: %w", err)
+	}
+
+	return counts, nil
+}
+
 func (s *Service) MGetFavoriteListingByUserID(ctx context.Context, userID string) (Listings, error) {
 	listings, err := s.repository.MGetFavoriteListingByUserID(ctx, userID)
 	if err != nil {
@@ -120,13 +163,26 @@ func (s *Service) AddFavoriteListing(ctx context.Context, listing *Listing) erro
 		return fmt.Errorf("failed to get favorite listings: %w", err)
 	}
 
-	favoriteListingsMap := favoriteListings.MapByURL()
-	if _, ok := favoriteListingsMap[listing.URL]; !ok {
+	favoriteListingsMap := favoriteListings.MapByObjectID()
+	if _, ok := favoriteListingsMap[listing.ObjectID]; !ok {
+		// new favorites go to the bottom of the personal ranking
+		listing.Rank = 1
+		for idx := range favoriteListings {
+			listing.Rank = max(listing.Rank, favoriteListings[idx].Rank+1)
+		}
+		listing.Stage, listing.StageChangedAt = StageInterested, time.Now().UTC()
 		err = s.repository.InsertFavoriteListingTx(ctx, tx, listing)
 		if err != nil {
 			s.log.Error().Err(err).Msg("failed to insert favorite listing")
 			return fmt.Errorf("failed to insert favorite listing: %w", err)
 		}
+
+		event := StageEvent{UserID: listing.UserID, ObjectID: listing.ObjectID, Stage: listing.Stage, CreatedAt: listing.StageChangedAt}
+		err = s.repository.InsertStageEventTx(ctx, tx, &event)
+		if err != nil {
+			s.log.Error().Err(err).Msg("failed to insert pipeline event")
+			return fmt.Errorf("failed to insert pipeline event: %w", err)
+		}
 	} else {
 		err = s.repository.UpdateFavoriteListingTx(ctx, tx, listing)
 		if err != nil {
@@ -143,6 +199,318 @@ func (s *Service) AddFavoriteListing(ctx context.Context, listing *Listing) erro
 	return nil
 }
 
+func (s *Service) GetFavoriteListing(ctx context.Context, userID, objectID string) (*Listing, error) {
+	listing, err := s.repository.GetFavoriteListingByUserIDAndObjectID(ctx, userID, objectID)
+	if err != nil {
+		s.log.Error().Err(err).Str("userID", userID).Str("objectID", objectID).Msg("failed to get favorite listing")
+		return nil, fmt.Errorf("failed to get favorite listing: %w", err)
+	}
+
+	return listing, nil
+}
+
+func (s *Service) RemoveFavoriteListing(ctx context.Context, userID, objectID string) error {
+	tx, err := s.repository.Begin(ctx)
+	if err != nil {
+		s.log.Error().Err(err).Msg("failed to begin a transaction")
+		return fmt.Errorf("failed to begin a transaction: %w", err)
+	}
+
+	defer func(tx domain.Tx) {
+		errRb := tx.Rollback()
+		if errRb != nil && !errors.Is(errRb, sql.ErrTxDone) {
+			s.log.Error().Err(errRb).Msg("failed to rollback a transaction")
+		}
+	}(tx)
+
+	err = s.repository.DeleteFavoriteListingByUserIDAndObjectIDTx(ctx, tx, userID, objectID)
+	if err != nil {
+		s.log.Error().Err(err).Str("userID", userID).Str("objectID", objectID).Msg("failed to delete favorite listing")
+		return fmt.Errorf("failed to delete favorite listing: %w", err)
+	}
+
+	err = s.repository.DeleteStageEventByUserIDAndObjectIDTx(ctx, tx, userID, objectID)
+	if err != nil {
+		s.log.Error().Err(err).Str("userID", userID).Str("objectID", objectID).Msg("failed to delete pipeline events")
+		return fmt.Errorf("failed to delete pipeline events: %w", err)
+	}
+
+	err = s.viewingsService.DeleteViewingByUserIDAndObjectIDTx(ctx, tx, userID, objectID)
+	if err != nil {
+		s.log.Error().Err(err).Str("userID", userID).Str("objectID", objectID).Msg("failed to cancel viewing")
+		return fmt.Errorf("failed to cancel viewing: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		s.log.Error().Err(err).Msg("failed to commit a transaction")
+		return fmt.Errorf("failed to commit a transaction: %w", err)
+	}
+
+	return nil
+}
+
+// SetFavoriteListingStage moves a favorite to a pipeline stage and records when it happened, setting the current stage
+// again changes nothing.
+func (s *Service) SetFavoriteListingStage(ctx context.Context, userID, objectID, stage string) (*Listing, error) {
+	if !IsValidStage(stage) {
+		return nil, fmt.Errorf("unknown pipeline stage %q", stage)
+	}
+
+	favorite, err := s.GetFavoriteListing(ctx, userID, objectID)
+	if err != nil {
+		return nil, err
+	}
+	if favorite.Stage == stage {
+		return favorite, nil
+	}
+
+	tx, err := s.repository.Begin(ctx)
+	if err != nil {
+		s.log.Error().Err(err).Msg("failed to begin a transaction")
+		return nil, fmt.Errorf("failed to begin a transaction: %w", err)
+	}
+
+	defer func(tx domain.Tx) {
+		errRb := tx.Rollback()
+		if errRb != nil && !errors.Is(errRb, sql.ErrTxDone) {
+			s.log.Error().Err(errRb).Msg("failed to rollback a transaction")
+		}
+	}(tx)
+
+	favorite.Stage, favorite.StageChangedAt = stage, time.Now().UTC()
+	err = s.repository.UpdateFavoriteListingStageTx(ctx, tx, favorite)
+	if err != nil {
+		s.log.Error().Err(err).Str("userID", userID).Str("objectID", objectID).Msg("failed to update favorite listing stage")
+		return nil, fmt.Errorf("failed to update favorite listing stage: %w", err)
+	}
+
+	event := StageEvent{UserID: userID, ObjectID: objectID, Stage: stage, CreatedAt: favorite.StageChangedAt}
+	err = s.repository.InsertStageEventTx(ctx, tx, &event)
+	if err != nil {
+		s.log.Error().Err(err).Str("userID", userID).Str("objectID", objectID).Msg("failed to insert pipeline event")
+		return nil, fmt.Errorf("failed to insert pipeline event: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		s.log.Error().Err(err).Msg("failed to commit a transaction")
+		return nil, fmt.Errorf("failed to commit a transaction: %w", err)
+	}
+
+	return favorite, nil
+}
+
+func (s *Service) MGetStageEvent(ctx context.Context, userID, objectID string) (StageEvents, error) {
+	events, err := s.repository.MGetStageEventByUserIDAndObjectID(ctx, userID, objectID)
+	if err != nil {
+		s.log.Error().Err(err).Str("userID", userID).Str("objectID", objectID).Msg("failed to get pipeline events")
+		return nil, fmt.Errorf("failed to get pipeline events: %w", err)
+	}
+
+	return events, nil
+}
+
+func (s *Service) MDeleteStageEventByUserIDTx(ctx context.Context, tx domain.Tx, userID string) error {
+	err := s.repository.MDeleteStageEventByUserIDTx(ctx, tx, userID)
+	if err != nil {
+		s.log.Error().Err(err).Str("userID", userID).Msg("failed to delete pipeline events")
+		return fmt.Errorf("failed to delete pipeline events: %w", err)
+	}
+
+	return nil
+}
+
+// MarkListingsSeen remembers listings as seen by their object IDs, so that they stay seen when a query is changed.
+func (s *Service) MarkListingsSeen(ctx context.Context, userID string, objectIDs []string) error {
+	tx, err := s.repository.Begin(ctx)
+	if err != nil {
+		s.log.Error().Err(err).Msg("failed to begin a transaction")
+		return fmt.Errorf("failed to begin a transaction: %w", err)
+	}
+
+	defer func(tx domain.Tx) {
+		errRb := tx.Rollback()
+		if errRb != nil && !errors.Is(errRb, sql.ErrTxDone) {
+			s.log.Error().Err(errRb).Msg("failed to rollback a transaction")
+		}
+	}(tx)
+
+	err = s.repository.MInsertSeenListingTx(ctx, tx, userID, objectIDs, time.Now().UTC())
+	if err != nil {
+		s.log.Error().Err(err).Str("userID", userID).Msg("failed to mark listings as seen")
+		return fmt.Errorf("failed to mark listings as seen: %w", err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		s.log.Error().Err(err).Msg("failed to commit a transaction")
+		return fmt.Errorf("failed to commit a transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Service) MGetSeenObjectID(ctx context.Context, userID string) ([]string, error) {
+	objectIDs, err := s.repository.MGetSeenObjectIDByUserID(ctx, userID)
+	if err != nil {
+		s.log.Error().Err(err).Str("userID", userID).Msg("failed to get seen listings")
+		return nil, fmt.Errorf("failed to get seen listings: %w", err)
+	}
+
+	return objectIDs, nil
+}
+
+func (s *Service) MDeleteSeenListingByUserIDTx(ctx context.Context, tx domain.Tx, userID string) error {
+	err := s.repository.MDeleteSeenListingByUserIDTx(ctx, tx, userID)
+	if err != nil {
+		s.log.Error().Err(err).Str("userID", userID).Msg("failed to delete seen listings")
+		return fmt.Errorf("failed to delete seen listings: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Service) SetFavoriteListingNote(ctx context.Context, userID, objectID, note string) error {
+	err := s.repository.UpdateFavoriteListingNoteByUserIDAndObjectID(ctx, userID, objectID, note)
+	if err != nil {
+		s.log.Error().Err(err).Str("userID", userID).Str("objectID", objectID).Msg("failed to update favorite listing note")
+		return fmt.Errorf("failed to update favorite listing note: %w", err)
+	}
+
+	return nil
+}
+
+// SetFavoriteListingRank moves a favorite to the given position of the personal ranking and renumbers the rest,
+// positions out of range are clamped.
+func (s *Service) SetFavoriteListingRank(ctx context.Context, userID, objectID string, rank int) error {
+	tx, err := s.repository.Begin(ctx)
+	if err != nil {
+		s.log.Error().Err(err).Msg("failed to begin a transaction")
+		return fmt.Errorf("failed to begin a transaction: %w", err)
+	}
+
+	defer func(tx domain.Tx) {
+		errRb := tx.Rollback()
+		if errRb != nil && !errors.Is(errRb, sql.ErrTxDone) {
+			s.log.Error().Err(errRb).Msg("failed to rollback a transaction")
+		}
+	}(tx)
+
+	favoriteListings, err := s.repository.MGetFavoriteListingByUserIDTx(ctx, tx, userID)
+	if err != nil {
+		s.log.Error().Err(err).Msg("failed to get favorite listings")
+		return fmt.Errorf("failed to get favorite listings: %w", err)
+	}
+	favoriteListings.SortByRank()
+
+	idx := slices.IndexFunc(favoriteListings, func(listing Listing) bool { return listing.ObjectID == objectID })
+	if idx < 0 {
+		return fmt.Errorf("failed to find favorite listing %s: %w", objectID, sql.ErrNoRows)
+	}
+	listing := favoriteListings[idx]
+	favoriteListings = slices.Delete(favoriteListings, idx, idx+1)
+	rank = min(max(rank, 1), len(favoriteListings)+1)
+	favoriteListings = slices.Insert(favoriteListings, rank-1, listing)
+	for idx = range favoriteListings {
+		favoriteListings[idx].Rank = idx + 1
+	}
+
+	if err = s.repository.MUpdateFavoriteListingRankTx(ctx, tx, favoriteListings); err != nil {
+		s.log.Error().Err(err).Msg("failed to update favorite listings rank")
+		return fmt.Errorf("failed to update favorite listings rank: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		s.log.Error().Err(err).Msg("failed to commit a transaction")
+		return fmt.Errorf("failed to commit a transaction: %w", err)
+	}
+
+	return nil
+}
+
+// CheckFavoriteListings re-fetches favorites which were not checked within the configured interval and returns what
+// has changed. Favorites which fail to load for reasons other than being taken off Funda are skipped until the next check.
+func (s *Service) CheckFavoriteListings(ctx context.Context, userID string) (Changes, error) {
+	favoriteListings, err := s.repository.MGetFavoriteListingByUserID(ctx, userID)
+	if err != nil {
+		s.log.Error().Err(err).Str("userID", userID).Msg("failed to get favorite listings")
+		return nil, fmt.Errorf("failed to get favorite listings: %w", err)
+	}
+
+	changes := make(Changes, 0, len(favoriteListings))
+	var isChecked bool
+	for idx := range favoriteListings {
+		favorite := &favoriteListings[idx]
+		if favorite.IsGone || time.Since(favorite.CheckedAt) < s.cfg.FavoritesCheckInterval {
+			continue
+		}
+		if isChecked {
+			time.Sleep(fundaAPIQueryInterval)
+		}
+		isChecked = true
+
+		change := Change{
+			Kind:      KindFavorite,
+			OldPrice:  favorite.Offers.Price,
+			OldStatus: favorite.Status,
+		}
+		listing, errGet := s.GetListing(ctx, favorite.URL)
+		switch {
+		case errGet != nil && IsGone(errGet):
+			favorite.IsGone = true
+		case errGet != nil:
+			s.log.Warn().Err(errGet).Str("userID", userID).Str("objectID", favorite.ObjectID).Msg("failed to check favorite listing")
+			continue
+		default:
+			favorite.Name = listing.Name
+			favorite.URL = listing.URL
+			favorite.Offers.PriceCurrency = listing.Offers.PriceCurrency
+			favorite.Offers.Price = listing.Offers.Price
+			favorite.Status = listing.Status
+		}
+		favorite.CheckedAt = time.Now().UTC()
+
+		// changes of the favorites updated before are already stored, so they are reported regardless
+		if err = s.repository.UpdateFavoriteListingStatus(ctx, favorite); err != nil {
+			s.log.Error().Err(err).Str("userID", userID).Str("objectID", favorite.ObjectID).Msg("failed to update favorite listing status")
+			continue
+		}
+
+		change.Listing = *favorite
+		if change.IsPriceChanged() {
+			pricePoint := NewPricePoint(favorite, favorite.CheckedAt)
+			// the new price is already stored, so the change is reported even though the price history misses it
+			if err = s.repository.InsertPricePoint(ctx, &pricePoint); err != nil {
+				s.log.Error().Err(err).Str("userID", userID).Str("objectID", favorite.ObjectID).Msg("failed to record favorite listing price")
+			}
+		}
+		if favorite.IsGone || change.IsPriceChanged() || change.IsStatusChanged() {
+			changes = append(changes, change)
+		}
+	}
+
+	return changes, nil
+}
+
+func (s *Service) MGetPricePoint(ctx context.Context, userID, objectID string) (PricePoints, error) {
+	points, err := s.repository.MGetPricePointByUserIDAndObjectID(ctx, userID, objectID)
+	if err != nil {
+		s.log.Error().Err(err).Str("userID", userID).Str("objectID", objectID).Msg("failed to get price history")
+		return nil, fmt.Errorf("failed to get price history: %w", err)
+	}
+
+	return points, nil
+}
+
+func (s *Service) MDeletePricePointByUserIDTx(ctx context.Context, tx domain.Tx, userID string) error {
+	err := s.repository.MDeletePricePointByUserIDTx(ctx, tx, userID)
+	if err != nil {
+		s.log.Error().Err(err).Str("userID", userID).Msg("failed to delete price history")
+		return fmt.Errorf("failed to delete price history: %w", err)
+	}
+
+	return nil
+}
+
 func (s *Service) MDeleteFavoriteListingByUserIDTx(ctx context.Context, tx domain.Tx, userID string) error {
 	err := s.repository.MDeleteFavoriteListingByUserIDTx(ctx, tx, userID)
 	if err != nil {
@@ -153,7 +521,45 @@ func (s *Service) MDeleteFavoriteListingByUserIDTx(ctx context.Context, tx domai
 	return nil
 }
 
-func (s *Service) GetCurrentlyListedListings(ctx context.Context, searchQuery string) (Listings, error) {
+func (s *Service) GetCurrentlyListedListings(ctx context.Context, searchQuery string) (Listings, *SearchResult, error) {
+	searchResult, err := s.GetListingItems(ctx, searchQuery)
+	if err != nil {
+		return nil, nil, err
+	}
+	listingItems := searchResult.Items
+
+	// retrieve detailed listing data in parallel
+	resultsCh := make(chan *Listing, len(listingItems)) // buffer to prevent blocking
+	g, ctx := errgroup.WithContext(ctx)
+	for idx := range listingItems {
+		time.Sleep(fundaAPIQueryInterval)
+		g.Go(func() error {
+			listing, gErr := s.GetListing(ctx, listingItems[idx].URL)
+			if gErr != nil {
+				s.log.Error().Err(gErr).Msg("failed to get listing while retrieving detailed data")
+				return gErr
+			}
+			resultsCh <- listing
+			return nil
+		})
+	}
+
+	if err = g.Wait(); err != nil {
+		s.log.Error().Err(err).Msg("failed to fetch new listings in parallel")
+		return nil, nil, fmt.Errorf("failed to fetch new listings in parallel: %w", err)
+	}
+	close(resultsCh)
+
+	listings := make(Listings, 0, len(listingItems))
+	for l := range resultsCh {
+		listings = append(listings, *l)
+	}
+
+	return listings, searchResult, nil
+}
+
+// GetListingItems walks through search result pages until an empty page is found or a configured cap is reached.
+func (s *Service) GetListingItems(ctx context.Context, searchQuery string) (*SearchResult, error) {
 	parsedURL, err := url.Parse(searchQuery)
 	if err != nil {
 		s.log.Error().Err(err).Msg("failed to parse search query")
@@ -165,11 +571,18 @@ func (s *Service) GetCurrentlyListedListings(ctx context.Context, searchQuery st
 		htmlContent    []byte
 		doc            *goquery.Document
 		emptyPageFound bool
-		listingItems   = make([]ListingItem, 0, defaultCapacity)
 		queryParams    = parsedURL.Query()
+		numberOfItems  int
+		result         = SearchResult{Items: make([]ListingItem, 0, defaultCapacity)}
 	)
 
 	for {
+		if s.cfg.MaxPages > 0 && result.PagesCount >= s.cfg.MaxPages {
+			s.log.Warn().Str("url", parsedURL.String()).Int("maxPages", s.cfg.MaxPages).Msg("stopping pagination iteration at pages cap")
+			result.IsTruncated = numberOfItems == 0 || numberOfItems > len(result.Items)
+			break
+		}
+
 		// set pagination and retrieve HTML content
 		queryParams.Set("search_result", strconv.Itoa(pageNumber))
 		parsedURL.RawQuery = queryParams.Encode()
@@ -199,7 +612,8 @@ func (s *Service) GetCurrentlyListedListings(ctx context.Context, searchQuery st
 				s.log.Warn().Err(err).Msg("failed to parse listings search list")
 				return
 			}
-			listingItems = append(listingItems, listingSearchList.ItemListElement...)
+			result.Items = append(result.Items, listingSearchList.ItemListElement...)
+			numberOfItems = max(numberOfItems, listingSearchList.NumberOfItems)
 			emptyPageFound = false
 		})
 
@@ -211,39 +625,50 @@ func (s *Service) GetCurrentlyListedListings(ctx context.Context, searchQuery st
 
 		// increment pagination
 		pageNumber++
+		result.PagesCount++
+
+		// the total count is known from the first page, so broad queries are detected before walking through them,
+		// pages are still walked through up to the caps
+		if result.PagesCount == 1 && s.cfg.MaxListings > 0 && numberOfItems > s.cfg.MaxListings {
+			s.log.Warn().Str("url", parsedURL.String()).Int("numberOfItems", numberOfItems).Int("maxListings", s.cfg.MaxListings).Msg("search query is too broad")
+			result.IsTruncated = true
+		}
+
+		if s.cfg.MaxListings > 0 && len(result.Items) >= s.cfg.MaxListings {
+			s.log.Warn().Str("url", parsedURL.String()).Int("maxListings", s.cfg.MaxListings).Msg("stopping pagination iteration at listings cap")
+			result.IsTruncated = len(result.Items) > s.cfg.MaxListings || numberOfItems == 0 || numberOfItems > s.cfg.MaxListings
+			result.Items = result.Items[:s.cfg.MaxListings]
+			break
+		}
 
 		// sleep
 		time.Sleep(fundaAPIQueryInterval)
 	}
 
-	// retrieve detailed listing data in parallel
-	resultsCh := make(chan *Listing, len(listingItems)) // buffer to prevent blocking
-	g, ctx := errgroup.WithContext(ctx)
-	for idx := range listingItems {
-		time.Sleep(fundaAPIQueryInterval)
-		g.Go(func() error {
-			listing, gErr := s.GetListing(ctx, listingItems[idx].URL)
-			if gErr != nil {
-				s.log.Error().Err(gErr).Msg("failed to get listing while retrieving detailed data")
-				return gErr
-			}
-			resultsCh <- listing
-			return nil
-		})
-	}
+	result.TotalCount = max(numberOfItems, len(result.Items))
+	return &result, nil
+}
 
-	if err = g.Wait(); err != nil {
-		s.log.Error().Err(err).Msg("failed to fetch new listings in parallel")
-		return nil, fmt.Errorf("failed to fetch new listings in parallel: %w", err)
+// PreviewListings runs only the pagination phase of a sync, it neither fetches listing details nor touches the DB.
+func (s *Service) PreviewListings(ctx context.Context, searchQuery string) (*Preview, error) {
+	startTs := time.Now()
+	searchResult, err := s.GetListingItems(ctx, searchQuery)
+	if err != nil {
+		s.log.Error().Err(err).Msg("failed to get listing items for preview")
+		return nil, fmt.Errorf("failed to get listing items for preview: %w", err)
 	}
-	close(resultsCh)
 
-	listings := make(Listings, 0, len(listingItems))
-	for l := range resultsCh {
-		listings = append(listings, *l)
+	preview := Preview{
+		TotalCount:         searchResult.TotalCount,
+		TrackedCount:       len(searchResult.Items),
+		IsTruncated:        searchResult.IsTruncated,
+		PagesCount:         searchResult.PagesCount,
+		Items:              searchResult.Items[:min(len(searchResult.Items), previewItemsCount)],
+		PaginationDuration: time.Since(startTs),
 	}
+	preview.EstimateSyncDuration()
 
-	return listings, nil
+	return &preview, nil
 }
 
 func (s *Service) GetListing(ctx context.Context, URL string) (*Listing, error) {
@@ -253,8 +678,11 @@ func (s *Service) GetListing(ctx context.Context, URL string) (*Listing, error)
 		doc         *goquery.Document
 	)
 
-	// хитрые жопы upd 6Jun2025
-	URL = strings.Replace(URL, "/en/en/", "/en/", 1)
+	URL, err = funda.CanonicalListingURL(URL)
+	if err != nil {
+		s.log.Error().Err(err).Msg("failed to canonicalize listing URL")
+		return nil, fmt.Errorf("failed to canonicalize listing URL: %w", err)
+	}
 
 	htmlContent, err = s.fundaAPIClient.GetHTMLContent(ctx, URL)
 	if err != nil {
@@ -281,14 +709,33 @@ func (s *Service) GetListing(ctx context.Context, URL string) (*Listing, error)
 		}
 	})
 
+	listing.Status = parseStatus(doc)
+	listing.Agent = parseAgent(doc)
+	listing.LivingArea = parseLivingArea(doc)
+	listing.SetPhotos()
+	listing.Rooms = parseRooms(doc)
+	listing.EnergyLabel = parseEnergyLabel(doc)
+	listing.Latitude, listing.Longitude = parseLocation(doc, listing.Geo)
+	if listing.Address.PostalCode == "" {
+		listing.Address.PostalCode = parsePostalCode(doc)
+	}
+
+	// identify listing by its canonical URL and object ID rather than by the URL it advertises itself with
+	listing.URL = URL
+	listing.ObjectID, err = funda.ObjectID(URL)
+	if err != nil {
+		s.log.Warn().Err(err).Str("url", URL).Msg("failed to extract object ID, falling back to canonical URL")
+		listing.ObjectID = URL
+	}
+
 	return &listing, nil
 }
 
-func (s *Service) UpdateAndCompareListings(ctx context.Context, userID, searchQuery string) (addedListings, removedListings, leftoverListings Listings, err error) {
+func (s *Service) UpdateAndCompareListings(ctx context.Context, userID, queryName string, queryVersion int, searchQuery string) (*Update, error) {
 	tx, err := s.repository.Begin(ctx)
 	if err != nil {
 		s.log.Error().Err(err).Msg("failed to begin a transaction")
-		return nil, nil, nil, fmt.Errorf("failed to begin a transaction: %w", err)
+		return nil, fmt.Errorf("failed to begin a transaction: %w", err)
 	}
 
 	defer func(tx domain.Tx) {
@@ -298,42 +745,191 @@ func (s *Service) UpdateAndCompareListings(ctx context.Context, userID, searchQu
 		}
 	}(tx)
 
-	currentlyListedListings, err := s.GetCurrentlyListedListings(ctx, searchQuery)
+	currentlyListedListings, searchResult, err := s.GetCurrentlyListedListings(ctx, searchQuery)
 	if err != nil {
 		s.log.Error().Err(err).Msg("failed to get currently listed listings")
-		return nil, nil, nil, fmt.Errorf("failed to get currently listed listings: %w", err)
+		return nil, fmt.Errorf("failed to get currently listed listings: %w", err)
 	}
 
-	currentlyStoredListings, err := s.repository.MGetListingByUserIDTx(ctx, tx, userID)
+	currentlyStoredListings, err := s.repository.MGetListingByUserIDAndQueryNameTx(ctx, tx, userID, queryName)
 	if err != nil {
 		s.log.Error().Err(err).Msg("failed to get currently stored listings")
-		return nil, nil, nil, fmt.Errorf("failed to get currently stored listings: %w", err)
+		return nil, fmt.Errorf("failed to get currently stored listings: %w", err)
 	}
 
-	removedListings, leftoverListings = currentlyStoredListings.CompareAndGetRemovedListings(currentlyListedListings)
-	addedListings = currentlyListedListings.CompareAndGetAddedListings(currentlyStoredListings)
+	removedListings, leftoverListings := currentlyStoredListings.CompareAndGetRemovedListings(currentlyListedListings)
+	addedListings := currentlyListedListings.CompareAndGetAddedListings(currentlyStoredListings)
+	// listings beyond the cap of a truncated search are not seen, which does not mean they are gone, so they are
+	// deleted only once they are missing from the results for a number of syncs in a row
+	var missedListings Listings
+	if searchResult.IsTruncated {
+		removedListings, missedListings = removedListings.SplitByMissedSyncs(s.cfg.MaxMissedSyncs)
+	}
 	addedListings.SetUserID(userID)
+	addedListings.SetQueryName(queryName)
+	addedListings.SetQueryVersion(queryVersion)
 	addedListings.GenerateUUIDs()
 
-	if err = s.repository.MDeleteListingByUserIDAndURLsTx(ctx, tx, userID, removedListings.URLs()); err != nil {
+	// prices are recorded for new listings and whenever a stored listing is listed at a different price
+	now := time.Now().UTC()
+	pricePoints := make(PricePoints, 0, len(addedListings))
+	for idx := range addedListings {
+		pricePoints = append(pricePoints, NewPricePoint(&addedListings[idx], now))
+	}
+	currentlyListedMap := currentlyListedListings.MapByObjectID()
+	for idx := range leftoverListings {
+		listedListing := currentlyListedMap[leftoverListings[idx].ObjectID]
+		if listedListing.Offers.Price != leftoverListings[idx].Offers.Price {
+			leftoverListings[idx].Offers = listedListing.Offers
+			pricePoints = append(pricePoints, NewPricePoint(&leftoverListings[idx], now))
+		}
+	}
+
+	if err = s.repository.MDeleteListingByUserIDAndQueryNameAndObjectIDsTx(ctx, tx, userID, queryName, removedListings.ObjectIDs()); err != nil {
 		s.log.Error().Err(err).Msg("failed to delete removed listings")
-		return nil, nil, nil, fmt.Errorf("failed to delete removed listings: %w", err)
+		return nil, fmt.Errorf("failed to delete removed listings: %w", err)
+	}
+
+	if err = s.repository.MIncrementListingMissedSyncsByUserIDAndQueryNameAndObjectIDsTx(ctx, tx, userID, queryName, missedListings.ObjectIDs()); err != nil {
+		s.log.Error().Err(err).Msg("failed to count missed syncs of listings")
+		return nil, fmt.Errorf("failed to count missed syncs of listings: %w", err)
 	}
 
 	if err = s.repository.MInsertListingTx(ctx, tx, addedListings); err != nil {
 		s.log.Error().Err(err).Msg("failed to add new listings")
-		return nil, nil, nil, fmt.Errorf("failed to add new listings: %w", err)
+		return nil, fmt.Errorf("failed to add new listings: %w", err)
 	}
 
 	if err = s.repository.MUpdateListingTx(ctx, tx, leftoverListings); err != nil {
 		s.log.Error().Err(err).Msg("failed to update remaining listings")
-		return nil, nil, nil, fmt.Errorf("failed to update remaining listings: %w", err)
+		return nil, fmt.Errorf("failed to update remaining listings: %w", err)
+	}
+
+	if err = s.repository.MInsertPricePointTx(ctx, tx, pricePoints); err != nil {
+		s.log.Error().Err(err).Msg("failed to record prices")
+		return nil, fmt.Errorf("failed to record prices: %w", err)
 	}
 
 	if err = tx.Commit(); err != nil {
 		s.log.Error().Err(err).Msg("failed to commit a transaction")
-		return nil, nil, nil, fmt.Errorf("failed to commit a transaction: %w", err)
+		return nil, fmt.Errorf("failed to commit a transaction: %w", err)
+	}
+
+	update := Update{
+		Added:        addedListings,
+		Removed:      removedListings,
+		Leftover:     leftoverListings,
+		TotalCount:   searchResult.TotalCount,
+		TrackedCount: len(searchResult.Items),
+		IsTruncated:  searchResult.IsTruncated,
+	}
+
+	return &update, nil
+}
+
+// parseStatus looks for a status label such as "onder bod" or "verhuurd" shown as a standalone element of a listing page.
+func parseStatus(doc *goquery.Document) string {
+	status := StatusAvailable
+	doc.Find("span, div, li, p, strong").EachWithBreak(func(i int, selection *goquery.Selection) bool {
+		text := strings.ToLower(strings.TrimSpace(selection.Text()))
+		for idx := range statusLabels {
+			if text == statusLabels[idx].label {
+				status = statusLabels[idx].status
+				return false
+			}
+		}
+		return true
+	})
+	return status
+}
+
+// parseAgent takes the name of the first real-estate agent linked from a listing page.
+func parseAgent(doc *goquery.Document) string {
+	var agent string
+	doc.Find(`a[href*="/makelaar/"]`).EachWithBreak(func(i int, selection *goquery.Selection) bool {
+		agent = strings.Join(strings.Fields(selection.Text()), " ")
+		return agent == ""
+	})
+	return agent
+}
+
+// parseLivingArea takes the living area in square meters from the features list of a listing page, where it is given
+// as a "Wonen" or "Woonoppervlakte" term, zero means unknown.
+func parseLivingArea(doc *goquery.Document) int {
+	var area int
+	doc.Find("dt").EachWithBreak(func(i int, selection *goquery.Selection) bool {
+		term := strings.ToLower(strings.TrimSpace(selection.Text()))
+		if term != "wonen" && term != "woonoppervlakte" {
+			return true
+		}
+		match := livingAreaRegexp.FindStringSubmatch(selection.NextFiltered("dd").Text())
+		if match == nil {
+			return true
+		}
+		// thousands are separated by dots in Dutch
+		area, _ = strconv.Atoi(strings.ReplaceAll(match[1], ".", ""))
+		return area == 0
+	})
+	return area
+}
+
+// parseRooms takes the number of rooms from the "Aantal kamers" term of the features list, e.g. "4 kamers (3
+// slaapkamers)", zero means unknown.
+func parseRooms(doc *goquery.Document) int {
+	var rooms int
+	doc.Find("dt").EachWithBreak(func(i int, selection *goquery.Selection) bool {
+		if strings.ToLower(strings.TrimSpace(selection.Text())) != "aantal kamers" {
+			return true
+		}
+		match := roomsRegexp.FindStringSubmatch(selection.NextFiltered("dd").Text())
+		if match == nil {
+			return true
+		}
+		rooms, _ = strconv.Atoi(match[1])
+		return false
+	})
+	return rooms
+}
+
+// parseEnergyLabel takes the energy label such as "A++" or "C" from the "Energielabel" term of the features list.
+func parseEnergyLabel(doc *goquery.Document) string {
+	var label string
+	doc.Find("dt").EachWithBreak(func(i int, selection *goquery.Selection) bool {
+		if strings.ToLower(strings.TrimSpace(selection.Text())) != "energielabel" {
+			return true
+		}
+		match := energyLabelRegexp.FindStringSubmatch(strings.TrimSpace(selection.NextFiltered("dd").Text()))
+		if match == nil {
+			return true
+		}
+		label = match[1]
+		return false
+	})
+	return label
+}
+
+// parseLocation takes coordinates from the structured data of a listing page, falling back to its place meta tags.
+func parseLocation(doc *goquery.Document, geo *Geo) (float64, float64) {
+	var latitude, longitude string
+	if geo != nil && geo.Latitude != "" && geo.Longitude != "" {
+		latitude, longitude = geo.Latitude.String(), geo.Longitude.String()
+	} else {
+		latitude, _ = doc.Find(`meta[property="place:location:latitude"]`).Attr("content")
+		longitude, _ = doc.Find(`meta[property="place:location:longitude"]`).Attr("content")
+	}
+	lat, errLat := strconv.ParseFloat(latitude, 64)
+	lon, errLon := strconv.ParseFloat(longitude, 64)
+	if errLat != nil || errLon != nil {
+		return 0, 0
 	}
+	return lat, lon
+}
 
-	return addedListings, removedListings, leftoverListings, nil
+// parsePostalCode looks for a Dutch postal code in the listing page header, which shows it next to the city.
+func parsePostalCode(doc *goquery.Document) string {
+	match := postalCodeRegexp.FindStringSubmatch(doc.Find("h1").First().Text())
+	if match == nil {
+		return ""
+	}
+	return match[1] + match[2]
 }
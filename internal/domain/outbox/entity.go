@@ -0,0 +1,12 @@
+package outbox
+
+import "time"
+
+type Message struct {
+	ID        int64
+	UserID    string
+	Text      string
+	CreatedAt time.Time
+}
+
+type Messages []Message
@@ -0,0 +1,13 @@
+package outbox
+
+import (
+	"context"
+	"fundaNotifier/internal/domain"
+)
+
+type Repository interface {
+	InsertMessage(ctx context.Context, message *Message) error
+	MGetMessageByUserID(ctx context.Context, userID string) (Messages, error)
+	MDeleteMessageByUserIDAndIDs(ctx context.Context, userID string, IDs []int64) error
+	MDeleteMessageByUserIDTx(ctx context.Context, tx domain.Tx, userID string) error
+}
@@ -0,0 +1,71 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"fundaNotifier/internal/domain"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+type Service struct {
+	repository Repository
+	log        *zerolog.Logger
+}
+
+func NewService(
+	repository Repository,
+	log *zerolog.Logger,
+) *Service {
+	return &Service{
+		repository: repository,
+		log:        log,
+	}
+}
+
+func (s *Service) HoldMessage(ctx context.Context, userID, text string) error {
+	message := Message{
+		UserID:    userID,
+		Text:      text,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	err := s.repository.InsertMessage(ctx, &message)
+	if err != nil {
+		s.log.Error().Err(err).Str("userID", userID).Msg("failed to hold message")
+		return fmt.Errorf("failed to hold message: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Service) MGetMessageByUserID(ctx context.Context, userID string) (Messages, error) {
+	messages, err := s.repository.MGetMessageByUserID(ctx, userID)
+	if err != nil {
+		s.log.Error().Err(err).Str("userID", userID).Msg("failed to get held messages")
+		return nil, fmt.Errorf("failed to get held messages: %w", err)
+	}
+
+	return messages, nil
+}
+
+func (s *Service) MDeleteMessageByUserIDAndIDs(ctx context.Context, userID string, IDs []int64) error {
+	err := s.repository.MDeleteMessageByUserIDAndIDs(ctx, userID, IDs)
+	if err != nil {
+		s.log.Error().Err(err).Str("userID", userID).Msg("failed to delete delivered messages")
+		return fmt.Errorf("failed to delete delivered messages: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Service) MDeleteMessageByUserIDTx(ctx context.Context, tx domain.Tx, userID string) error {
+	err := s.repository.MDeleteMessageByUserIDTx(ctx, tx, userID)
+	if err != nil {
+		s.log.Error().Err(err).Str("userID", userID).Msg("failed to delete held messages")
+		return fmt.Errorf("failed to delete held messages: %w", err)
+	}
+
+	return nil
+}
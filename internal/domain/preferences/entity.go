@@ -0,0 +1,322 @@
+package preferences
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"fundaNotifier/internal/domain/listings"
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const (
+	FeaturePrice         = "price"
+	FeaturePricePerMeter = "price_m2"
+	FeatureArea          = "area"
+	FeatureRooms         = "rooms"
+	FeatureEnergy        = "energy"
+	// WordFeaturePrefix prefixes features telling whether a listing name or description contains a word
+	WordFeaturePrefix = "word:"
+
+	// MinClassFeedback is how many likes and dislikes each it takes to train a model
+	MinClassFeedback = 3
+	// MaxThreshold is the highest threshold, only listings which are certain matches reach it
+	MaxThreshold = 100
+
+	minWordLen = 4
+	// minWordCount is how many rated listings a word must occur in to become a feature, rarer words would only be noise
+	minWordCount   = 2
+	trainEpochs    = 500
+	learningRate   = 0.5
+	regularization = 0.01
+)
+
+// numericFeatures are standardized before training, a listing lacking one of them gets the average
+var numericFeatures = []string{FeaturePrice, FeaturePricePerMeter, FeatureArea, FeatureRooms, FeatureEnergy}
+
+var ErrInvalidThreshold = errors.New("invalid threshold")
+
+// Feedback is a thumbs up or down given to a listing, features are kept as of the moment it was given since the
+// listing itself does not outlive the queries which found it.
+type Feedback struct {
+	UserID   string
+	ObjectID string
+	Liked    bool
+	// FeaturesRaw is a JSON object of feature values
+	FeaturesRaw string
+	Features    map[string]float64
+	CreatedAt   time.Time
+}
+
+func NewFeedback(userID string, listing *listings.Listing, liked bool) (*Feedback, error) {
+	features := Features(listing)
+	featuresRaw, err := json.Marshal(features)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode features: %w", err)
+	}
+
+	return &Feedback{
+		UserID:      userID,
+		ObjectID:    listing.ObjectID,
+		Liked:       liked,
+		FeaturesRaw: string(featuresRaw),
+		Features:    features,
+		CreatedAt:   time.Now().UTC(),
+	}, nil
+}
+
+func (f *Feedback) ParseRaw() error {
+	f.Features = make(map[string]float64)
+	if err := json.Unmarshal([]byte(f.FeaturesRaw), &f.Features); err != nil {
+		return fmt.Errorf("failed to decode features: %w", err)
+	}
+	return nil
+}
+
+type Feedbacks []Feedback
+
+func (f Feedbacks) Counts() (likes, dislikes int) {
+	for idx := range f {
+		if f[idx].Liked {
+			likes++
+		} else {
+			dislikes++
+		}
+	}
+	return likes, dislikes
+}
+
+// Settings are per user, a zero threshold does not suppress notifications.
+type Settings struct {
+	UserID string
+	// Threshold is the match probability in percent new listings need to be notified about
+	Threshold int
+}
+
+func NewSettings(userID string) *Settings {
+	return &Settings{
+		UserID: userID,
+	}
+}
+
+// Features returns values of parsed attributes a listing has along with the words of its name and description.
+func Features(listing *listings.Listing) map[string]float64 {
+	features := make(map[string]float64)
+	if listing.Offers.Price > 0 {
+		features[FeaturePrice] = listing.Offers.Price
+		if listing.LivingArea > 0 {
+			features[FeaturePricePerMeter] = listing.Offers.Price / float64(listing.LivingArea)
+		}
+	}
+	if listing.LivingArea > 0 {
+		features[FeatureArea] = float64(listing.LivingArea)
+	}
+	if listing.Rooms > 0 {
+		features[FeatureRooms] = float64(listing.Rooms)
+	}
+	if rank, ok := listing.EnergyRank(); ok {
+		features[FeatureEnergy] = float64(rank)
+	}
+
+	words := strings.FieldsFunc(strings.ToLower(listing.Name+" "+listing.Description), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+	for _, word := range words {
+		if len([]rune(word)) >= minWordLen {
+			features[WordFeaturePrefix+word] = 1
+		}
+	}
+	return features
+}
+
+type standardization struct {
+	mean, scale float64
+}
+
+// Model is a logistic regression over listing features, trained on the feedback of a single user.
+type Model struct {
+	Likes    int
+	Dislikes int
+
+	bias            float64
+	weights         map[string]float64
+	standardization map[string]standardization
+}
+
+type FeatureWeight struct {
+	Name   string
+	Weight float64
+}
+
+// Train fits a model to feedback, it stays untrained until there are enough likes and dislikes.
+func Train(feedbacks Feedbacks) *Model {
+	model := Model{
+		weights:         make(map[string]float64),
+		standardization: make(map[string]standardization),
+	}
+	model.Likes, model.Dislikes = feedbacks.Counts()
+	if !model.IsTrained() {
+		return &model
+	}
+
+	for _, feature := range numericFeatures {
+		var sum, sumSquares, count float64
+		for idx := range feedbacks {
+			if value, ok := feedbacks[idx].Features[feature]; ok {
+				sum += value
+				sumSquares += value * value
+				count++
+			}
+		}
+		if count == 0 {
+			continue
+		}
+		mean := sum / count
+		scale := math.Sqrt(sumSquares/count - mean*mean)
+		if scale == 0 || math.IsNaN(scale) {
+			scale = 1
+		}
+		model.standardization[feature] = standardization{mean: mean, scale: scale}
+	}
+
+	wordCounts := make(map[string]int)
+	for idx := range feedbacks {
+		for feature := range feedbacks[idx].Features {
+			if strings.HasPrefix(feature, WordFeaturePrefix) {
+				wordCounts[feature]++
+			}
+		}
+	}
+	for word, count := range wordCounts {
+		if count >= minWordCount {
+			model.weights[word] = 0
+		}
+	}
+	for feature := range model.standardization {
+		model.weights[feature] = 0
+	}
+
+	vectors := make([]map[string]float64, 0, len(feedbacks))
+	labels := make([]float64, 0, len(feedbacks))
+	for idx := range feedbacks {
+		vectors = append(vectors, model.vector(feedbacks[idx].Features))
+		label := 0.0
+		if feedbacks[idx].Liked {
+			label = 1
+		}
+		labels = append(labels, label)
+	}
+
+	// batch gradient descent with L2 regularization, which keeps weights of words seen a couple of times small
+	n := float64(len(vectors))
+	for epoch := 0; epoch < trainEpochs; epoch++ {
+		gradients := make(map[string]float64, len(model.weights))
+		var biasGradient float64
+		for idx := range vectors {
+			diff := model.predict(vectors[idx]) - labels[idx]
+			biasGradient += diff
+			for feature, value := range vectors[idx] {
+				gradients[feature] += diff * value
+			}
+		}
+		for feature, weight := range model.weights {
+			model.weights[feature] = weight - learningRate*(gradients[feature]/n+regularization*weight)
+		}
+		model.bias -= learningRate * biasGradient / n
+	}
+	return &model
+}
+
+func (m *Model) IsTrained() bool {
+	return m.Likes >= MinClassFeedback && m.Dislikes >= MinClassFeedback
+}
+
+// Probability is the chance in percent that the user likes a listing, it is false for an untrained model.
+func (m *Model) Probability(listing *listings.Listing) (int, bool) {
+	if !m.IsTrained() {
+		return 0, false
+	}
+	return int(math.Round(m.predict(m.vector(Features(listing))) * 100)), true
+}
+
+// Sort orders listings from the most likely match, an untrained model keeps the default order.
+func (m *Model) Sort(l listings.Listings) {
+	l.Sort()
+	if !m.IsTrained() {
+		return
+	}
+	type rated struct {
+		listing     listings.Listing
+		probability int
+	}
+	ratedListings := make([]rated, 0, len(l))
+	for idx := range l {
+		probability, _ := m.Probability(&l[idx])
+		ratedListings = append(ratedListings, rated{listing: l[idx], probability: probability})
+	}
+	sort.SliceStable(ratedListings, func(i, j int) bool {
+		return ratedListings[i].probability > ratedListings[j].probability
+	})
+	for idx := range ratedListings {
+		l[idx] = ratedListings[idx].listing
+	}
+}
+
+// Suppress splits listings into those reaching the threshold and those which do not, nothing is suppressed by an
+// untrained model or a zero threshold.
+func (m *Model) Suppress(l listings.Listings, threshold int) (listings.Listings, listings.Listings) {
+	if !m.IsTrained() || threshold <= 0 {
+		return l, nil
+	}
+	kept := make(listings.Listings, 0, len(l))
+	suppressed := make(listings.Listings, 0)
+	for idx := range l {
+		if probability, _ := m.Probability(&l[idx]); probability >= threshold {
+			kept = append(kept, l[idx])
+		} else {
+			suppressed = append(suppressed, l[idx])
+		}
+	}
+	return kept, suppressed
+}
+
+// Weights returns learned feature weights from the most liked to the most disliked one.
+func (m *Model) Weights() []FeatureWeight {
+	weights := make([]FeatureWeight, 0, len(m.weights))
+	for feature, weight := range m.weights {
+		weights = append(weights, FeatureWeight{Name: feature, Weight: weight})
+	}
+	sort.Slice(weights, func(i, j int) bool {
+		if weights[i].Weight == weights[j].Weight {
+			return weights[i].Name < weights[j].Name
+		}
+		return weights[i].Weight > weights[j].Weight
+	})
+	return weights
+}
+
+// vector keeps features known to the model, numeric ones standardized.
+func (m *Model) vector(features map[string]float64) map[string]float64 {
+	vector := make(map[string]float64, len(features))
+	for feature, value := range features {
+		if _, ok := m.weights[feature]; !ok {
+			continue
+		}
+		if s, ok := m.standardization[feature]; ok {
+			value = (value - s.mean) / s.scale
+		}
+		vector[feature] = value
+	}
+	return vector
+}
+
+func (m *Model) predict(vector map[string]float64) float64 {
+	z := m.bias
+	for feature, value := range vector {
+		z += m.weights[feature] * value
+	}
+	return 1 / (1 + math.Exp(-z))
+}
@@ -0,0 +1,16 @@
+package preferences
+
+import (
+	"context"
+	"fundaNotifier/internal/domain"
+)
+
+type Repository interface {
+	UpsertFeedback(ctx context.Context, feedback *Feedback) error
+	MGetFeedbackByUserID(ctx context.Context, userID string) (Feedbacks, error)
+	MDeleteFeedbackByUserID(ctx context.Context, userID string) error
+	MDeleteFeedbackByUserIDTx(ctx context.Context, tx domain.Tx, userID string) error
+	UpsertSettings(ctx context.Context, settings *Settings) error
+	GetSettingsByUserID(ctx context.Context, userID string) (*Settings, error)
+	DeleteSettingsByUserIDTx(ctx context.Context, tx domain.Tx, userID string) error
+}
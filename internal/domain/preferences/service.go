@@ -0,0 +1,168 @@
+package preferences
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"fundaNotifier/internal/domain"
+	"fundaNotifier/internal/domain/listings"
+	"sync"
+
+	"github.com/rs/zerolog"
+)
+
+// models keeps in-memory trained models by user ID, so that a model is trained anew only after its feedback changes.
+type models struct {
+	mu       sync.Mutex
+	data     map[string]*Model
+	versions map[string]int
+}
+
+func newModels() *models {
+	return &models{data: make(map[string]*Model), versions: make(map[string]int)}
+}
+
+// get returns the cached model of a user, if any, along with the version of the feedback it has to be trained on.
+func (m *models) get(userID string) (*Model, int, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	model, ok := m.data[userID]
+	return model, m.versions[userID], ok
+}
+
+// set caches a model unless the feedback of the user was changed while it was being trained.
+func (m *models) set(userID string, version int, model *Model) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.versions[userID] != version {
+		return
+	}
+	m.data[userID] = model
+}
+
+func (m *models) invalidate(userID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.data, userID)
+	m.versions[userID]++
+}
+
+type Service struct {
+	repository Repository
+	models     *models
+	log        *zerolog.Logger
+}
+
+func NewService(
+	repository Repository,
+	log *zerolog.Logger,
+) *Service {
+	return &Service{
+		repository: repository,
+		models:     newModels(),
+		log:        log,
+	}
+}
+
+// SetFeedback stores a thumbs up or down given to a listing, replacing the previous one.
+func (s *Service) SetFeedback(ctx context.Context, userID string, listing *listings.Listing, liked bool) error {
+	feedback, err := NewFeedback(userID, listing, liked)
+	if err != nil {
+		s.log.Error().Err(err).Str("userID", userID).Msg("failed to create feedback")
+		return fmt.Errorf("failed to create feedback: %w", err)
+	}
+
+	err = s.repository.UpsertFeedback(ctx, feedback)
+	s.models.invalidate(userID)
+	if err != nil {
+		s.log.Error().Err(err).Str("userID", userID).Msg("failed to upsert feedback")
+		return fmt.Errorf("failed to upsert feedback: %w", err)
+	}
+
+	return nil
+}
+
+// GetModel returns a model trained on the feedback of a user, it is untrained when there is not enough feedback yet.
+// The model is trained once and cached until the feedback changes, callers must not modify it.
+func (s *Service) GetModel(ctx context.Context, userID string) (*Model, error) {
+	model, version, ok := s.models.get(userID)
+	if ok {
+		return model, nil
+	}
+
+	feedbacks, err := s.repository.MGetFeedbackByUserID(ctx, userID)
+	if err != nil {
+		s.log.Error().Err(err).Str("userID", userID).Msg("failed to get feedback")
+		return nil, fmt.Errorf("failed to get feedback: %w", err)
+	}
+
+	model = Train(feedbacks)
+	s.models.set(userID, version, model)
+	return model, nil
+}
+
+// GetSettings returns settings of a user, the default ones when they were never changed.
+func (s *Service) GetSettings(ctx context.Context, userID string) (*Settings, error) {
+	settings, err := s.repository.GetSettingsByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return NewSettings(userID), nil
+		}
+		s.log.Error().Err(err).Str("userID", userID).Msg("failed to get preference settings")
+		return nil, fmt.Errorf("failed to get preference settings: %w", err)
+	}
+
+	return settings, nil
+}
+
+func (s *Service) SetThreshold(ctx context.Context, userID string, threshold int) error {
+	if threshold < 0 || threshold > MaxThreshold {
+		return fmt.Errorf("%d is not within 0-%d: %w", threshold, MaxThreshold, ErrInvalidThreshold)
+	}
+
+	settings := NewSettings(userID)
+	settings.Threshold = threshold
+	err := s.repository.UpsertSettings(ctx, settings)
+	if err != nil {
+		s.log.Error().Err(err).Str("userID", userID).Msg("failed to upsert preference settings")
+		return fmt.Errorf("failed to upsert preference settings: %w", err)
+	}
+
+	return nil
+}
+
+// ResetModel forgets all feedback of a user, it returns a wrapped sql.ErrNoRows when there is none.
+func (s *Service) ResetModel(ctx context.Context, userID string) error {
+	err := s.repository.MDeleteFeedbackByUserID(ctx, userID)
+	s.models.invalidate(userID)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			s.log.Error().Err(err).Str("userID", userID).Msg("failed to delete feedback")
+		}
+		return fmt.Errorf("failed to delete feedback: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Service) DeletePreferencesByUserIDTx(ctx context.Context, tx domain.Tx, userID string) error {
+	err := s.repository.MDeleteFeedbackByUserIDTx(ctx, tx, userID)
+	if err != nil {
+		s.log.Error().Err(err).Str("userID", userID).Msg("failed to delete feedback")
+		return fmt.Errorf("failed to delete feedback: %w", err)
+	}
+
+	err = s.repository.DeleteSettingsByUserIDTx(ctx, tx, userID)
+	if err != nil {
+		s.log.Error().Err(err).Str("userID", userID).Msg("failed to delete preference settings")
+		return fmt.Errorf("failed to delete preference settings: %w", err)
+	}
+
+	return nil
+}
+
+// ForgetModel drops the cached model of a user, it is called once a transaction deleting their feedback is committed.
+func (s *Service) ForgetModel(userID string) {
+	s.models.invalidate(userID)
+}
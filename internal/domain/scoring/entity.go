@@ -0,0 +1,307 @@
+package scoring
+
+import (
+	"errors"
+	"fmt"
+	"fundaNotifier/internal/domain/listings"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	CriterionPrice         = "price"
+	CriterionPricePerMeter = "price_m2"
+	CriterionRooms         = "rooms"
+	CriterionArea          = "area"
+	CriterionEnergy        = "energy"
+	CriterionDistance      = "distance"
+	CriterionKeywords      = "keywords"
+
+	MaxWeight = 10
+	// MaxScore is the score of a listing which is the best at every weighted criterion
+	MaxScore = 100
+
+	earthRadiusKm = 6371
+)
+
+// Criteria are listed in the order they are shown in.
+var Criteria = []string{CriterionPrice, CriterionPricePerMeter, CriterionRooms, CriterionArea, CriterionEnergy, CriterionDistance, CriterionKeywords}
+
+var (
+	ErrUnknownCriterion = errors.New("unknown criterion")
+	ErrInvalidWeight    = errors.New("invalid weight")
+	ErrInvalidName      = errors.New("invalid point of interest name")
+)
+
+type PointOfInterest struct {
+	Name      string
+	Latitude  float64
+	Longitude float64
+}
+
+// Profile is a personal scoring of listings, raw fields are stored while the parsed ones are used.
+type Profile struct {
+	UserID string
+	// WeightsRaw is a comma-separated list of criterion=weight pairs
+	WeightsRaw string
+	Weights    map[string]int
+	// KeywordsRaw is a comma-separated list of lowercase keywords
+	KeywordsRaw string
+	Keywords    []string
+	// PointsRaw is a semicolon-separated list of name:latitude:longitude triples
+	PointsRaw string
+	Points    []PointOfInterest
+}
+
+func NewProfile(userID string) *Profile {
+	return &Profile{
+		UserID:  userID,
+		Weights: make(map[string]int),
+	}
+}
+
+func (p *Profile) ParseRaw() {
+	p.Weights = make(map[string]int)
+	for _, pair := range strings.Split(p.WeightsRaw, ",") {
+		criterion, rawWeight, ok := strings.Cut(pair, "=")
+		weight, err := strconv.Atoi(rawWeight)
+		if ok && err == nil && slices.Contains(Criteria, criterion) && weight > 0 {
+			p.Weights[criterion] = weight
+		}
+	}
+
+	p.Keywords = nil
+	for _, keyword := range strings.Split(p.KeywordsRaw, ",") {
+		if keyword = strings.TrimSpace(keyword); keyword != "" {
+			p.Keywords = append(p.Keywords, keyword)
+		}
+	}
+
+	p.Points = nil
+	for _, rawPoint := range strings.Split(p.PointsRaw, ";") {
+		fields := strings.Split(rawPoint, ":")
+		if len(fields) != 3 {
+			continue
+		}
+		latitude, errLat := strconv.ParseFloat(fields[1], 64)
+		longitude, errLon := strconv.ParseFloat(fields[2], 64)
+		if errLat == nil && errLon == nil {
+			p.Points = append(p.Points, PointOfInterest{Name: fields[0], Latitude: latitude, Longitude: longitude})
+		}
+	}
+}
+
+func (p *Profile) encodeRaw() {
+	pairs := make([]string, 0, len(p.Weights))
+	for _, criterion := range Criteria {
+		if weight := p.Weights[criterion]; weight > 0 {
+			pairs = append(pairs, fmt.Sprintf("%s=%d", criterion, weight))
+		}
+	}
+	p.WeightsRaw = strings.Join(pairs, ",")
+	p.KeywordsRaw = strings.Join(p.Keywords, ",")
+
+	points := make([]string, 0, len(p.Points))
+	for idx := range p.Points {
+		points = append(points, fmt.Sprintf("%s:%s:%s", p.Points[idx].Name, strconv.FormatFloat(p.Points[idx].Latitude, 'f', -1, 64), strconv.FormatFloat(p.Points[idx].Longitude, 'f', -1, 64)))
+	}
+	p.PointsRaw = strings.Join(points, ";")
+}
+
+// IsEmpty is true when no criterion is weighted, such a profile does not score listings.
+func (p *Profile) IsEmpty() bool {
+	return len(p.Weights) == 0
+}
+
+// SetWeight weights a criterion, zero excludes it from scoring.
+func (p *Profile) SetWeight(criterion string, weight int) error {
+	if !slices.Contains(Criteria, criterion) {
+		return fmt.Errorf("%q: %w", criterion, ErrUnknownCriterion)
+	}
+	if weight < 0 || weight > MaxWeight {
+		return fmt.Errorf("%d is not within 0-%d: %w", weight, MaxWeight, ErrInvalidWeight)
+	}
+	if weight == 0 {
+		delete(p.Weights, criterion)
+	} else {
+		p.Weights[criterion] = weight
+	}
+	p.encodeRaw()
+	return nil
+}
+
+// SetKeywords replaces keywords, they are matched case-insensitively against listing names and descriptions.
+func (p *Profile) SetKeywords(keywords []string) {
+	p.Keywords = nil
+	for _, keyword := range keywords {
+		keyword = strings.ToLower(strings.TrimSpace(keyword))
+		if keyword != "" && !slices.Contains(p.Keywords, keyword) {
+			p.Keywords = append(p.Keywords, keyword)
+		}
+	}
+	p.encodeRaw()
+}
+
+// SetPoint adds a point of interest or moves an existing one with the same name.
+func (p *Profile) SetPoint(point PointOfInterest) error {
+	if point.Name == "" || strings.ContainsAny(point.Name, ":;") {
+		return fmt.Errorf("%q: %w", point.Name, ErrInvalidName)
+	}
+	p.RemovePoint(point.Name)
+	p.Points = append(p.Points, point)
+	p.encodeRaw()
+	return nil
+}
+
+// RemovePoint returns false when there is no point of interest with the given name.
+func (p *Profile) RemovePoint(name string) bool {
+	idx := slices.IndexFunc(p.Points, func(point PointOfInterest) bool {
+		return strings.EqualFold(point.Name, name)
+	})
+	if idx == -1 {
+		return false
+	}
+	p.Points = slices.Delete(p.Points, idx, idx+1)
+	p.encodeRaw()
+	return true
+}
+
+type valueRange struct {
+	min, max float64
+}
+
+// Scorer scores listings relative to a pool, criteria such as price are compared against the cheapest and the most
+// expensive listing of the pool.
+type Scorer struct {
+	profile *Profile
+	ranges  map[string]valueRange
+}
+
+func (p *Profile) NewScorer(pool listings.Listings) *Scorer {
+	scorer := Scorer{
+		profile: p,
+		ranges:  make(map[string]valueRange),
+	}
+	for idx := range pool {
+		for criterion, value := range p.values(&pool[idx]) {
+			r, ok := scorer.ranges[criterion]
+			if !ok {
+				r = valueRange{min: value, max: value}
+			}
+			scorer.ranges[criterion] = valueRange{min: min(r.min, value), max: max(r.max, value)}
+		}
+	}
+	return &scorer
+}
+
+// Score rates a listing from 0 to MaxScore, criteria a listing lacks data for are left out of its score. It returns
+// false when no weighted criterion applies to the listing.
+func (s *Scorer) Score(listing *listings.Listing) (int, bool) {
+	var total, weights float64
+	for criterion, value := range s.profile.values(listing) {
+		weight := float64(s.profile.Weights[criterion])
+		if weight == 0 {
+			continue
+		}
+		total += weight * s.normalize(criterion, value)
+		weights += weight
+	}
+	if weights == 0 {
+		return 0, false
+	}
+	return int(math.Round(total / weights * MaxScore)), true
+}
+
+// Sort orders listings from the best score, unscored listings go last in their default order.
+func (s *Scorer) Sort(l listings.Listings) {
+	l.Sort()
+	type scored struct {
+		listing listings.Listing
+		score   int
+	}
+	scoredListings := make([]scored, 0, len(l))
+	for idx := range l {
+		score, ok := s.Score(&l[idx])
+		if !ok {
+			score = -1
+		}
+		scoredListings = append(scoredListings, scored{listing: l[idx], score: score})
+	}
+	sort.SliceStable(scoredListings, func(i, j int) bool {
+		return scoredListings[i].score > scoredListings[j].score
+	})
+	for idx := range scoredListings {
+		l[idx] = scoredListings[idx].listing
+	}
+}
+
+// normalize maps a value to 0-1 where 1 is the best, relative criteria use the range of the pool.
+func (s *Scorer) normalize(criterion string, value float64) float64 {
+	switch criterion {
+	case CriterionEnergy:
+		return value / float64(len(listings.EnergyLabels)-1)
+	case CriterionKeywords:
+		return value / float64(len(s.profile.Keywords))
+	}
+
+	r, ok := s.ranges[criterion]
+	if !ok || r.max == r.min {
+		return 1
+	}
+	normalized := (value - r.min) / (r.max - r.min)
+	switch criterion {
+	case CriterionPrice, CriterionPricePerMeter, CriterionDistance:
+		return 1 - normalized
+	default:
+		return normalized
+	}
+}
+
+// values returns raw values of the criteria a listing has data for, regardless of their weights.
+func (p *Profile) values(listing *listings.Listing) map[string]float64 {
+	values := make(map[string]float64, len(Criteria))
+	if listing.Offers.Price > 0 {
+		values[CriterionPrice] = listing.Offers.Price
+		if listing.LivingArea > 0 {
+			values[CriterionPricePerMeter] = listing.Offers.Price / float64(listing.LivingArea)
+		}
+	}
+	if listing.Rooms > 0 {
+		values[CriterionRooms] = float64(listing.Rooms)
+	}
+	if listing.LivingArea > 0 {
+		values[CriterionArea] = float64(listing.LivingArea)
+	}
+	if rank, ok := listing.EnergyRank(); ok {
+		values[CriterionEnergy] = float64(rank)
+	}
+	if len(p.Points) != 0 && listing.HasLocation() {
+		var total float64
+		for idx := range p.Points {
+			total += distanceKm(listing.Latitude, listing.Longitude, p.Points[idx].Latitude, p.Points[idx].Longitude)
+		}
+		values[CriterionDistance] = total / float64(len(p.Points))
+	}
+	if len(p.Keywords) != 0 {
+		text := strings.ToLower(listing.Name + " " + listing.Description)
+		var hits int
+		for _, keyword := range p.Keywords {
+			if strings.Contains(text, keyword) {
+				hits++
+			}
+		}
+		values[CriterionKeywords] = float64(hits)
+	}
+	return values
+}
+
+// distanceKm is the great-circle distance between two points given in degrees.
+func distanceKm(lat1, lon1, lat2, lon2 float64) float64 {
+	toRad := func(deg float64) float64 { return deg * math.Pi / 180 }
+	dLat, dLon := toRad(lat2-lat1), toRad(lon2-lon1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(toRad(lat1))*math.Cos(toRad(lat2))*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(a))
+}
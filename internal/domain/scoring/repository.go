@@ -0,0 +1,13 @@
+package scoring
+
+import (
+	"context"
+	"fundaNotifier/internal/domain"
+)
+
+type Repository interface {
+	UpsertProfile(ctx context.Context, profile *Profile) error
+	GetProfileByUserID(ctx context.Context, userID string) (*Profile, error)
+	DeleteProfileByUserID(ctx context.Context, userID string) error
+	DeleteProfileByUserIDTx(ctx context.Context, tx domain.Tx, userID string) error
+}
@@ -0,0 +1,110 @@
+package scoring
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"fundaNotifier/internal/domain"
+
+	"github.com/rs/zerolog"
+)
+
+type Service struct {
+	repository Repository
+	log        *zerolog.Logger
+}
+
+func NewService(
+	repository Repository,
+	log *zerolog.Logger,
+) *Service {
+	return &Service{
+		repository: repository,
+		log:        log,
+	}
+}
+
+// GetProfile returns the scoring profile of a user, an empty one when it was never set up.
+func (s *Service) GetProfile(ctx context.Context, userID string) (*Profile, error) {
+	profile, err := s.repository.GetProfileByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return NewProfile(userID), nil
+		}
+		s.log.Error().Err(err).Str("userID", userID).Msg("failed to get scoring profile")
+		return nil, fmt.Errorf("failed to get scoring profile: %w", err)
+	}
+
+	return profile, nil
+}
+
+func (s *Service) SetWeight(ctx context.Context, userID, criterion string, weight int) (*Profile, error) {
+	return s.updateProfile(ctx, userID, func(profile *Profile) error {
+		return profile.SetWeight(criterion, weight)
+	})
+}
+
+func (s *Service) SetKeywords(ctx context.Context, userID string, keywords []string) (*Profile, error) {
+	return s.updateProfile(ctx, userID, func(profile *Profile) error {
+		profile.SetKeywords(keywords)
+		return nil
+	})
+}
+
+func (s *Service) SetPoint(ctx context.Context, userID string, point PointOfInterest) (*Profile, error) {
+	return s.updateProfile(ctx, userID, func(profile *Profile) error {
+		return profile.SetPoint(point)
+	})
+}
+
+// RemovePoint returns a wrapped sql.ErrNoRows when there is no point of interest with the given name.
+func (s *Service) RemovePoint(ctx context.Context, userID, name string) (*Profile, error) {
+	return s.updateProfile(ctx, userID, func(profile *Profile) error {
+		if !profile.RemovePoint(name) {
+			return fmt.Errorf("point of interest %q: %w", name, sql.ErrNoRows)
+		}
+		return nil
+	})
+}
+
+func (s *Service) ResetProfile(ctx context.Context, userID string) error {
+	err := s.repository.DeleteProfileByUserID(ctx, userID)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			s.log.Error().Err(err).Str("userID", userID).Msg("failed to delete scoring profile")
+		}
+		return fmt.Errorf("failed to delete scoring profile: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Service) DeleteProfileByUserIDTx(ctx context.Context, tx domain.Tx, userID string) error {
+	err := s.repository.DeleteProfileByUserIDTx(ctx, tx, userID)
+	if err != nil {
+		s.log.Error().Err(err).Str("userID", userID).Msg("failed to delete scoring profile")
+		return fmt.Errorf("failed to delete scoring profile: %w", err)
+	}
+
+	return nil
+}
+
+// updateProfile applies a change to the profile of a user and stores it unless the change is rejected.
+func (s *Service) updateProfile(ctx context.Context, userID string, change func(profile *Profile) error) (*Profile, error) {
+	profile, err := s.GetProfile(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err = change(profile); err != nil {
+		return nil, fmt.Errorf("failed to change scoring profile: %w", err)
+	}
+
+	err = s.repository.UpsertProfile(ctx, profile)
+	if err != nil {
+		s.log.Error().Err(err).Str("userID", userID).Msg("failed to upsert scoring profile")
+		return nil, fmt.Errorf("failed to upsert scoring profile: %w", err)
+	}
+
+	return profile, nil
+}
@@ -0,0 +1,94 @@
+package search_queries
+
+import (
+	"fundaNotifier/internal/domain/listings"
+	"strings"
+	"time"
+)
+
+const DefaultQueryName = "default"
+
+type SearchQuery struct {
+	UserID     string
+	Name       string
+	URL        string
+	Version    int
+	IsActive   bool
+	RegionsRaw string
+	Regions    []string
+	CitiesRaw  string
+	Cities     []string
+}
+
+func (q *SearchQuery) ParseRawRegionsAndCities() {
+	q.Regions = splitUnique(q.RegionsRaw)
+	q.RegionsRaw = strings.Join(q.Regions, ",")
+	q.Cities = splitUnique(q.CitiesRaw)
+	q.CitiesRaw = strings.Join(q.Cities, ",")
+}
+
+type SearchQueries []SearchQuery
+
+func (q *SearchQueries) Active() SearchQueries {
+	if q == nil || len(*q) == 0 {
+		return nil
+	}
+	result := make(SearchQueries, 0, len(*q))
+	for idx := range *q {
+		if (*q)[idx].IsActive {
+			result = append(result, (*q)[idx])
+		}
+	}
+	return result
+}
+
+// FilterListings applies each query's own filters to the listings found by it and drops duplicates across queries.
+func (q *SearchQueries) FilterListings(l listings.Listings) listings.Listings {
+	if q == nil || len(*q) == 0 || len(l) == 0 {
+		return nil
+	}
+
+	byQueryName := make(map[string]listings.Listings, len(*q))
+	for idx := range l {
+		byQueryName[l[idx].QueryName] = append(byQueryName[l[idx].QueryName], l[idx])
+	}
+
+	result := make(listings.Listings, 0, len(l))
+	for idx := range *q {
+		queryListings := byQueryName[(*q)[idx].Name]
+		result = append(result, queryListings.FilterByRegionsAndCities((*q)[idx].Regions, (*q)[idx].Cities)...)
+	}
+	return result.UniqueByObjectID()
+}
+
+// Version is a snapshot of a search query URL, every change of the URL produces a new one.
+type Version struct {
+	UserID    string
+	QueryName string
+	Number    int
+	URL       string
+	CreatedAt time.Time
+	// ListingsCount is the number of currently stored listings first found by this version
+	ListingsCount int
+}
+
+type Versions []Version
+
+// NormalizeName turns a user-provided query name into a lowercase single-word identifier.
+func NormalizeName(name string) string {
+	return strings.Join(strings.Fields(strings.ToLower(name)), "_")
+}
+
+func splitUnique(raw string) []string {
+	encountered := make(map[string]bool)
+	result := []string{}
+	for _, s := range strings.Split(strings.ToLower(raw), ",") {
+		s = strings.TrimSpace(s)
+		if s == "" || encountered[s] {
+			continue
+		}
+		encountered[s] = true
+		result = append(result, s)
+	}
+	return result
+}
@@ -6,7 +6,18 @@ import (
 )
 
 type Repository interface {
-	UpsertSearchQueryByUserID(ctx context.Context, userID, searchQuery string) error
+	Begin(ctx context.Context) (domain.Tx, error)
+	UpsertSearchQueryTx(ctx context.Context, tx domain.Tx, query *SearchQuery) error
+	GetSearchQueryByUserIDAndName(ctx context.Context, userID, queryName string) (*SearchQuery, error)
+	MGetSearchQueryByUserID(ctx context.Context, userID string) (SearchQueries, error)
+	UpdateSearchQueryIsActiveByUserIDAndName(ctx context.Context, userID, queryName string, isActive bool) error
+	UpdateSearchQueryFiltersByUserIDAndName(ctx context.Context, userID, queryName, regions, cities string) error
+	DeleteSearchQueryByUserIDAndNameTx(ctx context.Context, tx domain.Tx, userID, queryName string) error
 	DeleteSearchQueryByUserIDTx(ctx context.Context, tx domain.Tx, userID string) error
-	GetSearchQueryByUserID(ctx context.Context, userID string) (string, error)
+	InsertSearchQueryVersionTx(ctx context.Context, tx domain.Tx, version *Version) error
+	GetLatestSearchQueryVersionByUserIDAndNameTx(ctx context.Context, tx domain.Tx, userID, queryName string) (*Version, error)
+	GetSearchQueryVersionByUserIDAndNameAndNumber(ctx context.Context, userID, queryName string, number int) (*Version, error)
+	MGetSearchQueryVersionByUserIDAndName(ctx context.Context, userID, queryName string) (Versions, error)
+	MDeleteSearchQueryVersionByUserIDAndNameTx(ctx context.Context, tx domain.Tx, userID, queryName string) error
+	MDeleteSearchQueryVersionByUserIDTx(ctx context.Context, tx domain.Tx, userID string) error
 }
@@ -2,52 +2,254 @@ package search_queries
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"fundaNotifier/internal/domain"
+	"fundaNotifier/internal/pkg/funda"
+	"time"
 
 	"github.com/rs/zerolog"
 )
 
+type ListingsService interface {
+	MDeleteListingByUserIDAndQueryNameTx(ctx context.Context, tx domain.Tx, userID, queryName string) error
+	CountListingByQueryVersion(ctx context.Context, userID, queryName string) (map[int]int, error)
+}
+
 type Service struct {
-	repository Repository
-	log        *zerolog.Logger
+	repository      Repository
+	listingsService ListingsService
+	log             *zerolog.Logger
 }
 
 func NewService(
 	repository Repository,
+	listingsService ListingsService,
 	log *zerolog.Logger,
 ) *Service {
 	return &Service{
-		repository: repository,
-		log:        log,
+		repository:      repository,
+		listingsService: listingsService,
+		log:             log,
 	}
 }
 
-func (s *Service) GetSearchQuery(ctx context.Context, userID string) (URL string, err error) {
-	URL, err = s.repository.GetSearchQueryByUserID(ctx, userID)
+// UpsertSearchQuery sets the search query URL and records it as a new version unless the URL did not change.
+func (s *Service) UpsertSearchQuery(ctx context.Context, userID, queryName, URL string) error {
+	_, _, err := s.upsertSearchQuery(ctx, userID, queryName, URL)
+	return err
+}
+
+func (s *Service) upsertSearchQuery(ctx context.Context, userID, queryName, URL string) (version *Version, isCreated bool, err error) {
+	// store a canonical form so that cosmetic URL changes do not look like a different query
+	canonicalURL, err := funda.CanonicalSearchURL(URL)
+	if err != nil {
+		s.log.Warn().Err(err).Str("userID", userID).Msg("failed to canonicalize search query, storing it as is")
+	} else {
+		URL = canonicalURL
+	}
+
+	tx, err := s.repository.Begin(ctx)
 	if err != nil {
-		s.log.Error().Err(err).Str("userID", userID).Msg("failed to get search query")
-		return URL, fmt.Errorf("failed to get search query: %w", err)
+		s.log.Error().Err(err).Msg("failed to begin a transaction")
+		return nil, false, fmt.Errorf("failed to begin a transaction: %w", err)
+	}
+
+	defer func(tx domain.Tx) {
+		errRb := tx.Rollback()
+		if errRb != nil && !errors.Is(errRb, sql.ErrTxDone) {
+			s.log.Error().Err(errRb).Msg("failed to rollback a transaction")
+		}
+	}(tx)
+
+	query := SearchQuery{
+		UserID:   userID,
+		Name:     NormalizeName(queryName),
+		URL:      URL,
+		IsActive: true,
 	}
 
-	return URL, nil
+	version, err = s.repository.GetLatestSearchQueryVersionByUserIDAndNameTx(ctx, tx, userID, query.Name)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		s.log.Error().Err(err).Str("userID", userID).Str("queryName", query.Name).Msg("failed to get latest search query version")
+		return nil, false, fmt.Errorf("failed to get latest search query version: %w", err)
+	}
+	if version == nil || version.URL != URL {
+		newVersion := Version{
+			UserID:    userID,
+			QueryName: query.Name,
+			Number:    1,
+			URL:       URL,
+			CreatedAt: time.Now().UTC(),
+		}
+		if version != nil {
+			newVersion.Number = version.Number + 1
+		}
+		if err = s.repository.InsertSearchQueryVersionTx(ctx, tx, &newVersion); err != nil {
+			s.log.Error().Err(err).Str("userID", userID).Str("queryName", query.Name).Msg("failed to insert search query version")
+			return nil, false, fmt.Errorf("failed to insert search query version: %w", err)
+		}
+		version = &newVersion
+		isCreated = true
+	}
+	query.Version = version.Number
+
+	if err = s.repository.UpsertSearchQueryTx(ctx, tx, &query); err != nil {
+		s.log.Error().Err(err).Str("userID", userID).Str("queryName", query.Name).Msg("failed to upsert search query")
+		return nil, false, fmt.Errorf("failed to upsert search query: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		s.log.Error().Err(err).Msg("failed to commit a transaction")
+		return nil, false, fmt.Errorf("failed to commit a transaction: %w", err)
+	}
+
+	return version, isCreated, nil
 }
 
-func (s *Service) DeleteSearchQueryByUserIDTx(ctx context.Context, tx domain.Tx, userID string) error {
-	err := s.repository.DeleteSearchQueryByUserIDTx(ctx, tx, userID)
+// MGetSearchQueryVersion returns the query history, newest first, along with the number of stored listings each
+// version has found.
+func (s *Service) MGetSearchQueryVersion(ctx context.Context, userID, queryName string) (Versions, error) {
+	queryName = NormalizeName(queryName)
+	versions, err := s.repository.MGetSearchQueryVersionByUserIDAndName(ctx, userID, queryName)
 	if err != nil {
-		s.log.Error().Err(err).Str("userID", userID).Msg("failed to delete search query")
+		s.log.Error().Err(err).Str("userID", userID).Str("queryName", queryName).Msg("failed to get search query versions")
+		return nil, fmt.Errorf("failed to get search query versions: %w", err)
+	}
+
+	counts, err := s.listingsService.CountListingByQueryVersion(ctx, userID, queryName)
+	if err != nil {
+		s.log.Error().Err(err).Str("userID", userID).Str("queryName", queryName).Msg("failed to count listings by query version")
+		return nil, fmt.Errorf("failed to count listings by query version: %w", err)
+	}
+	for idx := range versions {
+		versions[idx].ListingsCount = counts[versions[idx].Number]
+	}
+
+	return versions, nil
+}
+
+// RestoreSearchQueryVersion sets the URL of an earlier version back, the restoration itself is recorded as a new
+// version so that the history is never rewritten. Current is nil when the restored URL is already the current one.
+func (s *Service) RestoreSearchQueryVersion(ctx context.Context, userID, queryName string, number int) (restored, current *Version, err error) {
+	queryName = NormalizeName(queryName)
+	restored, err = s.repository.GetSearchQueryVersionByUserIDAndNameAndNumber(ctx, userID, queryName, number)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			s.log.Error().Err(err).Str("userID", userID).Str("queryName", queryName).Int("version", number).Msg("failed to get search query version")
+		}
+		return nil, nil, fmt.Errorf("failed to get search query version: %w", err)
+	}
+
+	current, isCreated, err := s.upsertSearchQuery(ctx, userID, queryName, restored.URL)
+	if err != nil {
+		s.log.Error().Err(err).Str("userID", userID).Str("queryName", queryName).Int("version", number).Msg("failed to restore search query version")
+		return nil, nil, fmt.Errorf("failed to restore search query version: %w", err)
+	}
+
+	if !isCreated {
+		return restored, nil, nil
+	}
+
+	return restored, current, nil
+}
+
+func (s *Service) GetSearchQueryByName(ctx context.Context, userID, queryName string) (*SearchQuery, error) {
+	query, err := s.repository.GetSearchQueryByUserIDAndName(ctx, userID, NormalizeName(queryName))
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			s.log.Error().Err(err).Str("userID", userID).Str("queryName", queryName).Msg("failed to get search query")
+		}
+		return nil, fmt.Errorf("failed to get search query: %w", err)
+	}
+
+	return query, nil
+}
+
+func (s *Service) MGetSearchQueryByUserID(ctx context.Context, userID string) (SearchQueries, error) {
+	queries, err := s.repository.MGetSearchQueryByUserID(ctx, userID)
+	if err != nil {
+		s.log.Error().Err(err).Str("userID", userID).Msg("failed to get search queries")
+		return nil, fmt.Errorf("failed to get search queries: %w", err)
+	}
+
+	return queries, nil
+}
+
+func (s *Service) SetSearchQueryActive(ctx context.Context, userID, queryName string, isActive bool) error {
+	err := s.repository.UpdateSearchQueryIsActiveByUserIDAndName(ctx, userID, NormalizeName(queryName), isActive)
+	if err != nil {
+		s.log.Error().Err(err).Str("userID", userID).Str("queryName", queryName).Msg("failed to update search query state")
+		return fmt.Errorf("failed to update search query state: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Service) UpdateSearchQueryFilters(ctx context.Context, userID, queryName, regions, cities string) error {
+	query := SearchQuery{RegionsRaw: regions, CitiesRaw: cities}
+	query.ParseRawRegionsAndCities()
+
+	err := s.repository.UpdateSearchQueryFiltersByUserIDAndName(ctx, userID, NormalizeName(queryName), query.RegionsRaw, query.CitiesRaw)
+	if err != nil {
+		s.log.Error().Err(err).Str("userID", userID).Str("queryName", queryName).Msg("failed to update search query filters")
+		return fmt.Errorf("failed to update search query filters: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Service) RemoveSearchQuery(ctx context.Context, userID, queryName string) error {
+	queryName = NormalizeName(queryName)
+
+	tx, err := s.repository.Begin(ctx)
+	if err != nil {
+		s.log.Error().Err(err).Msg("failed to begin a transaction")
+		return fmt.Errorf("failed to begin a transaction: %w", err)
+	}
+
+	defer func(tx domain.Tx) {
+		errRb := tx.Rollback()
+		if errRb != nil && !errors.Is(errRb, sql.ErrTxDone) {
+			s.log.Error().Err(errRb).Msg("failed to rollback a transaction")
+		}
+	}(tx)
+
+	if err = s.repository.DeleteSearchQueryByUserIDAndNameTx(ctx, tx, userID, queryName); err != nil {
+		s.log.Error().Err(err).Str("userID", userID).Str("queryName", queryName).Msg("failed to delete search query")
 		return fmt.Errorf("failed to delete search query: %w", err)
 	}
 
+	if err = s.repository.MDeleteSearchQueryVersionByUserIDAndNameTx(ctx, tx, userID, queryName); err != nil {
+		s.log.Error().Err(err).Str("userID", userID).Str("queryName", queryName).Msg("failed to delete search query versions")
+		return fmt.Errorf("failed to delete search query versions: %w", err)
+	}
+
+	if err = s.listingsService.MDeleteListingByUserIDAndQueryNameTx(ctx, tx, userID, queryName); err != nil {
+		s.log.Error().Err(err).Str("userID", userID).Str("queryName", queryName).Msg("failed to delete search query listings")
+		return fmt.Errorf("failed to delete search query listings: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		s.log.Error().Err(err).Msg("failed to commit a transaction")
+		return fmt.Errorf("failed to commit a transaction: %w", err)
+	}
+
 	return nil
 }
 
-func (s *Service) UpsertSearchQueryByUserID(ctx context.Context, userID, searchQuery string) error {
-	err := s.repository.UpsertSearchQueryByUserID(ctx, userID, searchQuery)
+func (s *Service) DeleteSearchQueryByUserIDTx(ctx context.Context, tx domain.Tx, userID string) error {
+	err := s.repository.DeleteSearchQueryByUserIDTx(ctx, tx, userID)
+	if err != nil {
+		s.log.Error().Err(err).Str("userID", userID).Msg("failed to delete search query")
+		return fmt.Errorf("failed to delete search query: %w", err)
+	}
+
+	err = s.repository.MDeleteSearchQueryVersionByUserIDTx(ctx, tx, userID)
 	if err != nil {
-		s.log.Error().Err(err).Str("userID", userID).Msg("failed to upsert search query")
-		return fmt.Errorf("failed to upsert search query: %w", err)
+		s.log.Error().Err(err).Str("userID", userID).Msg("failed to delete search query versions")
+		return fmt.Errorf("failed to delete search query versions: %w", err)
 	}
 
 	return nil
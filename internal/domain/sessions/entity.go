@@ -1,10 +1,21 @@
 package sessions
 
 import (
+	"fundaNotifier/internal/pkg/cron"
 	"strings"
+	"sync"
 	"time"
 )
 
+const (
+	maxScheduleLookback = 7 * 24 * time.Hour
+	defaultTimezone     = "UTC"
+)
+
+// locations caches loaded timezones by name, loading one reads the timezone database and sessions render timestamps
+// all the time
+var locations sync.Map
+
 type Session struct {
 	UserID                   string
 	ChatID                   int64
@@ -17,8 +28,9 @@ type Session struct {
 	LastSyncedAt             time.Time
 	SyncCountSinceLastChange int
 	DNDActive                bool
-	DNDStart                 int
-	DNDEnd                   int
+	DNDQueue                 bool
+	Schedule                 string
+	Timezone                 string
 }
 
 func (s *Session) ParseRawRegionsAndCities() {
@@ -41,18 +53,36 @@ func (s *Session) ParseRawRegionsAndCities() {
 	}
 }
 
-func (s *Session) IsWithinDND() bool {
-	if !s.DNDActive {
-		return false
+func (s *Session) Location() *time.Location {
+	if s.Timezone == "" || s.Timezone == defaultTimezone {
+		return time.UTC
 	}
+	if loc, ok := locations.Load(s.Timezone); ok {
+		return loc.(*time.Location)
+	}
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	locations.Store(s.Timezone, loc)
+	return loc
+}
 
-	nowTs := time.Now().UTC()
-	minutes := nowTs.Hour()*60 + nowTs.Minute()
-
-	if s.DNDStart < s.DNDEnd {
-		return minutes >= s.DNDStart && minutes < s.DNDEnd
+func (s *Session) IsDueForSync(nowTs time.Time) bool {
+	if s.Schedule != "" {
+		schedule, err := cron.Parse(s.Schedule)
+		if err == nil {
+			from := s.LastSyncedAt
+			if from.Before(nowTs.Add(-maxScheduleLookback)) {
+				from = nowTs.Add(-maxScheduleLookback)
+			}
+			nextRun := schedule.Next(from.In(s.Location()))
+			return !nextRun.IsZero() && !nextRun.After(nowTs)
+		}
 	}
-	return minutes >= s.DNDStart || minutes < s.DNDEnd
+
+	interval := time.Duration(s.UpdateIntervalSeconds) * time.Second
+	return s.LastSyncedAt.Add(interval).Before(nowTs)
 }
 
 type Sessions []Session
@@ -62,11 +92,10 @@ func (s *Sessions) SelectForSync() Sessions {
 		return nil
 	}
 
+	nowTs := time.Now()
 	result := make(Sessions, 0, len(*s))
 	for idx := range *s {
-		interval := time.Duration((*s)[idx].UpdateIntervalSeconds) * time.Second
-		lastSyncedAt := (*s)[idx].LastSyncedAt
-		if lastSyncedAt.Add(interval).Before(time.Now()) {
+		if (*s)[idx].IsDueForSync(nowTs) {
 			result = append(result, (*s)[idx])
 		}
 	}
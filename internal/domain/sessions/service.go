@@ -15,32 +15,90 @@ import (
 type ListingsService interface {
 	MDeleteListingByUserIDTx(ctx context.Context, tx domain.Tx, userID string) error
 	MDeleteFavoriteListingByUserIDTx(ctx context.Context, tx domain.Tx, userID string) error
+	MDeletePricePointByUserIDTx(ctx context.Context, tx domain.Tx, userID string) error
+	MDeleteStageEventByUserIDTx(ctx context.Context, tx domain.Tx, userID string) error
+	MDeleteSeenListingByUserIDTx(ctx context.Context, tx domain.Tx, userID string) error
 }
 
 type SearchQueriesService interface {
-	GetSearchQuery(ctx context.Context, userID string) (URL string, err error)
 	DeleteSearchQueryByUserIDTx(ctx context.Context, tx domain.Tx, userID string) error
-	UpsertSearchQueryByUserID(ctx context.Context, userID, searchQuery string) error
+}
+
+type DNDWindowsService interface {
+	MDeleteWindowByUserIDTx(ctx context.Context, tx domain.Tx, userID string) error
+}
+
+type OutboxService interface {
+	MDeleteMessageByUserIDTx(ctx context.Context, tx domain.Tx, userID string) error
+}
+
+type UrgentRulesService interface {
+	DeleteRuleByUserIDTx(ctx context.Context, tx domain.Tx, userID string) error
+}
+
+type WatchedListingsService interface {
+	MDeleteWatchedListingByUserIDTx(ctx context.Context, tx domain.Tx, userID string) error
+}
+
+type BlacklistService interface {
+	MDeleteByUserIDTx(ctx context.Context, tx domain.Tx, userID string) error
+}
+
+type ViewingsService interface {
+	MDeleteViewingByUserIDTx(ctx context.Context, tx domain.Tx, userID string) error
+}
+
+type ScoringService interface {
+	DeleteProfileByUserIDTx(ctx context.Context, tx domain.Tx, userID string) error
+}
+
+type PreferencesService interface {
+	DeletePreferencesByUserIDTx(ctx context.Context, tx domain.Tx, userID string) error
+	ForgetModel(userID string)
 }
 
 type Service struct {
-	repository           Repository
-	listingsService      ListingsService
-	searchQueriesService SearchQueriesService
-	log                  *zerolog.Logger
+	repository             Repository
+	listingsService        ListingsService
+	searchQueriesService   SearchQueriesService
+	dndWindowsService      DNDWindowsService
+	outboxService          OutboxService
+	urgentRulesService     UrgentRulesService
+	watchedListingsService WatchedListingsService
+	blacklistService       BlacklistService
+	viewingsService        ViewingsService
+	scoringService         ScoringService
+	preferencesService     PreferencesService
+	log                    *zerolog.Logger
 }
 
 func NewService(
 	repository Repository,
 	listingsService ListingsService,
 	searchQueriesService SearchQueriesService,
+	dndWindowsService DNDWindowsService,
+	outboxService OutboxService,
+	urgentRulesService UrgentRulesService,
+	watchedListingsService WatchedListingsService,
+	blacklistService BlacklistService,
+	viewingsService ViewingsService,
+	scoringService ScoringService,
+	preferencesService PreferencesService,
 	log *zerolog.Logger,
 ) *Service {
 	return &Service{
-		repository:           repository,
-		listingsService:      listingsService,
-		searchQueriesService: searchQueriesService,
-		log:                  log,
+		repository:             repository,
+		listingsService:        listingsService,
+		searchQueriesService:   searchQueriesService,
+		dndWindowsService:      dndWindowsService,
+		outboxService:          outboxService,
+		urgentRulesService:     urgentRulesService,
+		watchedListingsService: watchedListingsService,
+		blacklistService:       blacklistService,
+		viewingsService:        viewingsService,
+		scoringService:         scoringService,
+		preferencesService:     preferencesService,
+		log:                    log,
 	}
 }
 
@@ -113,7 +171,7 @@ func (s *Service) MGetSession(ctx context.Context, onlyActive bool) (Sessions, e
 	return activeSessions, nil
 }
 
-func (s *Service) SetDNDSchedule(ctx context.Context, userID string, DNDStart, DNDEnd int) error {
+func (s *Service) ActivateDND(ctx context.Context, userID string) error {
 	tx, err := s.repository.Begin(ctx)
 	if err != nil {
 		s.log.Error().Err(err).Msg("failed to begin a transaction")
@@ -133,8 +191,7 @@ func (s *Service) SetDNDSchedule(ctx context.Context, userID string, DNDStart, D
 		return fmt.Errorf("failed to get session for update: %w", err)
 	}
 
-	session.DNDStart = DNDStart
-	session.DNDEnd = DNDEnd
+	session.DNDActive = true
 
 	if err = s.UpdateSessionByUserIDTx(ctx, tx, session); err != nil {
 		s.log.Error().Err(err).Msg("failed to update session")
@@ -149,7 +206,7 @@ func (s *Service) SetDNDSchedule(ctx context.Context, userID string, DNDStart, D
 	return nil
 }
 
-func (s *Service) ActivateDND(ctx context.Context, userID string) error {
+func (s *Service) DeactivateDND(ctx context.Context, userID string) error {
 	tx, err := s.repository.Begin(ctx)
 	if err != nil {
 		s.log.Error().Err(err).Msg("failed to begin a transaction")
@@ -169,7 +226,7 @@ func (s *Service) ActivateDND(ctx context.Context, userID string) error {
 		return fmt.Errorf("failed to get session for update: %w", err)
 	}
 
-	session.DNDActive = true
+	session.DNDActive = false
 
 	if err = s.UpdateSessionByUserIDTx(ctx, tx, session); err != nil {
 		s.log.Error().Err(err).Msg("failed to update session")
@@ -184,7 +241,7 @@ func (s *Service) ActivateDND(ctx context.Context, userID string) error {
 	return nil
 }
 
-func (s *Service) DeactivateDND(ctx context.Context, userID string) error {
+func (s *Service) UpdateDNDQueue(ctx context.Context, userID string, queue bool) error {
 	tx, err := s.repository.Begin(ctx)
 	if err != nil {
 		s.log.Error().Err(err).Msg("failed to begin a transaction")
@@ -204,7 +261,7 @@ func (s *Service) DeactivateDND(ctx context.Context, userID string) error {
 		return fmt.Errorf("failed to get session for update: %w", err)
 	}
 
-	session.DNDActive = false
+	session.DNDQueue = queue
 
 	if err = s.UpdateSessionByUserIDTx(ctx, tx, session); err != nil {
 		s.log.Error().Err(err).Msg("failed to update session")
@@ -326,6 +383,77 @@ func (s *Service) UpdatePollingInterval(ctx context.Context, userID string, poll
 	return nil
 }
 
+func (s *Service) UpdateSchedule(ctx context.Context, userID string, schedule string) error {
+	tx, err := s.repository.Begin(ctx)
+	if err != nil {
+		s.log.Error().Err(err).Msg("failed to begin a transaction")
+		return fmt.Errorf("failed to begin a transaction: %w", err)
+	}
+
+	defer func(tx domain.Tx) {
+		errRb := tx.Rollback()
+		if errRb != nil && !errors.Is(errRb, sql.ErrTxDone) {
+			s.log.Error().Err(errRb).Msg("failed to rollback a transaction")
+		}
+	}(tx)
+
+	session, err := s.GetSessionByUserIDTx(ctx, tx, userID)
+	if err != nil {
+		s.log.Error().Err(err).Msg("failed to get session for update")
+		return fmt.Errorf("failed to get session for update: %w", err)
+	}
+
+	session.Schedule = schedule
+	session.SyncCountSinceLastChange = 0
+
+	if err = s.UpdateSessionByUserIDTx(ctx, tx, session); err != nil {
+		s.log.Error().Err(err).Msg("failed to update session")
+		return fmt.Errorf("failed to update session: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		s.log.Error().Err(err).Msg("failed to commit a transaction")
+		return fmt.Errorf("failed to commit a transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Service) UpdateTimezone(ctx context.Context, userID string, timezone string) error {
+	tx, err := s.repository.Begin(ctx)
+	if err != nil {
+		s.log.Error().Err(err).Msg("failed to begin a transaction")
+		return fmt.Errorf("failed to begin a transaction: %w", err)
+	}
+
+	defer func(tx domain.Tx) {
+		errRb := tx.Rollback()
+		if errRb != nil && !errors.Is(errRb, sql.ErrTxDone) {
+			s.log.Error().Err(errRb).Msg("failed to rollback a transaction")
+		}
+	}(tx)
+
+	session, err := s.GetSessionByUserIDTx(ctx, tx, userID)
+	if err != nil {
+		s.log.Error().Err(err).Msg("failed to get session for update")
+		return fmt.Errorf("failed to get session for update: %w", err)
+	}
+
+	session.Timezone = timezone
+
+	if err = s.UpdateSessionByUserIDTx(ctx, tx, session); err != nil {
+		s.log.Error().Err(err).Msg("failed to update session")
+		return fmt.Errorf("failed to update session: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		s.log.Error().Err(err).Msg("failed to commit a transaction")
+		return fmt.Errorf("failed to commit a transaction: %w", err)
+	}
+
+	return nil
+}
+
 func (s *Service) UpdateRegions(ctx context.Context, userID string, regions string) error {
 	regions = strings.ToLower(regions)
 
@@ -542,11 +670,66 @@ func (s *Service) RemoveEverythingByUserID(ctx context.Context, userID string) e
 		return fmt.Errorf("failed to delete listings upon deletion request: %w", err)
 	}
 
+	if err = s.listingsService.MDeletePricePointByUserIDTx(ctx, tx, userID); err != nil {
+		s.log.Error().Err(err).Msg("failed to delete price history upon deletion request")
+		return fmt.Errorf("failed to delete price history upon deletion request: %w", err)
+	}
+
+	if err = s.listingsService.MDeleteStageEventByUserIDTx(ctx, tx, userID); err != nil {
+		s.log.Error().Err(err).Msg("failed to delete pipeline events upon deletion request")
+		return fmt.Errorf("failed to delete pipeline events upon deletion request: %w", err)
+	}
+
+	if err = s.listingsService.MDeleteSeenListingByUserIDTx(ctx, tx, userID); err != nil {
+		s.log.Error().Err(err).Msg("failed to delete seen listings upon deletion request")
+		return fmt.Errorf("failed to delete seen listings upon deletion request: %w", err)
+	}
+
 	if err = s.searchQueriesService.DeleteSearchQueryByUserIDTx(ctx, tx, userID); err != nil {
 		s.log.Error().Err(err).Msg("failed to delete search query upon deletion request")
 		return fmt.Errorf("failed to delete search query upon deletion request: %w", err)
 	}
 
+	if err = s.dndWindowsService.MDeleteWindowByUserIDTx(ctx, tx, userID); err != nil {
+		s.log.Error().Err(err).Msg("failed to delete DND windows upon deletion request")
+		return fmt.Errorf("failed to delete DND windows upon deletion request: %w", err)
+	}
+
+	if err = s.outboxService.MDeleteMessageByUserIDTx(ctx, tx, userID); err != nil {
+		s.log.Error().Err(err).Msg("failed to delete held messages upon deletion request")
+		return fmt.Errorf("failed to delete held messages upon deletion request: %w", err)
+	}
+
+	if err = s.urgentRulesService.DeleteRuleByUserIDTx(ctx, tx, userID); err != nil {
+		s.log.Error().Err(err).Msg("failed to delete urgent rule upon deletion request")
+		return fmt.Errorf("failed to delete urgent rule upon deletion request: %w", err)
+	}
+
+	if err = s.watchedListingsService.MDeleteWatchedListingByUserIDTx(ctx, tx, userID); err != nil {
+		s.log.Error().Err(err).Msg("failed to delete watched listings upon deletion request")
+		return fmt.Errorf("failed to delete watched listings upon deletion request: %w", err)
+	}
+
+	if err = s.blacklistService.MDeleteByUserIDTx(ctx, tx, userID); err != nil {
+		s.log.Error().Err(err).Msg("failed to delete hidden listings and blacklist rules upon deletion request")
+		return fmt.Errorf("failed to delete hidden listings and blacklist rules upon deletion request: %w", err)
+	}
+
+	if err = s.viewingsService.MDeleteViewingByUserIDTx(ctx, tx, userID); err != nil {
+		s.log.Error().Err(err).Msg("failed to delete viewings upon deletion request")
+		return fmt.Errorf("failed to delete viewings upon deletion request: %w", err)
+	}
+
+	if err = s.scoringService.DeleteProfileByUserIDTx(ctx, tx, userID); err != nil {
+		s.log.Error().Err(err).Msg("failed to delete scoring profile upon deletion request")
+		return fmt.Errorf("failed to delete scoring profile upon deletion request: %w", err)
+	}
+
+	if err = s.preferencesService.DeletePreferencesByUserIDTx(ctx, tx, userID); err != nil {
+		s.log.Error().Err(err).Msg("failed to delete preferences upon deletion request")
+		return fmt.Errorf("failed to delete preferences upon deletion request: %w", err)
+	}
+
 	if err = s.DeleteSessionByUserIDTx(ctx, tx, userID); err != nil {
 		s.log.Error().Err(err).Msg("failed to delete session upon deletion request")
 		return fmt.Errorf("failed to delete session upon deletion request: %w", err)
@@ -556,6 +739,7 @@ func (s *Service) RemoveEverythingByUserID(ctx context.Context, userID string) e
 		s.log.Error().Err(err).Msg("failed to commit a transaction")
 		return fmt.Errorf("failed to commit a transaction: %w", err)
 	}
+	s.preferencesService.ForgetModel(userID)
 
 	return nil
 }
@@ -0,0 +1,71 @@
+package urgent_rules
+
+import (
+	"fundaNotifier/internal/domain/listings"
+	"strings"
+	"time"
+)
+
+const sentOnLayout = "2006-01-02"
+
+type Rule struct {
+	UserID     string
+	RegionsRaw string
+	Regions    []string
+	CitiesRaw  string
+	Cities     []string
+	MaxPrice   float64
+	DailyCap   int
+	SentCount  int
+	SentOn     string
+}
+
+func (r *Rule) ParseRawRegionsAndCities() {
+	r.Regions = splitUnique(r.RegionsRaw)
+	r.RegionsRaw = strings.Join(r.Regions, ",")
+	r.Cities = splitUnique(r.CitiesRaw)
+	r.CitiesRaw = strings.Join(r.Cities, ",")
+}
+
+// Filter returns listings matching the rule, using the same region and city semantics as session filters.
+func (r *Rule) Filter(l listings.Listings) listings.Listings {
+	filteredListings := l.FilterByRegionsAndCities(r.Regions, r.Cities)
+	if r.MaxPrice <= 0 {
+		return filteredListings
+	}
+
+	result := make(listings.Listings, 0, len(filteredListings))
+	for idx := range filteredListings {
+		if filteredListings[idx].Offers.Price > 0 && filteredListings[idx].Offers.Price <= r.MaxPrice {
+			result = append(result, filteredListings[idx])
+		}
+	}
+	return result
+}
+
+// RemainingOn returns how many urgent alerts can still be sent on the given day (formatted as YYYY-MM-DD).
+func (r *Rule) RemainingOn(day string) int {
+	if r.SentOn != day {
+		return r.DailyCap
+	}
+	return max(r.DailyCap-r.SentCount, 0)
+}
+
+// Day returns the day key of ts, which must be in the user's location.
+func Day(ts time.Time) string {
+	return ts.Format(sentOnLayout)
+}
+
+func splitUnique(raw string) []string {
+	encountered := make(map[string]bool)
+	result := []string{}
+	for _, s := range strings.Split(strings.ToLower(raw), ",") {
+		s = strings.TrimSpace(s)
+		if s == "" || encountered[s] {
+			continue
+		}
+		encountered[s] = true
+		result = append(result, s)
+	}
+	return result
+}
@@ -0,0 +1,14 @@
+package urgent_rules
+
+import (
+	"context"
+	"fundaNotifier/internal/domain"
+)
+
+type Repository interface {
+	UpsertRule(ctx context.Context, rule *Rule) error
+	GetRuleByUserID(ctx context.Context, userID string) (*Rule, error)
+	UpdateSentCountByUserID(ctx context.Context, userID, sentOn string, sentCount int) error
+	DeleteRuleByUserID(ctx context.Context, userID string) error
+	DeleteRuleByUserIDTx(ctx context.Context, tx domain.Tx, userID string) error
+}
@@ -0,0 +1,95 @@
+package urgent_rules
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"fundaNotifier/internal/domain"
+
+	"github.com/rs/zerolog"
+)
+
+type Service struct {
+	repository Repository
+	log        *zerolog.Logger
+}
+
+func NewService(
+	repository Repository,
+	log *zerolog.Logger,
+) *Service {
+	return &Service{
+		repository: repository,
+		log:        log,
+	}
+}
+
+func (s *Service) SetRule(ctx context.Context, userID, regions, cities string, maxPrice float64, dailyCap int) error {
+	rule := Rule{
+		UserID:     userID,
+		RegionsRaw: regions,
+		CitiesRaw:  cities,
+		MaxPrice:   maxPrice,
+		DailyCap:   dailyCap,
+	}
+	rule.ParseRawRegionsAndCities()
+
+	err := s.repository.UpsertRule(ctx, &rule)
+	if err != nil {
+		s.log.Error().Err(err).Str("userID", userID).Msg("failed to upsert urgent rule")
+		return fmt.Errorf("failed to upsert urgent rule: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Service) GetRuleByUserID(ctx context.Context, userID string) (*Rule, error) {
+	rule, err := s.repository.GetRuleByUserID(ctx, userID)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			s.log.Error().Err(err).Str("userID", userID).Msg("failed to get urgent rule")
+		}
+		return nil, fmt.Errorf("failed to get urgent rule: %w", err)
+	}
+
+	return rule, nil
+}
+
+// RegisterSentAlerts accounts for alerts sent on the given day, resetting the counter when the day changes.
+func (s *Service) RegisterSentAlerts(ctx context.Context, rule *Rule, day string, count int) error {
+	sentCount := count
+	if rule.SentOn == day {
+		sentCount += rule.SentCount
+	}
+
+	err := s.repository.UpdateSentCountByUserID(ctx, rule.UserID, day, sentCount)
+	if err != nil {
+		s.log.Error().Err(err).Str("userID", rule.UserID).Msg("failed to update urgent alerts count")
+		return fmt.Errorf("failed to update urgent alerts count: %w", err)
+	}
+
+	rule.SentOn = day
+	rule.SentCount = sentCount
+	return nil
+}
+
+func (s *Service) RemoveRule(ctx context.Context, userID string) error {
+	err := s.repository.DeleteRuleByUserID(ctx, userID)
+	if err != nil {
+		s.log.Error().Err(err).Str("userID", userID).Msg("failed to delete urgent rule")
+		return fmt.Errorf("failed to delete urgent rule: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Service) DeleteRuleByUserIDTx(ctx context.Context, tx domain.Tx, userID string) error {
+	err := s.repository.DeleteRuleByUserIDTx(ctx, tx, userID)
+	if err != nil {
+		s.log.Error().Err(err).Str("userID", userID).Msg("failed to delete urgent rule")
+		return fmt.Errorf("failed to delete urgent rule: %w", err)
+	}
+
+	return nil
+}
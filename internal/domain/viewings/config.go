@@ -0,0 +1,8 @@
+package viewings
+
+import "time"
+
+type Config struct {
+	// ReminderBefore is how long before a viewing a reminder is sent unless set for the viewing itself
+	ReminderBefore time.Duration `env:"VIEWINGS_REMINDER_BEFORE" env-default:"2h"`
+}
@@ -0,0 +1,57 @@
+package viewings
+
+import (
+	"fundaNotifier/internal/pkg/ical"
+	"time"
+)
+
+// eventDuration is the length of a viewing in calendar exports, Funda does not tell how long a viewing takes.
+const eventDuration = 30 * time.Minute
+
+// Viewing is a scheduled visit of a favorite listing, a favorite has at most one viewing.
+type Viewing struct {
+	UserID     string
+	ObjectID   string
+	Name       string
+	Address    string
+	URL        string
+	StartsAt   time.Time
+	RemindAt   time.Time
+	IsReminded bool
+	CreatedAt  time.Time
+}
+
+// IsDue reports whether a reminder for the viewing has to be sent, reminders are not sent for past viewings.
+func (v *Viewing) IsDue(now time.Time) bool {
+	return !v.IsReminded && !v.RemindAt.After(now) && v.StartsAt.After(now)
+}
+
+type Viewings []Viewing
+
+// Upcoming returns viewings which have not started yet.
+func (v Viewings) Upcoming(now time.Time) Viewings {
+	result := make(Viewings, 0, len(v))
+	for idx := range v {
+		if v[idx].StartsAt.After(now) {
+			result = append(result, v[idx])
+		}
+	}
+	return result
+}
+
+// ICS renders viewings as an iCalendar document, stamp is the time of the export.
+func (v Viewings) ICS(stamp time.Time) []byte {
+	events := make([]ical.Event, 0, len(v))
+	for idx := range v {
+		events = append(events, ical.Event{
+			UID:         v[idx].UserID + "-" + v[idx].ObjectID + "@fundanotifier",
+			Start:       v[idx].StartsAt,
+			End:         v[idx].StartsAt.Add(eventDuration),
+			Summary:     "Viewing: " + v[idx].Name,
+			Location:    v[idx].Address,
+			Description: v[idx].URL,
+			URL:         v[idx].URL,
+		})
+	}
+	return ical.Encode(events, stamp)
+}
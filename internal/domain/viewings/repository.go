@@ -0,0 +1,15 @@
+package viewings
+
+import (
+	"context"
+	"fundaNotifier/internal/domain"
+)
+
+type Repository interface {
+	UpsertViewing(ctx context.Context, viewing *Viewing) error
+	MGetViewingByUserID(ctx context.Context, userID string) (Viewings, error)
+	UpdateViewingIsReminded(ctx context.Context, viewing *Viewing) error
+	DeleteViewingByUserIDAndObjectID(ctx context.Context, userID, objectID string) error
+	DeleteViewingByUserIDAndObjectIDTx(ctx context.Context, tx domain.Tx, userID, objectID string) error
+	MDeleteViewingByUserIDTx(ctx context.Context, tx domain.Tx, userID string) error
+}
@@ -0,0 +1,135 @@
+package viewings
+
+import (
+	"context"
+	"fmt"
+	"fundaNotifier/internal/domain"
+	"fundaNotifier/internal/domain/listings"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+type Service struct {
+	repository Repository
+	cfg        *Config
+	log        *zerolog.Logger
+}
+
+func NewService(
+	repository Repository,
+	cfg *Config,
+	log *zerolog.Logger,
+) *Service {
+	return &Service{
+		repository: repository,
+		cfg:        cfg,
+		log:        log,
+	}
+}
+
+// Schedule records a viewing of a favorite listing replacing a previous one, a zero remindBefore falls back to the
+// configured default.
+func (s *Service) Schedule(ctx context.Context, favorite *listings.Listing, startsAt time.Time, remindBefore time.Duration) (*Viewing, error) {
+	if remindBefore == 0 {
+		remindBefore = s.cfg.ReminderBefore
+	}
+
+	address := make([]string, 0, 2)
+	for _, part := range []string{favorite.Address.StreetAddress, favorite.Address.AddressLocality} {
+		if part != "" {
+			address = append(address, part)
+		}
+	}
+
+	viewing := Viewing{
+		UserID:    favorite.UserID,
+		ObjectID:  favorite.ObjectID,
+		Name:      favorite.Name,
+		Address:   strings.Join(address, ", "),
+		URL:       favorite.URL,
+		StartsAt:  startsAt.UTC(),
+		RemindAt:  startsAt.Add(-remindBefore).UTC(),
+		CreatedAt: time.Now().UTC(),
+	}
+
+	err := s.repository.UpsertViewing(ctx, &viewing)
+	if err != nil {
+		s.log.Error().Err(err).Str("userID", favorite.UserID).Str("objectID", favorite.ObjectID).Msg("failed to upsert viewing")
+		return nil, fmt.Errorf("failed to upsert viewing: %w", err)
+	}
+
+	return &viewing, nil
+}
+
+func (s *Service) Cancel(ctx context.Context, userID, objectID string) error {
+	err := s.repository.DeleteViewingByUserIDAndObjectID(ctx, userID, objectID)
+	if err != nil {
+		s.log.Error().Err(err).Str("userID", userID).Str("objectID", objectID).Msg("failed to delete viewing")
+		return fmt.Errorf("failed to delete viewing: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Service) MGetUpcomingViewing(ctx context.Context, userID string) (Viewings, error) {
+	viewings, err := s.repository.MGetViewingByUserID(ctx, userID)
+	if err != nil {
+		s.log.Error().Err(err).Str("userID", userID).Msg("failed to get viewings")
+		return nil, fmt.Errorf("failed to get viewings: %w", err)
+	}
+
+	return viewings.Upcoming(time.Now()), nil
+}
+
+// MGetDueViewing returns viewings of a user a reminder is due for, they stay due until marked as reminded.
+func (s *Service) MGetDueViewing(ctx context.Context, userID string) (Viewings, error) {
+	viewings, err := s.repository.MGetViewingByUserID(ctx, userID)
+	if err != nil {
+		s.log.Error().Err(err).Str("userID", userID).Msg("failed to get viewings")
+		return nil, fmt.Errorf("failed to get viewings: %w", err)
+	}
+
+	now := time.Now()
+	dueViewings := make(Viewings, 0)
+	for idx := range viewings {
+		if viewings[idx].IsDue(now) {
+			dueViewings = append(dueViewings, viewings[idx])
+		}
+	}
+
+	return dueViewings, nil
+}
+
+// MarkReminded is called once a reminder of a viewing was sent, so that it is not sent again.
+func (s *Service) MarkReminded(ctx context.Context, viewing *Viewing) error {
+	viewing.IsReminded = true
+	err := s.repository.UpdateViewingIsReminded(ctx, viewing)
+	if err != nil {
+		s.log.Error().Err(err).Str("userID", viewing.UserID).Str("objectID", viewing.ObjectID).Msg("failed to mark viewing as reminded")
+		return fmt.Errorf("failed to mark viewing as reminded: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Service) DeleteViewingByUserIDAndObjectIDTx(ctx context.Context, tx domain.Tx, userID, objectID string) error {
+	err := s.repository.DeleteViewingByUserIDAndObjectIDTx(ctx, tx, userID, objectID)
+	if err != nil {
+		s.log.Error().Err(err).Str("userID", userID).Str("objectID", objectID).Msg("failed to delete viewing")
+		return fmt.Errorf("failed to delete viewing: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Service) MDeleteViewingByUserIDTx(ctx context.Context, tx domain.Tx, userID string) error {
+	err := s.repository.MDeleteViewingByUserIDTx(ctx, tx, userID)
+	if err != nil {
+		s.log.Error().Err(err).Str("userID", userID).Msg("failed to delete viewings")
+		return fmt.Errorf("failed to delete viewings: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,8 @@
+package watched_listings
+
+import "time"
+
+type Config struct {
+	// CheckInterval is how often watched listings are re-fetched to detect price and status changes
+	CheckInterval time.Duration `env:"WATCHED_LISTINGS_CHECK_INTERVAL" env-default:"6h"`
+}
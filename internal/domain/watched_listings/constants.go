@@ -0,0 +1,7 @@
+package watched_listings
+
+import "time"
+
+const (
+	checkInterval = time.Millisecond * 500
+)
@@ -0,0 +1,50 @@
+package watched_listings
+
+import (
+	"fundaNotifier/internal/domain/listings"
+	"time"
+)
+
+// WatchedListing is a single listing tracked independently of any search query.
+type WatchedListing struct {
+	UserID    string
+	ObjectID  string
+	URL       string
+	Name      string
+	City      string
+	Currency  string
+	Price     float64
+	Status    string
+	IsGone    bool
+	CreatedAt time.Time
+	CheckedAt time.Time
+}
+
+func (w *WatchedListing) SetListing(listing *listings.Listing) {
+	w.ObjectID = listing.ObjectID
+	w.URL = listing.URL
+	w.Name = listing.Name
+	w.City = listing.Address.AddressLocality
+	w.Currency = listing.Offers.PriceCurrency
+	w.Price = listing.Offers.Price
+	w.Status = listing.Status
+}
+
+// Listing returns the fields of a listing a watched listing keeps, so that its changes are reported like those of
+// favorites.
+func (w *WatchedListing) Listing() listings.Listing {
+	listing := listings.Listing{
+		UserID:   w.UserID,
+		ObjectID: w.ObjectID,
+		URL:      w.URL,
+		Name:     w.Name,
+		Status:   w.Status,
+		IsGone:   w.IsGone,
+	}
+	listing.Address.AddressLocality = w.City
+	listing.Offers.PriceCurrency = w.Currency
+	listing.Offers.Price = w.Price
+	return listing
+}
+
+type WatchedListings []WatchedListing
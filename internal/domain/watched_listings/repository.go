@@ -0,0 +1,14 @@
+package watched_listings
+
+import (
+	"context"
+	"fundaNotifier/internal/domain"
+)
+
+type Repository interface {
+	UpsertWatchedListing(ctx context.Context, listing *WatchedListing) error
+	MGetWatchedListingByUserID(ctx context.Context, userID string) (WatchedListings, error)
+	UpdateWatchedListing(ctx context.Context, listing *WatchedListing) error
+	DeleteWatchedListingByUserIDAndObjectID(ctx context.Context, userID, objectID string) error
+	MDeleteWatchedListingByUserIDTx(ctx context.Context, tx domain.Tx, userID string) error
+}
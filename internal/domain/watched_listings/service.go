@@ -0,0 +1,156 @@
+package watched_listings
+
+import (
+	"context"
+	"fmt"
+	"fundaNotifier/internal/domain"
+	"fundaNotifier/internal/domain/listings"
+	"fundaNotifier/internal/pkg/funda"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+type ListingsService interface {
+	GetListing(ctx context.Context, URL string) (*listings.Listing, error)
+}
+
+type Service struct {
+	repository      Repository
+	listingsService ListingsService
+	cfg             *Config
+	log             *zerolog.Logger
+}
+
+func NewService(
+	repository Repository,
+	listingsService ListingsService,
+	cfg *Config,
+	log *zerolog.Logger,
+) *Service {
+	return &Service{
+		repository:      repository,
+		listingsService: listingsService,
+		cfg:             cfg,
+		log:             log,
+	}
+}
+
+// Watch fetches a listing by URL and starts tracking it, watching an already watched listing refreshes it.
+func (s *Service) Watch(ctx context.Context, userID, URL string) (*WatchedListing, error) {
+	listing, err := s.listingsService.GetListing(ctx, URL)
+	if err != nil {
+		s.log.Error().Err(err).Str("userID", userID).Msg("failed to get listing to watch")
+		return nil, fmt.Errorf("failed to get listing to watch: %w", err)
+	}
+
+	now := time.Now().UTC()
+	watchedListing := WatchedListing{
+		UserID:    userID,
+		CreatedAt: now,
+		CheckedAt: now,
+	}
+	watchedListing.SetListing(listing)
+
+	err = s.repository.UpsertWatchedListing(ctx, &watchedListing)
+	if err != nil {
+		s.log.Error().Err(err).Str("userID", userID).Msg("failed to upsert watched listing")
+		return nil, fmt.Errorf("failed to upsert watched listing: %w", err)
+	}
+
+	return &watchedListing, nil
+}
+
+// Unwatch stops tracking a listing given either by its URL or by its object ID.
+func (s *Service) Unwatch(ctx context.Context, userID, listing string) error {
+	objectID := strings.TrimSpace(listing)
+	if strings.Contains(objectID, "/") {
+		var err error
+		objectID, err = funda.ObjectID(objectID)
+		if err != nil {
+			return fmt.Errorf("failed to get object ID: %w", err)
+		}
+	}
+
+	err := s.repository.DeleteWatchedListingByUserIDAndObjectID(ctx, userID, objectID)
+	if err != nil {
+		s.log.Error().Err(err).Str("userID", userID).Str("objectID", objectID).Msg("failed to delete watched listing")
+		return fmt.Errorf("failed to delete watched listing: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Service) MGetWatchedListingByUserID(ctx context.Context, userID string) (WatchedListings, error) {
+	watchedListings, err := s.repository.MGetWatchedListingByUserID(ctx, userID)
+	if err != nil {
+		s.log.Error().Err(err).Str("userID", userID).Msg("failed to get watched listings")
+		return nil, fmt.Errorf("failed to get watched listings: %w", err)
+	}
+
+	return watchedListings, nil
+}
+
+// CheckWatchedListings re-fetches watched listings which have not disappeared yet and were not checked within the check
+// interval, and returns what has changed. Listings which fail to load for reasons other than being taken off Funda or
+// fail to be updated are skipped until the next check.
+func (s *Service) CheckWatchedListings(ctx context.Context, userID string) (listings.Changes, error) {
+	watchedListings, err := s.repository.MGetWatchedListingByUserID(ctx, userID)
+	if err != nil {
+		s.log.Error().Err(err).Str("userID", userID).Msg("failed to get watched listings")
+		return nil, fmt.Errorf("failed to get watched listings: %w", err)
+	}
+
+	changes := make(listings.Changes, 0, len(watchedListings))
+	var isChecked bool
+	for idx := range watchedListings {
+		if watchedListings[idx].IsGone || time.Since(watchedListings[idx].CheckedAt) < s.cfg.CheckInterval {
+			continue
+		}
+		if isChecked {
+			time.Sleep(checkInterval)
+		}
+		isChecked = true
+
+		change := listings.Change{
+			Kind:      listings.KindWatched,
+			OldPrice:  watchedListings[idx].Price,
+			OldStatus: watchedListings[idx].Status,
+		}
+		listing, errGet := s.listingsService.GetListing(ctx, watchedListings[idx].URL)
+		switch {
+		case errGet != nil && listings.IsGone(errGet):
+			watchedListings[idx].IsGone = true
+		case errGet != nil:
+			s.log.Warn().Err(errGet).Str("userID", userID).Str("objectID", watchedListings[idx].ObjectID).Msg("failed to check watched listing")
+			continue
+		default:
+			watchedListings[idx].SetListing(listing)
+		}
+		watchedListings[idx].CheckedAt = time.Now().UTC()
+
+		// changes of the listings updated before are already stored, so they are reported regardless
+		if err = s.repository.UpdateWatchedListing(ctx, &watchedListings[idx]); err != nil {
+			s.log.Error().Err(err).Str("userID", userID).Str("objectID", watchedListings[idx].ObjectID).Msg("failed to update watched listing")
+			continue
+		}
+
+		change.Listing = watchedListings[idx].Listing()
+		if change.Listing.IsGone || change.IsPriceChanged() || change.IsStatusChanged() {
+			changes = append(changes, change)
+		}
+	}
+
+	return changes, nil
+}
+
+func (s *Service) MDeleteWatchedListingByUserIDTx(ctx context.Context, tx domain.Tx, userID string) error {
+	err := s.repository.MDeleteWatchedListingByUserIDTx(ctx, tx, userID)
+	if err != nil {
+		s.log.Error().Err(err).Str("userID", userID).Msg("failed to delete watched listings")
+		return fmt.Errorf("failed to delete watched listings: %w", err)
+	}
+
+	return nil
+}
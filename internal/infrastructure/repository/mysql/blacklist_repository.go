@@ -0,0 +1,191 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"fundaNotifier/internal/domain"
+	"fundaNotifier/internal/domain/blacklist"
+	"time"
+)
+
+var _ blacklist.Repository = (*BlacklistRepository)(nil)
+
+type BlacklistRepository struct {
+	*Repository
+}
+
+func NewBlacklistRepository(repository *Repository) *BlacklistRepository {
+	return &BlacklistRepository{
+		Repository: repository,
+	}
+}
+
+func (r *BlacklistRepository) UpsertHiddenListing(ctx context.Context, listing *blacklist.HiddenListing) error {
+	const name = "BlacklistRepository.UpsertHiddenListing"
+	ctx, cancel := context.WithTimeout(ctx, time.Second*defaultTimeoutSeconds)
+	defer cancel()
+
+	_, err := r.db.ExecContext(ctx, "INSERT INTO hidden_listings (user_id, object_id, name, url, created_at) VALUES (?, ?, ?, ?, ?) ON CONFLICT (user_id, object_id) DO UPDATE SET name = excluded.name, url = excluded.url, created_at = excluded.created_at;", listing.UserID, listing.ObjectID, listing.Name, listing.URL, listing.CreatedAt)
+	if err != nil {
+		r.log.Error().Err(err).Str("method", name).Msg("failed to execute query in")
+		return fmt.Errorf("failed to execute query in %s: %w", name, err)
+	}
+
+	return nil
+}
+
+func (r *BlacklistRepository) MGetHiddenListingByUserID(ctx context.Context, userID string) (blacklist.HiddenListings, error) {
+	const name = "BlacklistRepository.MGetHiddenListingByUserID"
+	ctx, cancel := context.WithTimeout(ctx, time.Second*defaultTimeoutSeconds)
+	defer cancel()
+
+	result := make(blacklist.HiddenListings, 0, defaultCapacity)
+	rows, err := r.db.QueryContext(ctx, "SELECT user_id, object_id, name, url, created_at FROM hidden_listings WHERE user_id = ? ORDER BY created_at DESC;", userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return result, nil
+		}
+		r.log.Error().Err(err).Str("method", name).Msg("failed to execute query in")
+		return nil, fmt.Errorf("failed to execute query in %s: %w", name, err)
+	}
+	defer rows.Close()
+
+	// iterate over rows
+	for rows.Next() {
+		var entry blacklist.HiddenListing
+		if err = rows.Scan(&entry.UserID, &entry.ObjectID, &entry.Name, &entry.URL, &entry.CreatedAt); err != nil {
+			r.log.Error().Err(err).Str("method", name).Msg("failed to scan a row in")
+			return nil, fmt.Errorf("failed to scan a row in %s: %w", name, err)
+		}
+		result = append(result, entry)
+	}
+	if err = rows.Err(); err != nil {
+		r.log.Error().Err(err).Str("method", name).Msg("failed to iterate over rows in")
+		return nil, fmt.Errorf("failed to iterate over rows in %s: %w", name, err)
+	}
+
+	return result, nil
+}
+
+func (r *BlacklistRepository) DeleteHiddenListingByUserIDAndObjectID(ctx context.Context, userID, objectID string) error {
+	const name = "BlacklistRepository.DeleteHiddenListingByUserIDAndObjectID"
+	ctx, cancel := context.WithTimeout(ctx, time.Second*defaultTimeoutSeconds)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, "DELETE FROM hidden_listings WHERE user_id = ? AND object_id = ?;", userID, objectID)
+	if err != nil {
+		r.log.Error().Err(err).Str("method", name).Msg("failed to execute query in")
+		return fmt.Errorf("failed to execute query in %s: %w", name, err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		r.log.Error().Err(err).Str("method", name).Msg("failed to get affected rows in")
+		return fmt.Errorf("failed to get affected rows in %s: %w", name, err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("no rows were deleted in %s: %w", name, sql.ErrNoRows)
+	}
+
+	return nil
+}
+
+func (r *BlacklistRepository) MDeleteHiddenListingByUserIDTx(ctx context.Context, tx domain.Tx, userID string) error {
+	const name = "BlacklistRepository.MDeleteHiddenListingByUserIDTx"
+	ctx, cancel := context.WithTimeout(ctx, time.Second*defaultTimeoutSeconds)
+	defer cancel()
+
+	_, err := tx.ExecContext(ctx, "DELETE FROM hidden_listings WHERE user_id = ?;", userID)
+	if err != nil {
+		r.log.Error().Err(err).Str("method", name).Msg("failed to execute query in")
+		return fmt.Errorf("failed to execute query in %s: %w", name, err)
+	}
+
+	return nil
+}
+
+func (r *BlacklistRepository) InsertRule(ctx context.Context, rule *blacklist.Rule) error {
+	const name = "BlacklistRepository.InsertRule"
+	ctx, cancel := context.WithTimeout(ctx, time.Second*defaultTimeoutSeconds)
+	defer cancel()
+
+	_, err := r.db.ExecContext(ctx, "INSERT INTO blacklist_rules (user_id, kind, value, created_at) VALUES (?, ?, ?, ?) ON CONFLICT (user_id, kind, value) DO NOTHING;", rule.UserID, rule.Kind, rule.Value, rule.CreatedAt)
+	if err != nil {
+		r.log.Error().Err(err).Str("method", name).Msg("failed to execute query in")
+		return fmt.Errorf("failed to execute query in %s: %w", name, err)
+	}
+
+	return nil
+}
+
+func (r *BlacklistRepository) MGetRuleByUserID(ctx context.Context, userID string) (blacklist.Rules, error) {
+	const name = "BlacklistRepository.MGetRuleByUserID"
+	ctx, cancel := context.WithTimeout(ctx, time.Second*defaultTimeoutSeconds)
+	defer cancel()
+
+	result := make(blacklist.Rules, 0, defaultCapacity)
+	rows, err := r.db.QueryContext(ctx, "SELECT user_id, kind, value, created_at FROM blacklist_rules WHERE user_id = ? ORDER BY kind, value;", userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return result, nil
+		}
+		r.log.Error().Err(err).Str("method", name).Msg("failed to execute query in")
+		return nil, fmt.Errorf("failed to execute query in %s: %w", name, err)
+	}
+	defer rows.Close()
+
+	// iterate over rows
+	for rows.Next() {
+		var entry blacklist.Rule
+		if err = rows.Scan(&entry.UserID, &entry.Kind, &entry.Value, &entry.CreatedAt); err != nil {
+			r.log.Error().Err(err).Str("method", name).Msg("failed to scan a row in")
+			return nil, fmt.Errorf("failed to scan a row in %s: %w", name, err)
+		}
+		result = append(result, entry)
+	}
+	if err = rows.Err(); err != nil {
+		r.log.Error().Err(err).Str("method", name).Msg("failed to iterate over rows in")
+		return nil, fmt.Errorf("failed to iterate over rows in %s: %w", name, err)
+	}
+
+	return result, nil
+}
+
+func (r *BlacklistRepository) DeleteRuleByUserIDAndKindAndValue(ctx context.Context, userID, kind, value string) error {
+	const name = "BlacklistRepository.DeleteRuleByUserIDAndKindAndValue"
+	ctx, cancel := context.WithTimeout(ctx, time.Second*defaultTimeoutSeconds)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, "DELETE FROM blacklist_rules WHERE user_id = ? AND kind = ? AND value = ?;", userID, kind, value)
+	if err != nil {
+		r.log.Error().Err(err).Str("method", name).Msg("failed to execute query in")
+		return fmt.Errorf("failed to execute query in %s: %w", name, err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		r.log.Error().Err(err).Str("method", name).Msg("failed to get affected rows in")
+		return fmt.Errorf("failed to get affected rows in %s: %w", name, err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("no rows were deleted in %s: %w", name, sql.ErrNoRows)
+	}
+
+	return nil
+}
+
+func (r *BlacklistRepository) MDeleteRuleByUserIDTx(ctx context.Context, tx domain.Tx, userID string) error {
+	const name = "BlacklistRepository.MDeleteRuleByUserIDTx"
+	ctx, cancel := context.WithTimeout(ctx, time.Second*defaultTimeoutSeconds)
+	defer cancel()
+
+	_, err := tx.ExecContext(ctx, "DELETE FROM blacklist_rules WHERE user_id = ?;", userID)
+	if err != nil {
+		r.log.Error().Err(err).Str("method", name).Msg("failed to execute query in")
+		return fmt.Errorf("failed to execute query in %s: %w", name, err)
+	}
+
+	return nil
+}
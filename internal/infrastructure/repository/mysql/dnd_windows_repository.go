@@ -0,0 +1,108 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"fundaNotifier/internal/domain"
+	"fundaNotifier/internal/domain/dnd_windows"
+	"time"
+)
+
+var _ dnd_windows.Repository = (*DNDWindowsRepository)(nil)
+
+type DNDWindowsRepository struct {
+	*Repository
+}
+
+func NewDNDWindowsRepository(repository *Repository) *DNDWindowsRepository {
+	return &DNDWindowsRepository{
+		Repository: repository,
+	}
+}
+
+func (r *DNDWindowsRepository) UpsertWindow(ctx context.Context, window *dnd_windows.Window) error {
+	const name = "DNDWindowsRepository.UpsertWindow"
+	ctx, cancel := context.WithTimeout(ctx, time.Second*defaultTimeoutSeconds)
+	defer cancel()
+
+	_, err := r.db.ExecContext(ctx, "INSERT INTO dnd_windows (user_id, name, weekdays, start_minutes, end_minutes) VALUES (?, ?, ?, ?, ?) ON CONFLICT (user_id, name) DO UPDATE SET weekdays = excluded.weekdays, start_minutes = excluded.start_minutes, end_minutes = excluded.end_minutes;", window.UserID, window.Name, window.WeekdaysRaw, window.Start, window.End)
+	if err != nil {
+		r.log.Error().Err(err).Str("method", name).Msg("failed to execute query in")
+		return fmt.Errorf("failed to execute query in %s: %w", name, err)
+	}
+
+	return nil
+}
+
+func (r *DNDWindowsRepository) MGetWindowByUserID(ctx context.Context, userID string) (dnd_windows.Windows, error) {
+	const name = "DNDWindowsRepository.MGetWindowByUserID"
+	ctx, cancel := context.WithTimeout(ctx, time.Second*defaultTimeoutSeconds)
+	defer cancel()
+
+	result := make(dnd_windows.Windows, 0, defaultCapacity)
+	rows, err := r.db.QueryContext(ctx, "SELECT user_id, name, weekdays, start_minutes, end_minutes FROM dnd_windows WHERE user_id = ? ORDER BY name;", userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return result, nil
+		}
+		r.log.Error().Err(err).Str("method", name).Msg("failed to execute query in")
+		return nil, fmt.Errorf("failed to execute query in %s: %w", name, err)
+	}
+	defer rows.Close()
+
+	// iterate over rows
+	for rows.Next() {
+		var entry dnd_windows.Window
+		if err = rows.Scan(&entry.UserID, &entry.Name, &entry.WeekdaysRaw, &entry.Start, &entry.End); err != nil {
+			r.log.Error().Err(err).Str("method", name).Msg("failed to scan a row in")
+			return nil, fmt.Errorf("failed to scan a row in %s: %w", name, err)
+		}
+		entry.ParseRawWeekdays()
+		result = append(result, entry)
+	}
+	if err = rows.Err(); err != nil {
+		r.log.Error().Err(err).Str("method", name).Msg("failed to iterate over rows in")
+		return nil, fmt.Errorf("failed to iterate over rows in %s: %w", name, err)
+	}
+
+	return result, nil
+}
+
+func (r *DNDWindowsRepository) DeleteWindowByUserIDAndName(ctx context.Context, userID, windowName string) error {
+	const name = "DNDWindowsRepository.DeleteWindowByUserIDAndName"
+	ctx, cancel := context.WithTimeout(ctx, time.Second*defaultTimeoutSeconds)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, "DELETE FROM dnd_windows WHERE user_id = ? AND name = ?;", userID, windowName)
+	if err != nil {
+		r.log.Error().Err(err).Str("method", name).Msg("failed to execute query in")
+		return fmt.Errorf("failed to execute query in %s: %w", name, err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		r.log.Error().Err(err).Str("method", name).Msg("failed to get affected rows in")
+		return fmt.Errorf("failed to get affected rows in %s: %w", name, err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("no rows were deleted in %s: %w", name, sql.ErrNoRows)
+	}
+
+	return nil
+}
+
+func (r *DNDWindowsRepository) MDeleteWindowByUserIDTx(ctx context.Context, tx domain.Tx, userID string) error {
+	const name = "DNDWindowsRepository.MDeleteWindowByUserIDTx"
+	ctx, cancel := context.WithTimeout(ctx, time.Second*defaultTimeoutSeconds)
+	defer cancel()
+
+	_, err := tx.ExecContext(ctx, "DELETE FROM dnd_windows WHERE user_id = ?;", userID)
+	if err != nil {
+		r.log.Error().Err(err).Str("method", name).Msg("failed to execute query in")
+		return fmt.Errorf("failed to execute query in %s: %w", name, err)
+	}
+
+	return nil
+}
@@ -37,20 +37,57 @@ func (r *ListingsRepository) MDeleteListingByUserIDTx(ctx context.Context, tx do
 	return nil
 }
 
-func (r *ListingsRepository) MDeleteListingByUserIDAndURLsTx(ctx context.Context, tx domain.Tx, userID string, URLs []string) error {
-	const name = "ListingsRepository.MDeleteListingByUserIDAndURLsTx"
+func (r *ListingsRepository) MDeleteListingByUserIDAndQueryNameTx(ctx context.Context, tx domain.Tx, userID, queryName string) error {
+	const name = "ListingsRepository.MDeleteListingByUserIDAndQueryNameTx"
 	ctx, cancel := context.WithTimeout(ctx, time.Second*defaultTimeoutSeconds)
 	defer cancel()
 
-	stmt, err := tx.PrepareContext(ctx, "DELETE FROM listings WHERE user_id = ? AND url = ?;")
+	_, err := tx.ExecContext(ctx, "DELETE FROM listings WHERE user_id = ? AND query_name = ?;", userID, queryName)
+	if err != nil {
+		r.log.Error().Err(err).Str("method", name).Msg("failed to execute query in")
+		return fmt.Errorf("failed to execute query in %s: %w", name, err)
+	}
+
+	return nil
+}
+
+func (r *ListingsRepository) MDeleteListingByUserIDAndQueryNameAndObjectIDsTx(ctx context.Context, tx domain.Tx, userID, queryName string, objectIDs []string) error {
+	const name = "ListingsRepository.MDeleteListingByUserIDAndQueryNameAndObjectIDsTx"
+	ctx, cancel := context.WithTimeout(ctx, time.Second*defaultTimeoutSeconds)
+	defer cancel()
+
+	stmt, err := tx.PrepareContext(ctx, "DELETE FROM listings WHERE user_id = ? AND query_name = ? AND object_id = ?;")
+	if err != nil {
+		r.log.Error().Err(err).Str("method", name).Msg("failed to prepare statement in")
+		return fmt.Errorf("failed to prepare statement in %s: %w", name, err)
+	}
+	defer stmt.Close()
+
+	for idx := range objectIDs {
+		_, err = stmt.ExecContext(ctx, userID, queryName, objectIDs[idx])
+		if err != nil {
+			r.log.Error().Err(err).Str("method", name).Msg("failed to execute query in")
+			return fmt.Errorf("failed to execute query in %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+func (r *ListingsRepository) MIncrementListingMissedSyncsByUserIDAndQueryNameAndObjectIDsTx(ctx context.Context, tx domain.Tx, userID, queryName string, objectIDs []string) error {
+	const name = "ListingsRepository.MIncrementListingMissedSyncsByUserIDAndQueryNameAndObjectIDsTx"
+	ctx, cancel := context.WithTimeout(ctx, time.Second*defaultTimeoutSeconds)
+	defer cancel()
+
+	stmt, err := tx.PrepareContext(ctx, "UPDATE listings SET missed_syncs = missed_syncs + 1 WHERE user_id = ? AND query_name = ? AND object_id = ?;")
 	if err != nil {
 		r.log.Error().Err(err).Str("method", name).Msg("failed to prepare statement in")
 		return fmt.Errorf("failed to prepare statement in %s: %w", name, err)
 	}
 	defer stmt.Close()
 
-	for idx := range URLs {
-		_, err = stmt.ExecContext(ctx, userID, URLs[idx])
+	for idx := range objectIDs {
+		_, err = stmt.ExecContext(ctx, userID, queryName, objectIDs[idx])
 		if err != nil {
 			r.log.Error().Err(err).Str("method", name).Msg("failed to execute query in")
 			return fmt.Errorf("failed to execute query in %s: %w", name, err)
@@ -66,7 +103,7 @@ func (r *ListingsRepository) GetListingByUUID(ctx context.Context, UUID string)
 	defer cancel()
 
 	var entry listings.Listing
-	err := r.db.QueryRowContext(ctx, "SELECT user_id, name, url, description, address_street, address_locality, address_region, currency, price, is_new, created_at, uuid FROM listings WHERE uuid = ?;", UUID).Scan(&entry.UserID, &entry.Name, &entry.URL, &entry.Description, &entry.Address.StreetAddress, &entry.Address.AddressLocality, &entry.Address.AddressRegion, &entry.Offers.PriceCurrency, &entry.Offers.Price, &entry.IsNew, &entry.CreatedAt, &entry.UUID)
+	err := r.db.QueryRowContext(ctx, "SELECT user_id, query_name, object_id, name, url, description, address_street, address_locality, address_region, address_postal_code, agent, living_area, rooms, energy_label, latitude, longitude, photos, currency, price, is_new, created_at, uuid FROM listings WHERE uuid = ?;", UUID).Scan(&entry.UserID, &entry.QueryName, &entry.ObjectID, &entry.Name, &entry.URL, &entry.Description, &entry.Address.StreetAddress, &entry.Address.AddressLocality, &entry.Address.AddressRegion, &entry.Address.PostalCode, &entry.Agent, &entry.LivingArea, &entry.Rooms, &entry.EnergyLabel, &entry.Latitude, &entry.Longitude, &entry.PhotosRaw, &entry.Offers.PriceCurrency, &entry.Offers.Price, &entry.IsNew, &entry.CreatedAt, &entry.UUID)
 	if err != nil {
 		r.log.Error().Err(err).Str("method", name).Msg("failed to execute query in")
 		return nil, fmt.Errorf("failed to execute query in %s: %w", name, err)
@@ -75,6 +112,81 @@ func (r *ListingsRepository) GetListingByUUID(ctx context.Context, UUID string)
 	return &entry, nil
 }
 
+func (r *ListingsRepository) GetListingByUserIDAndObjectID(ctx context.Context, userID, objectID string) (*listings.Listing, error) {
+	const name = "ListingsRepository.GetListingByUserIDAndObjectID"
+	ctx, cancel := context.WithTimeout(ctx, time.Second*defaultTimeoutSeconds)
+	defer cancel()
+
+	var entry listings.Listing
+	err := r.db.QueryRowContext(ctx, "SELECT user_id, query_name, object_id, name, url, description, address_street, address_locality, address_region, address_postal_code, agent, living_area, rooms, energy_label, latitude, longitude, photos, currency, price, is_new, created_at, uuid FROM listings WHERE user_id = ? AND object_id = ? ORDER BY created_at LIMIT 1;", userID, objectID).Scan(&entry.UserID, &entry.QueryName, &entry.ObjectID, &entry.Name, &entry.URL, &entry.Description, &entry.Address.StreetAddress, &entry.Address.AddressLocality, &entry.Address.AddressRegion, &entry.Address.PostalCode, &entry.Agent, &entry.LivingArea, &entry.Rooms, &entry.EnergyLabel, &entry.Latitude, &entry.Longitude, &entry.PhotosRaw, &entry.Offers.PriceCurrency, &entry.Offers.Price, &entry.IsNew, &entry.CreatedAt, &entry.UUID)
+	if err != nil {
+		r.log.Error().Err(err).Str("method", name).Msg("failed to execute query in")
+		return nil, fmt.Errorf("failed to execute query in %s: %w", name, err)
+	}
+
+	return &entry, nil
+}
+
+func (r *ListingsRepository) SearchListingByUserID(ctx context.Context, userID, match string) (listings.Matches, error) {
+	const name = "ListingsRepository.SearchListingByUserID"
+	ctx, cancel := context.WithTimeout(ctx, time.Second*defaultTimeoutSeconds)
+	defer cancel()
+
+	result := make(listings.Matches, 0, defaultCapacity)
+	rows, err := r.db.QueryContext(ctx, "SELECT l.user_id, l.query_name, l.object_id, l.name, l.url, l.description, l.address_street, l.address_locality, l.address_region, l.address_postal_code, l.agent, l.living_area, l.rooms, l.energy_label, l.latitude, l.longitude, l.photos, l.currency, l.price, l.is_new, l.created_at, l.uuid, snippet(listings_search, -1, ?, ?, ?, ?) FROM listings_search JOIN listings l ON l.rowid = listings_search.rowid WHERE listings_search MATCH ? AND l.user_id = ? ORDER BY rank;", listings.SnippetOpen, listings.SnippetClose, listings.SnippetEllipsis, listings.SnippetTokens, match, userID)
+	if err != nil {
+		r.log.Error().Err(err).Str("method", name).Msg("failed to execute query in")
+		return nil, fmt.Errorf("failed to execute query in %s: %w", name, err)
+	}
+	defer rows.Close()
+
+	// iterate over rows
+	for rows.Next() {
+		var entry listings.Match
+		if err = rows.Scan(&entry.Listing.UserID, &entry.Listing.QueryName, &entry.Listing.ObjectID, &entry.Listing.Name, &entry.Listing.URL, &entry.Listing.Description, &entry.Listing.Address.StreetAddress, &entry.Listing.Address.AddressLocality, &entry.Listing.Address.AddressRegion, &entry.Listing.Address.PostalCode, &entry.Listing.Agent, &entry.Listing.LivingArea, &entry.Listing.Rooms, &entry.Listing.EnergyLabel, &entry.Listing.Latitude, &entry.Listing.Longitude, &entry.Listing.PhotosRaw, &entry.Listing.Offers.PriceCurrency, &entry.Listing.Offers.Price, &entry.Listing.IsNew, &entry.Listing.CreatedAt, &entry.Listing.UUID, &entry.Snippet); err != nil {
+			r.log.Error().Err(err).Str("method", name).Msg("failed to scan a row in")
+			return nil, fmt.Errorf("failed to scan a row in %s: %w", name, err)
+		}
+		result = append(result, entry)
+	}
+	if err = rows.Err(); err != nil {
+		r.log.Error().Err(err).Str("method", name).Msg("failed to iterate over rows in")
+		return nil, fmt.Errorf("failed to iterate over rows in %s: %w", name, err)
+	}
+
+	return result, nil
+}
+
+func (r *ListingsRepository) CountListingByUserIDAndQueryNameGroupByQueryVersion(ctx context.Context, userID, queryName string) (map[int]int, error) {
+	const name = "ListingsRepository.CountListingByUserIDAndQueryNameGroupByQueryVersion"
+	ctx, cancel := context.WithTimeout(ctx, time.Second*defaultTimeoutSeconds)
+	defer cancel()
+
+	result := make(map[int]int)
+	rows, err := r.db.QueryContext(ctx, "SELECT query_version, COUNT(*) FROM listings WHERE user_id = ? AND query_name = ? GROUP BY query_version;", userID, queryName)
+	if err != nil {
+		r.log.Error().Err(err).Str("method", name).Msg("failed to execute query in")
+		return nil, fmt.Errorf("failed to execute query in %s: %w", name, err)
+	}
+	defer rows.Close()
+
+	// iterate over rows
+	for rows.Next() {
+		var queryVersion, count int
+		if err = rows.Scan(&queryVersion, &count); err != nil {
+			r.log.Error().Err(err).Str("method", name).Msg("failed to scan a row in")
+			return nil, fmt.Errorf("failed to scan a row in %s: %w", name, err)
+		}
+		result[queryVersion] = count
+	}
+	if err = rows.Err(); err != nil {
+		r.log.Error().Err(err).Str("method", name).Msg("failed to iterate over rows in")
+		return nil, fmt.Errorf("failed to iterate over rows in %s: %w", name, err)
+	}
+
+	return result, nil
+}
+
 func (r *ListingsRepository) MGetListingByUserID(ctx context.Context, userID string, showOnlyNew bool) (listings.Listings, error) {
 	const name = "ListingsRepository.MGetListingByUserID"
 	ctx, cancel := context.WithTimeout(ctx, time.Second*defaultTimeoutSeconds)
@@ -82,9 +194,9 @@ func (r *ListingsRepository) MGetListingByUserID(ctx context.Context, userID str
 
 	var query string
 	if showOnlyNew {
-		query = "SELECT user_id, name, url, description, address_street, address_locality, address_region, currency, price, is_new, created_at, uuid FROM listings WHERE user_id = ? AND is_new IS TRUE;"
+		query = "SELECT user_id, query_name, object_id, name, url, description, address_street, address_locality, address_region, address_postal_code, agent, living_area, rooms, energy_label, latitude, longitude, photos, currency, price, is_new, created_at, uuid FROM listings WHERE user_id = ? AND is_new IS TRUE;"
 	} else {
-		query = "SELECT user_id, name, url, description, address_street, address_locality, address_region, currency, price, is_new, created_at, uuid FROM listings WHERE user_id = ?;"
+		query = "SELECT user_id, query_name, object_id, name, url, description, address_street, address_locality, address_region, address_postal_code, agent, living_area, rooms, energy_label, latitude, longitude, photos, currency, price, is_new, created_at, uuid FROM listings WHERE user_id = ?;"
 	}
 
 	result := make(listings.Listings, 0, defaultCapacity)
@@ -102,7 +214,7 @@ func (r *ListingsRepository) MGetListingByUserID(ctx context.Context, userID str
 	// iterate over rows
 	for rows.Next() {
 		var entry listings.Listing
-		if err = rows.Scan(&entry.UserID, &entry.Name, &entry.URL, &entry.Description, &entry.Address.StreetAddress, &entry.Address.AddressLocality, &entry.Address.AddressRegion, &entry.Offers.PriceCurrency, &entry.Offers.Price, &entry.IsNew, &entry.CreatedAt, &entry.UUID); err != nil {
+		if err = rows.Scan(&entry.UserID, &entry.QueryName, &entry.ObjectID, &entry.Name, &entry.URL, &entry.Description, &entry.Address.StreetAddress, &entry.Address.AddressLocality, &entry.Address.AddressRegion, &entry.Address.PostalCode, &entry.Agent, &entry.LivingArea, &entry.Rooms, &entry.EnergyLabel, &entry.Latitude, &entry.Longitude, &entry.PhotosRaw, &entry.Offers.PriceCurrency, &entry.Offers.Price, &entry.IsNew, &entry.CreatedAt, &entry.UUID); err != nil {
 			r.log.Error().Err(err).Str("method", name).Msg("failed to scan a row in")
 			return nil, fmt.Errorf("failed to scan a row in %s: %w", name, err)
 		}
@@ -116,13 +228,13 @@ func (r *ListingsRepository) MGetListingByUserID(ctx context.Context, userID str
 	return result, nil
 }
 
-func (r *ListingsRepository) MGetListingByUserIDTx(ctx context.Context, tx domain.Tx, userID string) (listings.Listings, error) {
-	const name = "ListingsRepository.MGetListingByUserIDTx"
+func (r *ListingsRepository) MGetListingByUserIDAndQueryNameTx(ctx context.Context, tx domain.Tx, userID, queryName string) (listings.Listings, error) {
+	const name = "ListingsRepository.MGetListingByUserIDAndQueryNameTx"
 	ctx, cancel := context.WithTimeout(ctx, time.Second*defaultTimeoutSeconds)
 	defer cancel()
 
 	result := make(listings.Listings, 0, defaultCapacity)
-	rows, err := tx.QueryContext(ctx, "SELECT user_id, name, url, description, address_street, address_locality, address_region, currency, price, is_new, created_at, uuid FROM listings WHERE user_id = ?;", userID)
+	rows, err := tx.QueryContext(ctx, "SELECT user_id, query_name, object_id, name, url, description, address_street, address_locality, address_region, address_postal_code, agent, living_area, rooms, energy_label, latitude, longitude, photos, currency, price, is_new, missed_syncs, created_at, uuid FROM listings WHERE user_id = ? AND query_name = ?;", userID, queryName)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			r.log.Warn().Err(err).Str("method", name).Msg("no data was found")
@@ -136,7 +248,7 @@ func (r *ListingsRepository) MGetListingByUserIDTx(ctx context.Context, tx domai
 	// iterate over rows
 	for rows.Next() {
 		var entry listings.Listing
-		if err = rows.Scan(&entry.UserID, &entry.Name, &entry.URL, &entry.Description, &entry.Address.StreetAddress, &entry.Address.AddressLocality, &entry.Address.AddressRegion, &entry.Offers.PriceCurrency, &entry.Offers.Price, &entry.IsNew, &entry.CreatedAt, &entry.UUID); err != nil {
+		if err = rows.Scan(&entry.UserID, &entry.QueryName, &entry.ObjectID, &entry.Name, &entry.URL, &entry.Description, &entry.Address.StreetAddress, &entry.Address.AddressLocality, &entry.Address.AddressRegion, &entry.Address.PostalCode, &entry.Agent, &entry.LivingArea, &entry.Rooms, &entry.EnergyLabel, &entry.Latitude, &entry.Longitude, &entry.PhotosRaw, &entry.Offers.PriceCurrency, &entry.Offers.Price, &entry.IsNew, &entry.MissedSyncs, &entry.CreatedAt, &entry.UUID); err != nil {
 			r.log.Error().Err(err).Str("method", name).Msg("failed to scan a row in")
 			return nil, fmt.Errorf("failed to scan a row in %s: %w", name, err)
 		}
@@ -155,7 +267,7 @@ func (r *ListingsRepository) MInsertListingTx(ctx context.Context, tx domain.Tx,
 		return nil
 	}
 
-	const fieldsLimit = 2731 // max is 32766 divided by 12
+	const fieldsLimit = 1424 // max is 32766 divided by 23
 	if len(listings) <= fieldsLimit {
 		return r.mInsertListingTx(ctx, tx, listings)
 	}
@@ -179,7 +291,7 @@ func (r *ListingsRepository) MInsertListingTx(ctx context.Context, tx domain.Tx,
 func (r *ListingsRepository) mInsertListingTx(ctx context.Context, tx domain.Tx, listings listings.Listings) error {
 	const (
 		name     = "ListingsRepository.mInsertListingTx"
-		fieldsNb = 12
+		fieldsNb = 23
 	)
 	ctx, cancel := context.WithTimeout(ctx, time.Second*defaultTimeoutSeconds)
 	defer cancel()
@@ -188,7 +300,7 @@ func (r *ListingsRepository) mInsertListingTx(ctx context.Context, tx domain.Tx,
 	timestamp := time.Now().UTC()
 	b := strings.Builder{}
 	params := make([]interface{}, 0, len(listings)*fieldsNb)
-	b.WriteString("INSERT INTO listings (user_id, name, url, description, address_street, address_locality, address_region, currency, price, is_new, created_at, uuid) VALUES ")
+	b.WriteString("INSERT INTO listings (user_id, query_name, query_version, object_id, name, url, description, address_street, address_locality, address_region, address_postal_code, agent, living_area, rooms, energy_label, latitude, longitude, photos, currency, price, is_new, created_at, uuid) VALUES ")
 	counter := 0
 	for idx := range listings {
 		if counter > 0 {
@@ -198,12 +310,23 @@ func (r *ListingsRepository) mInsertListingTx(ctx context.Context, tx domain.Tx,
 		params = append(
 			params,
 			listings[idx].UserID,
+			listings[idx].QueryName,
+			listings[idx].QueryVersion,
+			listings[idx].ObjectID,
 			listings[idx].Name,
 			listings[idx].URL,
 			listings[idx].Description,
 			listings[idx].Address.StreetAddress,
 			listings[idx].Address.AddressLocality,
 			listings[idx].Address.AddressRegion,
+			listings[idx].Address.PostalCode,
+			listings[idx].Agent,
+			listings[idx].LivingArea,
+			listings[idx].Rooms,
+			listings[idx].EnergyLabel,
+			listings[idx].Latitude,
+			listings[idx].Longitude,
+			listings[idx].PhotosRaw,
 			listings[idx].Offers.PriceCurrency,
 			listings[idx].Offers.Price,
 			true,
@@ -231,7 +354,7 @@ func (r *ListingsRepository) MUpdateListingTx(ctx context.Context, tx domain.Tx,
 		return nil
 	}
 
-	stmt, err := tx.PrepareContext(ctx, "UPDATE listings SET name = ?, description = ?, address_street = ?, address_locality = ?, address_region = ?, currency = ?, price = ?, is_new = false WHERE user_id = ? and url = ?;")
+	stmt, err := tx.PrepareContext(ctx, "UPDATE listings SET name = ?, url = ?, description = ?, address_street = ?, address_locality = ?, address_region = ?, address_postal_code = ?, agent = ?, living_area = ?, rooms = ?, energy_label = ?, latitude = ?, longitude = ?, photos = ?, currency = ?, price = ?, is_new = false, missed_syncs = 0 WHERE user_id = ? AND query_name = ? AND object_id = ?;")
 	if err != nil {
 		r.log.Error().Err(err).Str("method", name).Msg("failed to prepare statement in")
 		return fmt.Errorf("failed to prepare statement in %s: %w", name, err)
@@ -239,7 +362,7 @@ func (r *ListingsRepository) MUpdateListingTx(ctx context.Context, tx domain.Tx,
 	defer stmt.Close()
 
 	for idx := range listings {
-		_, err = stmt.ExecContext(ctx, listings[idx].Name, listings[idx].Description, listings[idx].Address.StreetAddress, listings[idx].Address.AddressLocality, listings[idx].Address.AddressRegion, listings[idx].Offers.PriceCurrency, listings[idx].Offers.Price, listings[idx].UserID, listings[idx].URL)
+		_, err = stmt.ExecContext(ctx, listings[idx].Name, listings[idx].URL, listings[idx].Description, listings[idx].Address.StreetAddress, listings[idx].Address.AddressLocality, listings[idx].Address.AddressRegion, listings[idx].Address.PostalCode, listings[idx].Agent, listings[idx].LivingArea, listings[idx].Rooms, listings[idx].EnergyLabel, listings[idx].Latitude, listings[idx].Longitude, listings[idx].PhotosRaw, listings[idx].Offers.PriceCurrency, listings[idx].Offers.Price, listings[idx].UserID, listings[idx].QueryName, listings[idx].ObjectID)
 		if err != nil {
 			r.log.Error().Err(err).Str("method", name).Msg("failed to execute query in")
 			return fmt.Errorf("failed to execute query in %s: %w", name, err)
@@ -258,7 +381,25 @@ func (r *ListingsRepository) UpdateFavoriteListingTx(ctx context.Context, tx dom
 		return nil
 	}
 
-	_, err := tx.ExecContext(ctx, "UPDATE favorites SET name = ?, description = ?, address_street = ?, address_locality = ?, address_region = ?, currency = ?, price = ? WHERE user_id = ? and url = ?;", listing.Name, listing.Description, listing.Address.StreetAddress, listing.Address.AddressLocality, listing.Address.AddressRegion, listing.Offers.PriceCurrency, listing.Offers.Price, listing.UserID, listing.URL)
+	_, err := tx.ExecContext(ctx, "UPDATE favorites SET name = ?, url = ?, description = ?, address_street = ?, address_locality = ?, address_region = ?, currency = ?, price = ? WHERE user_id = ? and object_id = ?;", listing.Name, listing.URL, listing.Description, listing.Address.StreetAddress, listing.Address.AddressLocality, listing.Address.AddressRegion, listing.Offers.PriceCurrency, listing.Offers.Price, listing.UserID, listing.ObjectID)
+	if err != nil {
+		r.log.Error().Err(err).Str("method", name).Msg("failed to execute query in")
+		return fmt.Errorf("failed to execute query in %s: %w", name, err)
+	}
+
+	return nil
+}
+
+func (r *ListingsRepository) UpdateFavoriteListingStatus(ctx context.Context, listing *listings.Listing) error {
+	const name = "ListingsRepository.UpdateFavoriteListingStatus"
+	ctx, cancel := context.WithTimeout(ctx, time.Second*defaultTimeoutSeconds)
+	defer cancel()
+
+	if listing == nil {
+		return nil
+	}
+
+	_, err := r.db.ExecContext(ctx, "UPDATE favorites SET name = ?, url = ?, currency = ?, price = ?, status = ?, is_gone = ?, checked_at = ? WHERE user_id = ? and object_id = ?;", listing.Name, listing.URL, listing.Offers.PriceCurrency, listing.Offers.Price, listing.Status, listing.IsGone, listing.CheckedAt, listing.UserID, listing.ObjectID)
 	if err != nil {
 		r.log.Error().Err(err).Str("method", name).Msg("failed to execute query in")
 		return fmt.Errorf("failed to execute query in %s: %w", name, err)
@@ -276,12 +417,117 @@ func (r *ListingsRepository) InsertFavoriteListingTx(ctx context.Context, tx dom
 		return nil
 	}
 
-	_, err := tx.ExecContext(ctx, "INSERT INTO favorites (user_id, name, url, description, address_street, address_locality, address_region, currency, price) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?);", listing.UserID, listing.Name, listing.URL, listing.Description, listing.Address.StreetAddress, listing.Address.AddressLocality, listing.Address.AddressRegion, listing.Offers.PriceCurrency, listing.Offers.Price)
+	_, err := tx.ExecContext(ctx, "INSERT INTO favorites (user_id, object_id, name, url, description, address_street, address_locality, address_region, currency, price, note, rank, status, is_gone, checked_at, stage, stage_changed_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);", listing.UserID, listing.ObjectID, listing.Name, listing.URL, listing.Description, listing.Address.StreetAddress, listing.Address.AddressLocality, listing.Address.AddressRegion, listing.Offers.PriceCurrency, listing.Offers.Price, listing.Note, listing.Rank, listing.Status, listing.IsGone, listing.CheckedAt, listing.Stage, listing.StageChangedAt)
+	if err != nil {
+		r.log.Error().Err(err).Str("method", name).Msg("failed to execute query in")
+		return fmt.Errorf("failed to execute query in %s: %w", name, err)
+	}
+
+	return nil
+}
+
+func (r *ListingsRepository) GetFavoriteListingByUserIDAndObjectID(ctx context.Context, userID, objectID string) (*listings.Listing, error) {
+	const name = "ListingsRepository.GetFavoriteListingByUserIDAndObjectID"
+	ctx, cancel := context.WithTimeout(ctx, time.Second*defaultTimeoutSeconds)
+	defer cancel()
+
+	var entry listings.Listing
+	row := r.db.QueryRowContext(ctx, "SELECT user_id, object_id, name, url, description, address_street, address_locality, address_region, currency, price, note, rank, status, is_gone, checked_at, stage, stage_changed_at FROM favorites WHERE user_id = ? AND object_id = ?;", userID, objectID)
+	err := row.Scan(&entry.UserID, &entry.ObjectID, &entry.Name, &entry.URL, &entry.Description, &entry.Address.StreetAddress, &entry.Address.AddressLocality, &entry.Address.AddressRegion, &entry.Offers.PriceCurrency, &entry.Offers.Price, &entry.Note, &entry.Rank, &entry.Status, &entry.IsGone, &entry.CheckedAt, &entry.Stage, &entry.StageChangedAt)
+	if err != nil {
+		r.log.Error().Err(err).Str("method", name).Msg("failed to execute query in")
+		return nil, fmt.Errorf("failed to execute query in %s: %w", name, err)
+	}
+
+	return &entry, nil
+}
+
+func (r *ListingsRepository) UpdateFavoriteListingNoteByUserIDAndObjectID(ctx context.Context, userID, objectID, note string) error {
+	const name = "ListingsRepository.UpdateFavoriteListingNoteByUserIDAndObjectID"
+	ctx, cancel := context.WithTimeout(ctx, time.Second*defaultTimeoutSeconds)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, "UPDATE favorites SET note = ? WHERE user_id = ? AND object_id = ?;", note, userID, objectID)
 	if err != nil {
 		r.log.Error().Err(err).Str("method", name).Msg("failed to execute query in")
 		return fmt.Errorf("failed to execute query in %s: %w", name, err)
 	}
 
+	affected, err := result.RowsAffected()
+	if err != nil {
+		r.log.Error().Err(err).Str("method", name).Msg("failed to get affected rows in")
+		return fmt.Errorf("failed to get affected rows in %s: %w", name, err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("no rows were updated in %s: %w", name, sql.ErrNoRows)
+	}
+
+	return nil
+}
+
+func (r *ListingsRepository) MUpdateFavoriteListingRankTx(ctx context.Context, tx domain.Tx, listings listings.Listings) error {
+	const name = "ListingsRepository.MUpdateFavoriteListingRankTx"
+	ctx, cancel := context.WithTimeout(ctx, time.Second*defaultTimeoutSeconds)
+	defer cancel()
+
+	for idx := range listings {
+		_, err := tx.ExecContext(ctx, "UPDATE favorites SET rank = ? WHERE user_id = ? AND object_id = ?;", listings[idx].Rank, listings[idx].UserID, listings[idx].ObjectID)
+		if err != nil {
+			r.log.Error().Err(err).Str("method", name).Msg("failed to execute query in")
+			return fmt.Errorf("failed to execute query in %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+func (r *ListingsRepository) UpdateFavoriteListingStageTx(ctx context.Context, tx domain.Tx, listing *listings.Listing) error {
+	const name = "ListingsRepository.UpdateFavoriteListingStageTx"
+	ctx, cancel := context.WithTimeout(ctx, time.Second*defaultTimeoutSeconds)
+	defer cancel()
+
+	if listing == nil {
+		return nil
+	}
+
+	result, err := tx.ExecContext(ctx, "UPDATE favorites SET stage = ?, stage_changed_at = ? WHERE user_id = ? AND object_id = ?;", listing.Stage, listing.StageChangedAt, listing.UserID, listing.ObjectID)
+	if err != nil {
+		r.log.Error().Err(err).Str("method", name).Msg("failed to execute query in")
+		return fmt.Errorf("failed to execute query in %s: %w", name, err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		r.log.Error().Err(err).Str("method", name).Msg("failed to get affected rows in")
+		return fmt.Errorf("failed to get affected rows in %s: %w", name, err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("no rows were updated in %s: %w", name, sql.ErrNoRows)
+	}
+
+	return nil
+}
+
+func (r *ListingsRepository) DeleteFavoriteListingByUserIDAndObjectIDTx(ctx context.Context, tx domain.Tx, userID, objectID string) error {
+	const name = "ListingsRepository.DeleteFavoriteListingByUserIDAndObjectIDTx"
+	ctx, cancel := context.WithTimeout(ctx, time.Second*defaultTimeoutSeconds)
+	defer cancel()
+
+	result, err := tx.ExecContext(ctx, "DELETE FROM favorites WHERE user_id = ? AND object_id = ?;", userID, objectID)
+	if err != nil {
+		r.log.Error().Err(err).Str("method", name).Msg("failed to execute query in")
+		return fmt.Errorf("failed to execute query in %s: %w", name, err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		r.log.Error().Err(err).Str("method", name).Msg("failed to get affected rows in")
+		return fmt.Errorf("failed to get affected rows in %s: %w", name, err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("no rows were deleted in %s: %w", name, sql.ErrNoRows)
+	}
+
 	return nil
 }
 
@@ -291,7 +537,7 @@ func (r *ListingsRepository) MGetFavoriteListingByUserID(ctx context.Context, us
 	defer cancel()
 
 	result := make(listings.Listings, 0, defaultCapacity)
-	rows, err := r.db.QueryContext(ctx, "SELECT user_id, name, url, description, address_street, address_locality, address_region, currency, price FROM favorites WHERE user_id = ?;", userID)
+	rows, err := r.db.QueryContext(ctx, "SELECT user_id, object_id, name, url, description, address_street, address_locality, address_region, currency, price, note, rank, status, is_gone, checked_at, stage, stage_changed_at FROM favorites WHERE user_id = ? ORDER BY rank;", userID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			r.log.Warn().Err(err).Str("method", name).Msg("no data was found")
@@ -305,7 +551,7 @@ func (r *ListingsRepository) MGetFavoriteListingByUserID(ctx context.Context, us
 	// iterate over rows
 	for rows.Next() {
 		var entry listings.Listing
-		if err = rows.Scan(&entry.UserID, &entry.Name, &entry.URL, &entry.Description, &entry.Address.StreetAddress, &entry.Address.AddressLocality, &entry.Address.AddressRegion, &entry.Offers.PriceCurrency, &entry.Offers.Price); err != nil {
+		if err = rows.Scan(&entry.UserID, &entry.ObjectID, &entry.Name, &entry.URL, &entry.Description, &entry.Address.StreetAddress, &entry.Address.AddressLocality, &entry.Address.AddressRegion, &entry.Offers.PriceCurrency, &entry.Offers.Price, &entry.Note, &entry.Rank, &entry.Status, &entry.IsGone, &entry.CheckedAt, &entry.Stage, &entry.StageChangedAt); err != nil {
 			r.log.Error().Err(err).Str("method", name).Msg("failed to scan a row in")
 			return nil, fmt.Errorf("failed to scan a row in %s: %w", name, err)
 		}
@@ -325,7 +571,7 @@ func (r *ListingsRepository) MGetFavoriteListingByUserIDTx(ctx context.Context,
 	defer cancel()
 
 	result := make(listings.Listings, 0, defaultCapacity)
-	rows, err := tx.QueryContext(ctx, "SELECT user_id, name, url, description, address_street, address_locality, address_region, currency, price FROM favorites WHERE user_id = ?;", userID)
+	rows, err := tx.QueryContext(ctx, "SELECT user_id, object_id, name, url, description, address_street, address_locality, address_region, currency, price, note, rank, status, is_gone, checked_at, stage, stage_changed_at FROM favorites WHERE user_id = ? ORDER BY rank;", userID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			r.log.Warn().Err(err).Str("method", name).Msg("no data was found")
@@ -339,7 +585,7 @@ func (r *ListingsRepository) MGetFavoriteListingByUserIDTx(ctx context.Context,
 	// iterate over rows
 	for rows.Next() {
 		var entry listings.Listing
-		if err = rows.Scan(&entry.UserID, &entry.Name, &entry.URL, &entry.Description, &entry.Address.StreetAddress, &entry.Address.AddressLocality, &entry.Address.AddressRegion, &entry.Offers.PriceCurrency, &entry.Offers.Price); err != nil {
+		if err = rows.Scan(&entry.UserID, &entry.ObjectID, &entry.Name, &entry.URL, &entry.Description, &entry.Address.StreetAddress, &entry.Address.AddressLocality, &entry.Address.AddressRegion, &entry.Offers.PriceCurrency, &entry.Offers.Price, &entry.Note, &entry.Rank, &entry.Status, &entry.IsGone, &entry.CheckedAt, &entry.Stage, &entry.StageChangedAt); err != nil {
 			r.log.Error().Err(err).Str("method", name).Msg("failed to scan a row in")
 			return nil, fmt.Errorf("failed to scan a row in %s: %w", name, err)
 		}
@@ -366,3 +612,244 @@ func (r *ListingsRepository) MDeleteFavoriteListingByUserIDTx(ctx context.Contex
 
 	return nil
 }
+
+// insertPricePointQuery skips a point when the latest recorded price of the listing is the same, which happens when
+// a listing is matched by several search queries.
+const insertPricePointQuery = "INSERT INTO price_history (user_id, object_id, currency, price, recorded_at) SELECT ?, ?, ?, ?, ? WHERE NOT EXISTS (SELECT 1 FROM (SELECT price FROM price_history WHERE user_id = ? AND object_id = ? ORDER BY recorded_at DESC LIMIT 1) WHERE price = ?);"
+
+func (r *ListingsRepository) MInsertPricePointTx(ctx context.Context, tx domain.Tx, points listings.PricePoints) error {
+	const name = "ListingsRepository.MInsertPricePointTx"
+	ctx, cancel := context.WithTimeout(ctx, time.Second*defaultTimeoutSeconds)
+	defer cancel()
+
+	if len(points) == 0 {
+		return nil
+	}
+
+	stmt, err := tx.PrepareContext(ctx, insertPricePointQuery)
+	if err != nil {
+		r.log.Error().Err(err).Str("method", name).Msg("failed to prepare statement in")
+		return fmt.Errorf("failed to prepare statement in %s: %w", name, err)
+	}
+	defer stmt.Close()
+
+	for idx := range points {
+		_, err = stmt.ExecContext(ctx, points[idx].UserID, points[idx].ObjectID, points[idx].Currency, points[idx].Price, points[idx].RecordedAt, points[idx].UserID, points[idx].ObjectID, points[idx].Price)
+		if err != nil {
+			r.log.Error().Err(err).Str("method", name).Msg("failed to execute query in")
+			return fmt.Errorf("failed to execute query in %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+func (r *ListingsRepository) InsertPricePoint(ctx context.Context, point *listings.PricePoint) error {
+	const name = "ListingsRepository.InsertPricePoint"
+	ctx, cancel := context.WithTimeout(ctx, time.Second*defaultTimeoutSeconds)
+	defer cancel()
+
+	_, err := r.db.ExecContext(ctx, insertPricePointQuery, point.UserID, point.ObjectID, point.Currency, point.Price, point.RecordedAt, point.UserID, point.ObjectID, point.Price)
+	if err != nil {
+		r.log.Error().Err(err).Str("method", name).Msg("failed to execute query in")
+		return fmt.Errorf("failed to execute query in %s: %w", name, err)
+	}
+
+	return nil
+}
+
+func (r *ListingsRepository) MGetPricePointByUserIDAndObjectID(ctx context.Context, userID, objectID string) (listings.PricePoints, error) {
+	const name = "ListingsRepository.MGetPricePointByUserIDAndObjectID"
+	ctx, cancel := context.WithTimeout(ctx, time.Second*defaultTimeoutSeconds)
+	defer cancel()
+
+	result := make(listings.PricePoints, 0, defaultCapacity)
+	rows, err := r.db.QueryContext(ctx, "SELECT user_id, object_id, currency, price, recorded_at FROM price_history WHERE user_id = ? AND object_id = ? ORDER BY recorded_at;", userID, objectID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return result, nil
+		}
+		r.log.Error().Err(err).Str("method", name).Msg("failed to execute query in")
+		return nil, fmt.Errorf("failed to execute query in %s: %w", name, err)
+	}
+	defer rows.Close()
+
+	// iterate over rows
+	for rows.Next() {
+		var entry listings.PricePoint
+		if err = rows.Scan(&entry.UserID, &entry.ObjectID, &entry.Currency, &entry.Price, &entry.RecordedAt); err != nil {
+			r.log.Error().Err(err).Str("method", name).Msg("failed to scan a row in")
+			return nil, fmt.Errorf("failed to scan a row in %s: %w", name, err)
+		}
+		result = append(result, entry)
+	}
+	if err = rows.Err(); err != nil {
+		r.log.Error().Err(err).Str("method", name).Msg("failed to iterate over rows in")
+		return nil, fmt.Errorf("failed to iterate over rows in %s: %w", name, err)
+	}
+
+	return result, nil
+}
+
+func (r *ListingsRepository) MDeletePricePointByUserIDTx(ctx context.Context, tx domain.Tx, userID string) error {
+	const name = "ListingsRepository.MDeletePricePointByUserIDTx"
+	ctx, cancel := context.WithTimeout(ctx, time.Second*defaultTimeoutSeconds)
+	defer cancel()
+
+	_, err := tx.ExecContext(ctx, "DELETE FROM price_history WHERE user_id = ?;", userID)
+	if err != nil {
+		r.log.Error().Err(err).Str("method", name).Msg("failed to execute query in")
+		return fmt.Errorf("failed to execute query in %s: %w", name, err)
+	}
+
+	return nil
+}
+
+func (r *ListingsRepository) InsertStageEventTx(ctx context.Context, tx domain.Tx, event *listings.StageEvent) error {
+	const name = "ListingsRepository.InsertStageEventTx"
+	ctx, cancel := context.WithTimeout(ctx, time.Second*defaultTimeoutSeconds)
+	defer cancel()
+
+	_, err := tx.ExecContext(ctx, "INSERT INTO pipeline_events (user_id, object_id, stage, created_at) VALUES (?, ?, ?, ?);", event.UserID, event.ObjectID, event.Stage, event.CreatedAt)
+	if err != nil {
+		r.log.Error().Err(err).Str("method", name).Msg("failed to execute query in")
+		return fmt.Errorf("failed to execute query in %s: %w", name, err)
+	}
+
+	return nil
+}
+
+func (r *ListingsRepository) MGetStageEventByUserIDAndObjectID(ctx context.Context, userID, objectID string) (listings.StageEvents, error) {
+	const name = "ListingsRepository.MGetStageEventByUserIDAndObjectID"
+	ctx, cancel := context.WithTimeout(ctx, time.Second*defaultTimeoutSeconds)
+	defer cancel()
+
+	result := make(listings.StageEvents, 0, defaultCapacity)
+	rows, err := r.db.QueryContext(ctx, "SELECT user_id, object_id, stage, created_at FROM pipeline_events WHERE user_id = ? AND object_id = ? ORDER BY created_at;", userID, objectID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return result, nil
+		}
+		r.log.Error().Err(err).Str("method", name).Msg("failed to execute query in")
+		return nil, fmt.Errorf("failed to execute query in %s: %w", name, err)
+	}
+	defer rows.Close()
+
+	// iterate over rows
+	for rows.Next() {
+		var entry listings.StageEvent
+		if err = rows.Scan(&entry.UserID, &entry.ObjectID, &entry.Stage, &entry.CreatedAt); err != nil {
+			r.log.Error().Err(err).Str("method", name).Msg("failed to scan a row in")
+			return nil, fmt.Errorf("failed to scan a row in %s: %w", name, err)
+		}
+		result = append(result, entry)
+	}
+	if err = rows.Err(); err != nil {
+		r.log.Error().Err(err).Str("method", name).Msg("failed to iterate over rows in")
+		return nil, fmt.Errorf("failed to iterate over rows in %s: %w", name, err)
+	}
+
+	return result, nil
+}
+
+func (r *ListingsRepository) DeleteStageEventByUserIDAndObjectIDTx(ctx context.Context, tx domain.Tx, userID, objectID string) error {
+	const name = "ListingsRepository.DeleteStageEventByUserIDAndObjectIDTx"
+	ctx, cancel := context.WithTimeout(ctx, time.Second*defaultTimeoutSeconds)
+	defer cancel()
+
+	_, err := tx.ExecContext(ctx, "DELETE FROM pipeline_events WHERE user_id = ? AND object_id = ?;", userID, objectID)
+	if err != nil {
+		r.log.Error().Err(err).Str("method", name).Msg("failed to execute query in")
+		return fmt.Errorf("failed to execute query in %s: %w", name, err)
+	}
+
+	return nil
+}
+
+func (r *ListingsRepository) MDeleteStageEventByUserIDTx(ctx context.Context, tx domain.Tx, userID string) error {
+	const name = "ListingsRepository.MDeleteStageEventByUserIDTx"
+	ctx, cancel := context.WithTimeout(ctx, time.Second*defaultTimeoutSeconds)
+	defer cancel()
+
+	_, err := tx.ExecContext(ctx, "DELETE FROM pipeline_events WHERE user_id = ?;", userID)
+	if err != nil {
+		r.log.Error().Err(err).Str("method", name).Msg("failed to execute query in")
+		return fmt.Errorf("failed to execute query in %s: %w", name, err)
+	}
+
+	return nil
+}
+
+func (r *ListingsRepository) MInsertSeenListingTx(ctx context.Context, tx domain.Tx, userID string, objectIDs []string, seenAt time.Time) error {
+	const name = "ListingsRepository.MInsertSeenListingTx"
+	ctx, cancel := context.WithTimeout(ctx, time.Second*defaultTimeoutSeconds)
+	defer cancel()
+
+	if len(objectIDs) == 0 {
+		return nil
+	}
+
+	stmt, err := tx.PrepareContext(ctx, "INSERT INTO seen_listings (user_id, object_id, seen_at) VALUES (?, ?, ?) ON CONFLICT (user_id, object_id) DO NOTHING;")
+	if err != nil {
+		r.log.Error().Err(err).Str("method", name).Msg("failed to prepare statement in")
+		return fmt.Errorf("failed to prepare statement in %s: %w", name, err)
+	}
+	defer stmt.Close()
+
+	for idx := range objectIDs {
+		_, err = stmt.ExecContext(ctx, userID, objectIDs[idx], seenAt)
+		if err != nil {
+			r.log.Error().Err(err).Str("method", name).Msg("failed to execute query in")
+			return fmt.Errorf("failed to execute query in %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+func (r *ListingsRepository) MGetSeenObjectIDByUserID(ctx context.Context, userID string) ([]string, error) {
+	const name = "ListingsRepository.MGetSeenObjectIDByUserID"
+	ctx, cancel := context.WithTimeout(ctx, time.Second*defaultTimeoutSeconds)
+	defer cancel()
+
+	result := make([]string, 0, defaultCapacity)
+	rows, err := r.db.QueryContext(ctx, "SELECT object_id FROM seen_listings WHERE user_id = ?;", userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return result, nil
+		}
+		r.log.Error().Err(err).Str("method", name).Msg("failed to execute query in")
+		return nil, fmt.Errorf("failed to execute query in %s: %w", name, err)
+	}
+	defer rows.Close()
+
+	// iterate over rows
+	for rows.Next() {
+		var objectID string
+		if err = rows.Scan(&objectID); err != nil {
+			r.log.Error().Err(err).Str("method", name).Msg("failed to scan a row in")
+			return nil, fmt.Errorf("failed to scan a row in %s: %w", name, err)
+		}
+		result = append(result, objectID)
+	}
+	if err = rows.Err(); err != nil {
+		r.log.Error().Err(err).Str("method", name).Msg("failed to iterate over rows in")
+		return nil, fmt.Errorf("failed to iterate over rows in %s: %w", name, err)
+	}
+
+	return result, nil
+}
+
+func (r *ListingsRepository) MDeleteSeenListingByUserIDTx(ctx context.Context, tx domain.Tx, userID string) error {
+	const name = "ListingsRepository.MDeleteSeenListingByUserIDTx"
+	ctx, cancel := context.WithTimeout(ctx, time.Second*defaultTimeoutSeconds)
+	defer cancel()
+
+	_, err := tx.ExecContext(ctx, "DELETE FROM seen_listings WHERE user_id = ?;", userID)
+	if err != nil {
+		r.log.Error().Err(err).Str("method", name).Msg("failed to execute query in")
+		return fmt.Errorf("failed to execute query in %s: %w", name, err)
+	}
+
+	return nil
+}
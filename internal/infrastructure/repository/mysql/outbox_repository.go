@@ -0,0 +1,107 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"fundaNotifier/internal/domain"
+	"fundaNotifier/internal/domain/outbox"
+	"time"
+)
+
+var _ outbox.Repository = (*OutboxRepository)(nil)
+
+type OutboxRepository struct {
+	*Repository
+}
+
+func NewOutboxRepository(repository *Repository) *OutboxRepository {
+	return &OutboxRepository{
+		Repository: repository,
+	}
+}
+
+func (r *OutboxRepository) InsertMessage(ctx context.Context, message *outbox.Message) error {
+	const name = "OutboxRepository.InsertMessage"
+	ctx, cancel := context.WithTimeout(ctx, time.Second*defaultTimeoutSeconds)
+	defer cancel()
+
+	_, err := r.db.ExecContext(ctx, "INSERT INTO outbox (user_id, message, created_at) VALUES (?, ?, ?);", message.UserID, message.Text, message.CreatedAt)
+	if err != nil {
+		r.log.Error().Err(err).Str("method", name).Msg("failed to execute query in")
+		return fmt.Errorf("failed to execute query in %s: %w", name, err)
+	}
+
+	return nil
+}
+
+func (r *OutboxRepository) MGetMessageByUserID(ctx context.Context, userID string) (outbox.Messages, error) {
+	const name = "OutboxRepository.MGetMessageByUserID"
+	ctx, cancel := context.WithTimeout(ctx, time.Second*defaultTimeoutSeconds)
+	defer cancel()
+
+	result := make(outbox.Messages, 0, defaultCapacity)
+	rows, err := r.db.QueryContext(ctx, "SELECT id, user_id, message, created_at FROM outbox WHERE user_id = ? ORDER BY id;", userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return result, nil
+		}
+		r.log.Error().Err(err).Str("method", name).Msg("failed to execute query in")
+		return nil, fmt.Errorf("failed to execute query in %s: %w", name, err)
+	}
+	defer rows.Close()
+
+	// iterate over rows
+	for rows.Next() {
+		var entry outbox.Message
+		if err = rows.Scan(&entry.ID, &entry.UserID, &entry.Text, &entry.CreatedAt); err != nil {
+			r.log.Error().Err(err).Str("method", name).Msg("failed to scan a row in")
+			return nil, fmt.Errorf("failed to scan a row in %s: %w", name, err)
+		}
+		result = append(result, entry)
+	}
+	if err = rows.Err(); err != nil {
+		r.log.Error().Err(err).Str("method", name).Msg("failed to iterate over rows in")
+		return nil, fmt.Errorf("failed to iterate over rows in %s: %w", name, err)
+	}
+
+	return result, nil
+}
+
+func (r *OutboxRepository) MDeleteMessageByUserIDAndIDs(ctx context.Context, userID string, IDs []int64) error {
+	const name = "OutboxRepository.MDeleteMessageByUserIDAndIDs"
+	ctx, cancel := context.WithTimeout(ctx, time.Second*defaultTimeoutSeconds)
+	defer cancel()
+
+	stmt, err := r.db.PrepareContext(ctx, "DELETE FROM outbox WHERE user_id = ? AND id = ?;")
+	if err != nil {
+		r.log.Error().Err(err).Str("method", name).Msg("failed to prepare statement in")
+		return fmt.Errorf("failed to prepare statement in %s: %w", name, err)
+	}
+	defer stmt.Close()
+
+	for idx := range IDs {
+		_, err = stmt.ExecContext(ctx, userID, IDs[idx])
+		if err != nil {
+			r.log.Error().Err(err).Str("method", name).Msg("failed to execute query in")
+			return fmt.Errorf("failed to execute query in %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+func (r *OutboxRepository) MDeleteMessageByUserIDTx(ctx context.Context, tx domain.Tx, userID string) error {
+	const name = "OutboxRepository.MDeleteMessageByUserIDTx"
+	ctx, cancel := context.WithTimeout(ctx, time.Second*defaultTimeoutSeconds)
+	defer cancel()
+
+	_, err := tx.ExecContext(ctx, "DELETE FROM outbox WHERE user_id = ?;", userID)
+	if err != nil {
+		r.log.Error().Err(err).Str("method", name).Msg("failed to execute query in")
+		return fmt.Errorf("failed to execute query in %s: %w", name, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,153 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"fundaNotifier/internal/domain"
+	"fundaNotifier/internal/domain/preferences"
+	"time"
+)
+
+var _ preferences.Repository = (*PreferencesRepository)(nil)
+
+type PreferencesRepository struct {
+	*Repository
+}
+
+func NewPreferencesRepository(repository *Repository) *PreferencesRepository {
+	return &PreferencesRepository{
+		Repository: repository,
+	}
+}
+
+func (r *PreferencesRepository) UpsertFeedback(ctx context.Context, feedback *preferences.Feedback) error {
+	const name = "PreferencesRepository.UpsertFeedback"
+	ctx, cancel := context.WithTimeout(ctx, time.Second*defaultTimeoutSeconds)
+	defer cancel()
+
+	_, err := r.db.ExecContext(ctx, "INSERT INTO listing_feedback (user_id, object_id, liked, features, created_at) VALUES (?, ?, ?, ?, ?) ON CONFLICT (user_id, object_id) DO UPDATE SET liked = excluded.liked, features = excluded.features, created_at = excluded.created_at;", feedback.UserID, feedback.ObjectID, feedback.Liked, feedback.FeaturesRaw, feedback.CreatedAt)
+	if err != nil {
+		r.log.Error().Err(err).Str("method", name).Msg("failed to execute query in")
+		return fmt.Errorf("failed to execute query in %s: %w", name, err)
+	}
+
+	return nil
+}
+
+func (r *PreferencesRepository) MGetFeedbackByUserID(ctx context.Context, userID string) (preferences.Feedbacks, error) {
+	const name = "PreferencesRepository.MGetFeedbackByUserID"
+	ctx, cancel := context.WithTimeout(ctx, time.Second*defaultTimeoutSeconds)
+	defer cancel()
+
+	result := make(preferences.Feedbacks, 0, defaultCapacity)
+	rows, err := r.db.QueryContext(ctx, "SELECT user_id, object_id, liked, features, created_at FROM listing_feedback WHERE user_id = ? ORDER BY created_at;", userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return result, nil
+		}
+		r.log.Error().Err(err).Str("method", name).Msg("failed to execute query in")
+		return nil, fmt.Errorf("failed to execute query in %s: %w", name, err)
+	}
+	defer rows.Close()
+
+	// iterate over rows
+	for rows.Next() {
+		var entry preferences.Feedback
+		if err = rows.Scan(&entry.UserID, &entry.ObjectID, &entry.Liked, &entry.FeaturesRaw, &entry.CreatedAt); err != nil {
+			r.log.Error().Err(err).Str("method", name).Msg("failed to scan a row in")
+			return nil, fmt.Errorf("failed to scan a row in %s: %w", name, err)
+		}
+		if err = entry.ParseRaw(); err != nil {
+			r.log.Error().Err(err).Str("method", name).Msg("failed to parse a row in")
+			return nil, fmt.Errorf("failed to parse a row in %s: %w", name, err)
+		}
+		result = append(result, entry)
+	}
+	if err = rows.Err(); err != nil {
+		r.log.Error().Err(err).Str("method", name).Msg("failed to iterate over rows in")
+		return nil, fmt.Errorf("failed to iterate over rows in %s: %w", name, err)
+	}
+
+	return result, nil
+}
+
+func (r *PreferencesRepository) MDeleteFeedbackByUserID(ctx context.Context, userID string) error {
+	const name = "PreferencesRepository.MDeleteFeedbackByUserID"
+	ctx, cancel := context.WithTimeout(ctx, time.Second*defaultTimeoutSeconds)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, "DELETE FROM listing_feedback WHERE user_id = ?;", userID)
+	if err != nil {
+		r.log.Error().Err(err).Str("method", name).Msg("failed to execute query in")
+		return fmt.Errorf("failed to execute query in %s: %w", name, err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		r.log.Error().Err(err).Str("method", name).Msg("failed to get affected rows in")
+		return fmt.Errorf("failed to get affected rows in %s: %w", name, err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("no rows were deleted in %s: %w", name, sql.ErrNoRows)
+	}
+
+	return nil
+}
+
+func (r *PreferencesRepository) MDeleteFeedbackByUserIDTx(ctx context.Context, tx domain.Tx, userID string) error {
+	const name = "PreferencesRepository.MDeleteFeedbackByUserIDTx"
+	ctx, cancel := context.WithTimeout(ctx, time.Second*defaultTimeoutSeconds)
+	defer cancel()
+
+	_, err := tx.ExecContext(ctx, "DELETE FROM listing_feedback WHERE user_id = ?;", userID)
+	if err != nil {
+		r.log.Error().Err(err).Str("method", name).Msg("failed to execute query in")
+		return fmt.Errorf("failed to execute query in %s: %w", name, err)
+	}
+
+	return nil
+}
+
+func (r *PreferencesRepository) UpsertSettings(ctx context.Context, settings *preferences.Settings) error {
+	const name = "PreferencesRepository.UpsertSettings"
+	ctx, cancel := context.WithTimeout(ctx, time.Second*defaultTimeoutSeconds)
+	defer cancel()
+
+	_, err := r.db.ExecContext(ctx, "INSERT INTO preference_settings (user_id, threshold) VALUES (?, ?) ON CONFLICT (user_id) DO UPDATE SET threshold = excluded.threshold;", settings.UserID, settings.Threshold)
+	if err != nil {
+		r.log.Error().Err(err).Str("method", name).Msg("failed to execute query in")
+		return fmt.Errorf("failed to execute query in %s: %w", name, err)
+	}
+
+	return nil
+}
+
+func (r *PreferencesRepository) GetSettingsByUserID(ctx context.Context, userID string) (*preferences.Settings, error) {
+	const name = "PreferencesRepository.GetSettingsByUserID"
+	ctx, cancel := context.WithTimeout(ctx, time.Second*defaultTimeoutSeconds)
+	defer cancel()
+
+	var settings preferences.Settings
+	err := r.db.QueryRowContext(ctx, "SELECT user_id, threshold FROM preference_settings WHERE user_id = ?;", userID).Scan(&settings.UserID, &settings.Threshold)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan a row in %s: %w", name, err)
+	}
+
+	return &settings, nil
+}
+
+func (r *PreferencesRepository) DeleteSettingsByUserIDTx(ctx context.Context, tx domain.Tx, userID string) error {
+	const name = "PreferencesRepository.DeleteSettingsByUserIDTx"
+	ctx, cancel := context.WithTimeout(ctx, time.Second*defaultTimeoutSeconds)
+	defer cancel()
+
+	_, err := tx.ExecContext(ctx, "DELETE FROM preference_settings WHERE user_id = ?;", userID)
+	if err != nil {
+		r.log.Error().Err(err).Str("method", name).Msg("failed to execute query in")
+		return fmt.Errorf("failed to execute query in %s: %w", name, err)
+	}
+
+	return nil
+}
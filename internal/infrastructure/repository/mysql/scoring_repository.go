@@ -0,0 +1,88 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"fundaNotifier/internal/domain"
+	"fundaNotifier/internal/domain/scoring"
+	"time"
+)
+
+var _ scoring.Repository = (*ScoringRepository)(nil)
+
+type ScoringRepository struct {
+	*Repository
+}
+
+func NewScoringRepository(repository *Repository) *ScoringRepository {
+	return &ScoringRepository{
+		Repository: repository,
+	}
+}
+
+func (r *ScoringRepository) UpsertProfile(ctx context.Context, profile *scoring.Profile) error {
+	const name = "ScoringRepository.UpsertProfile"
+	ctx, cancel := context.WithTimeout(ctx, time.Second*defaultTimeoutSeconds)
+	defer cancel()
+
+	_, err := r.db.ExecContext(ctx, "INSERT INTO scoring_profiles (user_id, weights, keywords, points) VALUES (?, ?, ?, ?) ON CONFLICT (user_id) DO UPDATE SET weights = excluded.weights, keywords = excluded.keywords, points = excluded.points;", profile.UserID, profile.WeightsRaw, profile.KeywordsRaw, profile.PointsRaw)
+	if err != nil {
+		r.log.Error().Err(err).Str("method", name).Msg("failed to execute query in")
+		return fmt.Errorf("failed to execute query in %s: %w", name, err)
+	}
+
+	return nil
+}
+
+func (r *ScoringRepository) GetProfileByUserID(ctx context.Context, userID string) (*scoring.Profile, error) {
+	const name = "ScoringRepository.GetProfileByUserID"
+	ctx, cancel := context.WithTimeout(ctx, time.Second*defaultTimeoutSeconds)
+	defer cancel()
+
+	var profile scoring.Profile
+	err := r.db.QueryRowContext(ctx, "SELECT user_id, weights, keywords, points FROM scoring_profiles WHERE user_id = ?;", userID).Scan(&profile.UserID, &profile.WeightsRaw, &profile.KeywordsRaw, &profile.PointsRaw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan a row in %s: %w", name, err)
+	}
+	profile.ParseRaw()
+
+	return &profile, nil
+}
+
+func (r *ScoringRepository) DeleteProfileByUserID(ctx context.Context, userID string) error {
+	const name = "ScoringRepository.DeleteProfileByUserID"
+	ctx, cancel := context.WithTimeout(ctx, time.Second*defaultTimeoutSeconds)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, "DELETE FROM scoring_profiles WHERE user_id = ?;", userID)
+	if err != nil {
+		r.log.Error().Err(err).Str("method", name).Msg("failed to execute query in")
+		return fmt.Errorf("failed to execute query in %s: %w", name, err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		r.log.Error().Err(err).Str("method", name).Msg("failed to get affected rows in")
+		return fmt.Errorf("failed to get affected rows in %s: %w", name, err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("no rows were deleted in %s: %w", name, sql.ErrNoRows)
+	}
+
+	return nil
+}
+
+func (r *ScoringRepository) DeleteProfileByUserIDTx(ctx context.Context, tx domain.Tx, userID string) error {
+	const name = "ScoringRepository.DeleteProfileByUserIDTx"
+	ctx, cancel := context.WithTimeout(ctx, time.Second*defaultTimeoutSeconds)
+	defer cancel()
+
+	_, err := tx.ExecContext(ctx, "DELETE FROM scoring_profiles WHERE user_id = ?;", userID)
+	if err != nil {
+		r.log.Error().Err(err).Str("method", name).Msg("failed to execute query in")
+		return fmt.Errorf("failed to execute query in %s: %w", name, err)
+	}
+
+	return nil
+}
@@ -2,6 +2,8 @@ package mysql
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"fundaNotifier/internal/domain"
 	"fundaNotifier/internal/domain/search_queries"
@@ -20,12 +22,12 @@ func NewSearchQueriesRepository(repository *Repository) *SearchQueriesRepository
 	}
 }
 
-func (r *SearchQueriesRepository) UpsertSearchQueryByUserID(ctx context.Context, userID, searchQuery string) error {
-	const name = "SearchQueriesRepository.UpsertSearchQueryByUserID"
+func (r *SearchQueriesRepository) UpsertSearchQueryTx(ctx context.Context, tx domain.Tx, query *search_queries.SearchQuery) error {
+	const name = "SearchQueriesRepository.UpsertSearchQueryTx"
 	ctx, cancel := context.WithTimeout(ctx, time.Second*defaultTimeoutSeconds)
 	defer cancel()
 
-	_, err := r.db.ExecContext(ctx, "INSERT INTO search_queries (user_id, search_query) VALUES (?, ?) ON CONFLICT (user_id) DO UPDATE SET search_query = excluded.search_query;", userID, searchQuery)
+	_, err := tx.ExecContext(ctx, "INSERT INTO search_queries (user_id, name, search_query, version, is_active) VALUES (?, ?, ?, ?, ?) ON CONFLICT (user_id, name) DO UPDATE SET search_query = excluded.search_query, version = excluded.version;", query.UserID, query.Name, query.URL, query.Version, query.IsActive)
 	if err != nil {
 		r.log.Error().Err(err).Str("method", name).Msg("failed to execute query in")
 		return fmt.Errorf("failed to execute query in %s: %w", name, err)
@@ -34,6 +36,97 @@ func (r *SearchQueriesRepository) UpsertSearchQueryByUserID(ctx context.Context,
 	return nil
 }
 
+func (r *SearchQueriesRepository) GetSearchQueryByUserIDAndName(ctx context.Context, userID, queryName string) (*search_queries.SearchQuery, error) {
+	const name = "SearchQueriesRepository.GetSearchQueryByUserIDAndName"
+	ctx, cancel := context.WithTimeout(ctx, time.Second*defaultTimeoutSeconds)
+	defer cancel()
+
+	var entry search_queries.SearchQuery
+	err := r.db.QueryRowContext(ctx, "SELECT user_id, name, search_query, version, is_active, regions, cities FROM search_queries WHERE user_id = ? AND name = ?;", userID, queryName).Scan(&entry.UserID, &entry.Name, &entry.URL, &entry.Version, &entry.IsActive, &entry.RegionsRaw, &entry.CitiesRaw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan a row in %s: %w", name, err)
+	}
+	entry.ParseRawRegionsAndCities()
+
+	return &entry, nil
+}
+
+func (r *SearchQueriesRepository) MGetSearchQueryByUserID(ctx context.Context, userID string) (search_queries.SearchQueries, error) {
+	const name = "SearchQueriesRepository.MGetSearchQueryByUserID"
+	ctx, cancel := context.WithTimeout(ctx, time.Second*defaultTimeoutSeconds)
+	defer cancel()
+
+	result := make(search_queries.SearchQueries, 0, defaultCapacity)
+	rows, err := r.db.QueryContext(ctx, "SELECT user_id, name, search_query, version, is_active, regions, cities FROM search_queries WHERE user_id = ? ORDER BY name;", userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return result, nil
+		}
+		r.log.Error().Err(err).Str("method", name).Msg("failed to execute query in")
+		return nil, fmt.Errorf("failed to execute query in %s: %w", name, err)
+	}
+	defer rows.Close()
+
+	// iterate over rows
+	for rows.Next() {
+		var entry search_queries.SearchQuery
+		if err = rows.Scan(&entry.UserID, &entry.Name, &entry.URL, &entry.Version, &entry.IsActive, &entry.RegionsRaw, &entry.CitiesRaw); err != nil {
+			r.log.Error().Err(err).Str("method", name).Msg("failed to scan a row in")
+			return nil, fmt.Errorf("failed to scan a row in %s: %w", name, err)
+		}
+		entry.ParseRawRegionsAndCities()
+		result = append(result, entry)
+	}
+	if err = rows.Err(); err != nil {
+		r.log.Error().Err(err).Str("method", name).Msg("failed to iterate over rows in")
+		return nil, fmt.Errorf("failed to iterate over rows in %s: %w", name, err)
+	}
+
+	return result, nil
+}
+
+func (r *SearchQueriesRepository) UpdateSearchQueryIsActiveByUserIDAndName(ctx context.Context, userID, queryName string, isActive bool) error {
+	const name = "SearchQueriesRepository.UpdateSearchQueryIsActiveByUserIDAndName"
+	ctx, cancel := context.WithTimeout(ctx, time.Second*defaultTimeoutSeconds)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, "UPDATE search_queries SET is_active = ? WHERE user_id = ? AND name = ?;", isActive, userID, queryName)
+	if err != nil {
+		r.log.Error().Err(err).Str("method", name).Msg("failed to execute query in")
+		return fmt.Errorf("failed to execute query in %s: %w", name, err)
+	}
+
+	return r.checkAffected(name, result)
+}
+
+func (r *SearchQueriesRepository) UpdateSearchQueryFiltersByUserIDAndName(ctx context.Context, userID, queryName, regions, cities string) error {
+	const name = "SearchQueriesRepository.UpdateSearchQueryFiltersByUserIDAndName"
+	ctx, cancel := context.WithTimeout(ctx, time.Second*defaultTimeoutSeconds)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, "UPDATE search_queries SET regions = ?, cities = ? WHERE user_id = ? AND name = ?;", regions, cities, userID, queryName)
+	if err != nil {
+		r.log.Error().Err(err).Str("method", name).Msg("failed to execute query in")
+		return fmt.Errorf("failed to execute query in %s: %w", name, err)
+	}
+
+	return r.checkAffected(name, result)
+}
+
+func (r *SearchQueriesRepository) DeleteSearchQueryByUserIDAndNameTx(ctx context.Context, tx domain.Tx, userID, queryName string) error {
+	const name = "SearchQueriesRepository.DeleteSearchQueryByUserIDAndNameTx"
+	ctx, cancel := context.WithTimeout(ctx, time.Second*defaultTimeoutSeconds)
+	defer cancel()
+
+	result, err := tx.ExecContext(ctx, "DELETE FROM search_queries WHERE user_id = ? AND name = ?;", userID, queryName)
+	if err != nil {
+		r.log.Error().Err(err).Str("method", name).Msg("failed to execute query in")
+		return fmt.Errorf("failed to execute query in %s: %w", name, err)
+	}
+
+	return r.checkAffected(name, result)
+}
+
 func (r *SearchQueriesRepository) DeleteSearchQueryByUserIDTx(ctx context.Context, tx domain.Tx, userID string) error {
 	const name = "SearchQueriesRepository.DeleteSearchQueryByUserID"
 	ctx, cancel := context.WithTimeout(ctx, time.Second*defaultTimeoutSeconds)
@@ -48,17 +141,118 @@ func (r *SearchQueriesRepository) DeleteSearchQueryByUserIDTx(ctx context.Contex
 	return nil
 }
 
-func (r *SearchQueriesRepository) GetSearchQueryByUserID(ctx context.Context, userID string) (string, error) {
-	const name = "SearchQueriesRepository.GetSearchQueryByUserID"
+func (r *SearchQueriesRepository) InsertSearchQueryVersionTx(ctx context.Context, tx domain.Tx, version *search_queries.Version) error {
+	const name = "SearchQueriesRepository.InsertSearchQueryVersionTx"
+	ctx, cancel := context.WithTimeout(ctx, time.Second*defaultTimeoutSeconds)
+	defer cancel()
+
+	_, err := tx.ExecContext(ctx, "INSERT INTO search_query_versions (user_id, query_name, version, search_query, created_at) VALUES (?, ?, ?, ?, ?);", version.UserID, version.QueryName, version.Number, version.URL, version.CreatedAt)
+	if err != nil {
+		r.log.Error().Err(err).Str("method", name).Msg("failed to execute query in")
+		return fmt.Errorf("failed to execute query in %s: %w", name, err)
+	}
+
+	return nil
+}
+
+func (r *SearchQueriesRepository) GetLatestSearchQueryVersionByUserIDAndNameTx(ctx context.Context, tx domain.Tx, userID, queryName string) (*search_queries.Version, error) {
+	const name = "SearchQueriesRepository.GetLatestSearchQueryVersionByUserIDAndNameTx"
+	ctx, cancel := context.WithTimeout(ctx, time.Second*defaultTimeoutSeconds)
+	defer cancel()
+
+	var entry search_queries.Version
+	err := tx.QueryRowContext(ctx, "SELECT user_id, query_name, version, search_query, created_at FROM search_query_versions WHERE user_id = ? AND query_name = ? ORDER BY version DESC LIMIT 1;", userID, queryName).Scan(&entry.UserID, &entry.QueryName, &entry.Number, &entry.URL, &entry.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan a row in %s: %w", name, err)
+	}
+
+	return &entry, nil
+}
+
+func (r *SearchQueriesRepository) GetSearchQueryVersionByUserIDAndNameAndNumber(ctx context.Context, userID, queryName string, number int) (*search_queries.Version, error) {
+	const name = "SearchQueriesRepository.GetSearchQueryVersionByUserIDAndNameAndNumber"
 	ctx, cancel := context.WithTimeout(ctx, time.Second*defaultTimeoutSeconds)
 	defer cancel()
 
-	var searchQuery string
-	err := r.db.QueryRowContext(ctx, "SELECT search_query FROM search_queries WHERE user_id = ?;", userID).Scan(&searchQuery)
+	var entry search_queries.Version
+	err := r.db.QueryRowContext(ctx, "SELECT user_id, query_name, version, search_query, created_at FROM search_query_versions WHERE user_id = ? AND query_name = ? AND version = ?;", userID, queryName, number).Scan(&entry.UserID, &entry.QueryName, &entry.Number, &entry.URL, &entry.CreatedAt)
 	if err != nil {
-		r.log.Error().Err(err).Str("method", name).Msg("failed to scan a row in")
-		return searchQuery, fmt.Errorf("failed to scan a row in %s: %w", name, err)
+		return nil, fmt.Errorf("failed to scan a row in %s: %w", name, err)
 	}
 
-	return searchQuery, nil
+	return &entry, nil
+}
+
+func (r *SearchQueriesRepository) MGetSearchQueryVersionByUserIDAndName(ctx context.Context, userID, queryName string) (search_queries.Versions, error) {
+	const name = "SearchQueriesRepository.MGetSearchQueryVersionByUserIDAndName"
+	ctx, cancel := context.WithTimeout(ctx, time.Second*defaultTimeoutSeconds)
+	defer cancel()
+
+	result := make(search_queries.Versions, 0, defaultCapacity)
+	rows, err := r.db.QueryContext(ctx, "SELECT user_id, query_name, version, search_query, created_at FROM search_query_versions WHERE user_id = ? AND query_name = ? ORDER BY version DESC;", userID, queryName)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return result, nil
+		}
+		r.log.Error().Err(err).Str("method", name).Msg("failed to execute query in")
+		return nil, fmt.Errorf("failed to execute query in %s: %w", name, err)
+	}
+	defer rows.Close()
+
+	// iterate over rows
+	for rows.Next() {
+		var entry search_queries.Version
+		if err = rows.Scan(&entry.UserID, &entry.QueryName, &entry.Number, &entry.URL, &entry.CreatedAt); err != nil {
+			r.log.Error().Err(err).Str("method", name).Msg("failed to scan a row in")
+			return nil, fmt.Errorf("failed to scan a row in %s: %w", name, err)
+		}
+		result = append(result, entry)
+	}
+	if err = rows.Err(); err != nil {
+		r.log.Error().Err(err).Str("method", name).Msg("failed to iterate over rows in")
+		return nil, fmt.Errorf("failed to iterate over rows in %s: %w", name, err)
+	}
+
+	return result, nil
+}
+
+func (r *SearchQueriesRepository) MDeleteSearchQueryVersionByUserIDAndNameTx(ctx context.Context, tx domain.Tx, userID, queryName string) error {
+	const name = "SearchQueriesRepository.MDeleteSearchQueryVersionByUserIDAndNameTx"
+	ctx, cancel := context.WithTimeout(ctx, time.Second*defaultTimeoutSeconds)
+	defer cancel()
+
+	_, err := tx.ExecContext(ctx, "DELETE FROM search_query_versions WHERE user_id = ? AND query_name = ?;", userID, queryName)
+	if err != nil {
+		r.log.Error().Err(err).Str("method", name).Msg("failed to execute query in")
+		return fmt.Errorf("failed to execute query in %s: %w", name, err)
+	}
+
+	return nil
+}
+
+func (r *SearchQueriesRepository) MDeleteSearchQueryVersionByUserIDTx(ctx context.Context, tx domain.Tx, userID string) error {
+	const name = "SearchQueriesRepository.MDeleteSearchQueryVersionByUserIDTx"
+	ctx, cancel := context.WithTimeout(ctx, time.Second*defaultTimeoutSeconds)
+	defer cancel()
+
+	_, err := tx.ExecContext(ctx, "DELETE FROM search_query_versions WHERE user_id = ?;", userID)
+	if err != nil {
+		r.log.Error().Err(err).Str("method", name).Msg("failed to execute query in")
+		return fmt.Errorf("failed to execute query in %s: %w", name, err)
+	}
+
+	return nil
+}
+
+func (r *SearchQueriesRepository) checkAffected(name string, result sql.Result) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		r.log.Error().Err(err).Str("method", name).Msg("failed to get affected rows in")
+		return fmt.Errorf("failed to get affected rows in %s: %w", name, err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("no rows were affected in %s: %w", name, sql.ErrNoRows)
+	}
+
+	return nil
 }
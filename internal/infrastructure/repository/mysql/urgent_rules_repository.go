@@ -0,0 +1,102 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"fundaNotifier/internal/domain"
+	"fundaNotifier/internal/domain/urgent_rules"
+	"time"
+)
+
+var _ urgent_rules.Repository = (*UrgentRulesRepository)(nil)
+
+type UrgentRulesRepository struct {
+	*Repository
+}
+
+func NewUrgentRulesRepository(repository *Repository) *UrgentRulesRepository {
+	return &UrgentRulesRepository{
+		Repository: repository,
+	}
+}
+
+func (r *UrgentRulesRepository) UpsertRule(ctx context.Context, rule *urgent_rules.Rule) error {
+	const name = "UrgentRulesRepository.UpsertRule"
+	ctx, cancel := context.WithTimeout(ctx, time.Second*defaultTimeoutSeconds)
+	defer cancel()
+
+	_, err := r.db.ExecContext(ctx, "INSERT INTO urgent_rules (user_id, regions, cities, max_price, daily_cap) VALUES (?, ?, ?, ?, ?) ON CONFLICT (user_id) DO UPDATE SET regions = excluded.regions, cities = excluded.cities, max_price = excluded.max_price, daily_cap = excluded.daily_cap;", rule.UserID, rule.RegionsRaw, rule.CitiesRaw, rule.MaxPrice, rule.DailyCap)
+	if err != nil {
+		r.log.Error().Err(err).Str("method", name).Msg("failed to execute query in")
+		return fmt.Errorf("failed to execute query in %s: %w", name, err)
+	}
+
+	return nil
+}
+
+func (r *UrgentRulesRepository) GetRuleByUserID(ctx context.Context, userID string) (*urgent_rules.Rule, error) {
+	const name = "UrgentRulesRepository.GetRuleByUserID"
+	ctx, cancel := context.WithTimeout(ctx, time.Second*defaultTimeoutSeconds)
+	defer cancel()
+
+	var rule urgent_rules.Rule
+	err := r.db.QueryRowContext(ctx, "SELECT user_id, regions, cities, max_price, daily_cap, sent_count, sent_on FROM urgent_rules WHERE user_id = ?;", userID).Scan(&rule.UserID, &rule.RegionsRaw, &rule.CitiesRaw, &rule.MaxPrice, &rule.DailyCap, &rule.SentCount, &rule.SentOn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan a row in %s: %w", name, err)
+	}
+	rule.ParseRawRegionsAndCities()
+
+	return &rule, nil
+}
+
+func (r *UrgentRulesRepository) UpdateSentCountByUserID(ctx context.Context, userID, sentOn string, sentCount int) error {
+	const name = "UrgentRulesRepository.UpdateSentCountByUserID"
+	ctx, cancel := context.WithTimeout(ctx, time.Second*defaultTimeoutSeconds)
+	defer cancel()
+
+	_, err := r.db.ExecContext(ctx, "UPDATE urgent_rules SET sent_on = ?, sent_count = ? WHERE user_id = ?;", sentOn, sentCount, userID)
+	if err != nil {
+		r.log.Error().Err(err).Str("method", name).Msg("failed to execute query in")
+		return fmt.Errorf("failed to execute query in %s: %w", name, err)
+	}
+
+	return nil
+}
+
+func (r *UrgentRulesRepository) DeleteRuleByUserID(ctx context.Context, userID string) error {
+	const name = "UrgentRulesRepository.DeleteRuleByUserID"
+	ctx, cancel := context.WithTimeout(ctx, time.Second*defaultTimeoutSeconds)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, "DELETE FROM urgent_rules WHERE user_id = ?;", userID)
+	if err != nil {
+		r.log.Error().Err(err).Str("method", name).Msg("failed to execute query in")
+		return fmt.Errorf("failed to execute query in %s: %w", name, err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		r.log.Error().Err(err).Str("method", name).Msg("failed to get affected rows in")
+		return fmt.Errorf("failed to get affected rows in %s: %w", name, err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("no rows were deleted in %s: %w", name, sql.ErrNoRows)
+	}
+
+	return nil
+}
+
+func (r *UrgentRulesRepository) DeleteRuleByUserIDTx(ctx context.Context, tx domain.Tx, userID string) error {
+	const name = "UrgentRulesRepository.DeleteRuleByUserIDTx"
+	ctx, cancel := context.WithTimeout(ctx, time.Second*defaultTimeoutSeconds)
+	defer cancel()
+
+	_, err := tx.ExecContext(ctx, "DELETE FROM urgent_rules WHERE user_id = ?;", userID)
+	if err != nil {
+		r.log.Error().Err(err).Str("method", name).Msg("failed to execute query in")
+		return fmt.Errorf("failed to execute query in %s: %w", name, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,135 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"fundaNotifier/internal/domain"
+	"fundaNotifier/internal/domain/viewings"
+	"time"
+)
+
+var _ viewings.Repository = (*ViewingsRepository)(nil)
+
+type ViewingsRepository struct {
+	*Repository
+}
+
+func NewViewingsRepository(repository *Repository) *ViewingsRepository {
+	return &ViewingsRepository{
+		Repository: repository,
+	}
+}
+
+func (r *ViewingsRepository) UpsertViewing(ctx context.Context, viewing *viewings.Viewing) error {
+	const name = "ViewingsRepository.UpsertViewing"
+	ctx, cancel := context.WithTimeout(ctx, time.Second*defaultTimeoutSeconds)
+	defer cancel()
+
+	_, err := r.db.ExecContext(ctx, "INSERT INTO viewings (user_id, object_id, name, address, url, starts_at, remind_at, is_reminded, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?) ON CONFLICT (user_id, object_id) DO UPDATE SET name = excluded.name, address = excluded.address, url = excluded.url, starts_at = excluded.starts_at, remind_at = excluded.remind_at, is_reminded = excluded.is_reminded, created_at = excluded.created_at;", viewing.UserID, viewing.ObjectID, viewing.Name, viewing.Address, viewing.URL, viewing.StartsAt, viewing.RemindAt, viewing.IsReminded, viewing.CreatedAt)
+	if err != nil {
+		r.log.Error().Err(err).Str("method", name).Msg("failed to execute query in")
+		return fmt.Errorf("failed to execute query in %s: %w", name, err)
+	}
+
+	return nil
+}
+
+func (r *ViewingsRepository) MGetViewingByUserID(ctx context.Context, userID string) (viewings.Viewings, error) {
+	const name = "ViewingsRepository.MGetViewingByUserID"
+	ctx, cancel := context.WithTimeout(ctx, time.Second*defaultTimeoutSeconds)
+	defer cancel()
+
+	result := make(viewings.Viewings, 0, defaultCapacity)
+	rows, err := r.db.QueryContext(ctx, "SELECT user_id, object_id, name, address, url, starts_at, remind_at, is_reminded, created_at FROM viewings WHERE user_id = ? ORDER BY starts_at;", userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return result, nil
+		}
+		r.log.Error().Err(err).Str("method", name).Msg("failed to execute query in")
+		return nil, fmt.Errorf("failed to execute query in %s: %w", name, err)
+	}
+	defer rows.Close()
+
+	// iterate over rows
+	for rows.Next() {
+		var entry viewings.Viewing
+		if err = rows.Scan(&entry.UserID, &entry.ObjectID, &entry.Name, &entry.Address, &entry.URL, &entry.StartsAt, &entry.RemindAt, &entry.IsReminded, &entry.CreatedAt); err != nil {
+			r.log.Error().Err(err).Str("method", name).Msg("failed to scan a row in")
+			return nil, fmt.Errorf("failed to scan a row in %s: %w", name, err)
+		}
+		result = append(result, entry)
+	}
+	if err = rows.Err(); err != nil {
+		r.log.Error().Err(err).Str("method", name).Msg("failed to iterate over rows in")
+		return nil, fmt.Errorf("failed to iterate over rows in %s: %w", name, err)
+	}
+
+	return result, nil
+}
+
+func (r *ViewingsRepository) UpdateViewingIsReminded(ctx context.Context, viewing *viewings.Viewing) error {
+	const name = "ViewingsRepository.UpdateViewingIsReminded"
+	ctx, cancel := context.WithTimeout(ctx, time.Second*defaultTimeoutSeconds)
+	defer cancel()
+
+	_, err := r.db.ExecContext(ctx, "UPDATE viewings SET is_reminded = ? WHERE user_id = ? AND object_id = ?;", viewing.IsReminded, viewing.UserID, viewing.ObjectID)
+	if err != nil {
+		r.log.Error().Err(err).Str("method", name).Msg("failed to execute query in")
+		return fmt.Errorf("failed to execute query in %s: %w", name, err)
+	}
+
+	return nil
+}
+
+func (r *ViewingsRepository) DeleteViewingByUserIDAndObjectID(ctx context.Context, userID, objectID string) error {
+	const name = "ViewingsRepository.DeleteViewingByUserIDAndObjectID"
+	ctx, cancel := context.WithTimeout(ctx, time.Second*defaultTimeoutSeconds)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, "DELETE FROM viewings WHERE user_id = ? AND object_id = ?;", userID, objectID)
+	if err != nil {
+		r.log.Error().Err(err).Str("method", name).Msg("failed to execute query in")
+		return fmt.Errorf("failed to execute query in %s: %w", name, err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		r.log.Error().Err(err).Str("method", name).Msg("failed to get affected rows in")
+		return fmt.Errorf("failed to get affected rows in %s: %w", name, err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("no rows were deleted in %s: %w", name, sql.ErrNoRows)
+	}
+
+	return nil
+}
+
+func (r *ViewingsRepository) DeleteViewingByUserIDAndObjectIDTx(ctx context.Context, tx domain.Tx, userID, objectID string) error {
+	const name = "ViewingsRepository.DeleteViewingByUserIDAndObjectIDTx"
+	ctx, cancel := context.WithTimeout(ctx, time.Second*defaultTimeoutSeconds)
+	defer cancel()
+
+	_, err := tx.ExecContext(ctx, "DELETE FROM viewings WHERE user_id = ? AND object_id = ?;", userID, objectID)
+	if err != nil {
+		r.log.Error().Err(err).Str("method", name).Msg("failed to execute query in")
+		return fmt.Errorf("failed to execute query in %s: %w", name, err)
+	}
+
+	return nil
+}
+
+func (r *ViewingsRepository) MDeleteViewingByUserIDTx(ctx context.Context, tx domain.Tx, userID string) error {
+	const name = "ViewingsRepository.MDeleteViewingByUserIDTx"
+	ctx, cancel := context.WithTimeout(ctx, time.Second*defaultTimeoutSeconds)
+	defer cancel()
+
+	_, err := tx.ExecContext(ctx, "DELETE FROM viewings WHERE user_id = ?;", userID)
+	if err != nil {
+		r.log.Error().Err(err).Str("method", name).Msg("failed to execute query in")
+		return fmt.Errorf("failed to execute query in %s: %w", name, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,121 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"fundaNotifier/internal/domain"
+	"fundaNotifier/internal/domain/watched_listings"
+	"time"
+)
+
+var _ watched_listings.Repository = (*WatchedListingsRepository)(nil)
+
+type WatchedListingsRepository struct {
+	*Repository
+}
+
+func NewWatchedListingsRepository(repository *Repository) *WatchedListingsRepository {
+	return &WatchedListingsRepository{
+		Repository: repository,
+	}
+}
+
+func (r *WatchedListingsRepository) UpsertWatchedListing(ctx context.Context, listing *watched_listings.WatchedListing) error {
+	const name = "WatchedListingsRepository.UpsertWatchedListing"
+	ctx, cancel := context.WithTimeout(ctx, time.Second*defaultTimeoutSeconds)
+	defer cancel()
+
+	_, err := r.db.ExecContext(ctx, "INSERT INTO watched_listings (user_id, object_id, url, name, city, currency, price, status, is_gone, created_at, checked_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) ON CONFLICT (user_id, object_id) DO UPDATE SET url = excluded.url, name = excluded.name, city = excluded.city, currency = excluded.currency, price = excluded.price, status = excluded.status, is_gone = excluded.is_gone, checked_at = excluded.checked_at;", listing.UserID, listing.ObjectID, listing.URL, listing.Name, listing.City, listing.Currency, listing.Price, listing.Status, listing.IsGone, listing.CreatedAt, listing.CheckedAt)
+	if err != nil {
+		r.log.Error().Err(err).Str("method", name).Msg("failed to execute query in")
+		return fmt.Errorf("failed to execute query in %s: %w", name, err)
+	}
+
+	return nil
+}
+
+func (r *WatchedListingsRepository) MGetWatchedListingByUserID(ctx context.Context, userID string) (watched_listings.WatchedListings, error) {
+	const name = "WatchedListingsRepository.MGetWatchedListingByUserID"
+	ctx, cancel := context.WithTimeout(ctx, time.Second*defaultTimeoutSeconds)
+	defer cancel()
+
+	result := make(watched_listings.WatchedListings, 0, defaultCapacity)
+	rows, err := r.db.QueryContext(ctx, "SELECT user_id, object_id, url, name, city, currency, price, status, is_gone, created_at, checked_at FROM watched_listings WHERE user_id = ? ORDER BY created_at;", userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return result, nil
+		}
+		r.log.Error().Err(err).Str("method", name).Msg("failed to execute query in")
+		return nil, fmt.Errorf("failed to execute query in %s: %w", name, err)
+	}
+	defer rows.Close()
+
+	// iterate over rows
+	for rows.Next() {
+		var entry watched_listings.WatchedListing
+		if err = rows.Scan(&entry.UserID, &entry.ObjectID, &entry.URL, &entry.Name, &entry.City, &entry.Currency, &entry.Price, &entry.Status, &entry.IsGone, &entry.CreatedAt, &entry.CheckedAt); err != nil {
+			r.log.Error().Err(err).Str("method", name).Msg("failed to scan a row in")
+			return nil, fmt.Errorf("failed to scan a row in %s: %w", name, err)
+		}
+		result = append(result, entry)
+	}
+	if err = rows.Err(); err != nil {
+		r.log.Error().Err(err).Str("method", name).Msg("failed to iterate over rows in")
+		return nil, fmt.Errorf("failed to iterate over rows in %s: %w", name, err)
+	}
+
+	return result, nil
+}
+
+func (r *WatchedListingsRepository) UpdateWatchedListing(ctx context.Context, listing *watched_listings.WatchedListing) error {
+	const name = "WatchedListingsRepository.UpdateWatchedListing"
+	ctx, cancel := context.WithTimeout(ctx, time.Second*defaultTimeoutSeconds)
+	defer cancel()
+
+	_, err := r.db.ExecContext(ctx, "UPDATE watched_listings SET url = ?, name = ?, city = ?, currency = ?, price = ?, status = ?, is_gone = ?, checked_at = ? WHERE user_id = ? AND object_id = ?;", listing.URL, listing.Name, listing.City, listing.Currency, listing.Price, listing.Status, listing.IsGone, listing.CheckedAt, listing.UserID, listing.ObjectID)
+	if err != nil {
+		r.log.Error().Err(err).Str("method", name).Msg("failed to execute query in")
+		return fmt.Errorf("failed to execute query in %s: %w", name, err)
+	}
+
+	return nil
+}
+
+func (r *WatchedListingsRepository) DeleteWatchedListingByUserIDAndObjectID(ctx context.Context, userID, objectID string) error {
+	const name = "WatchedListingsRepository.DeleteWatchedListingByUserIDAndObjectID"
+	ctx, cancel := context.WithTimeout(ctx, time.Second*defaultTimeoutSeconds)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, "DELETE FROM watched_listings WHERE user_id = ? AND object_id = ?;", userID, objectID)
+	if err != nil {
+		r.log.Error().Err(err).Str("method", name).Msg("failed to execute query in")
+		return fmt.Errorf("failed to execute query in %s: %w", name, err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		r.log.Error().Err(err).Str("method", name).Msg("failed to get affected rows in")
+		return fmt.Errorf("failed to get affected rows in %s: %w", name, err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("no rows were deleted in %s: %w", name, sql.ErrNoRows)
+	}
+
+	return nil
+}
+
+func (r *WatchedListingsRepository) MDeleteWatchedListingByUserIDTx(ctx context.Context, tx domain.Tx, userID string) error {
+	const name = "WatchedListingsRepository.MDeleteWatchedListingByUserIDTx"
+	ctx, cancel := context.WithTimeout(ctx, time.Second*defaultTimeoutSeconds)
+	defer cancel()
+
+	_, err := tx.ExecContext(ctx, "DELETE FROM watched_listings WHERE user_id = ?;", userID)
+	if err != nil {
+		r.log.Error().Err(err).Str("method", name).Msg("failed to execute query in")
+		return fmt.Errorf("failed to execute query in %s: %w", name, err)
+	}
+
+	return nil
+}
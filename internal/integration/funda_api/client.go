@@ -33,15 +33,33 @@ func NewFundaAPIClient(
 	}
 }
 
+// ResponseError is returned when Funda responds with anything but 200, including redirects which are not followed.
+type ResponseError struct {
+	Code int
+}
+
+func (e *ResponseError) Error() string {
+	return fmt.Sprintf("got response code %d from %s", e.Code, name)
+}
+
+func (e *ResponseError) StatusCode() int {
+	return e.Code
+}
+
 func (c *FundaAPIClient) GetHTMLContent(ctx context.Context, URL string) ([]byte, error) {
 	resp, err := c.client.R().SetContext(ctx).SetHeader("referer", URL).SetHeaders(provideHeaders()).Get(URL)
 	if err != nil {
+		// redirects are not followed, so the response of a redirect comes along with an error
+		if resp != nil && resp.RawResponse != nil && resp.StatusCode() >= http.StatusMultipleChoices && resp.StatusCode() < http.StatusBadRequest {
+			c.log.Warn().Str("client", name).Msg(fmt.Sprintf("got response code %d", resp.StatusCode()))
+			return nil, &ResponseError{Code: resp.StatusCode()}
+		}
 		c.log.Error().Err(err).Str("client", name).Msg("failed to execute request")
 		return nil, fmt.Errorf("failed to execute request in %s: %w", name, err)
 	}
 	if resp.StatusCode() != http.StatusOK {
 		c.log.Warn().Str("client", name).Msg(fmt.Sprintf("got response code %d", resp.StatusCode()))
-		return nil, fmt.Errorf("got response code %d from %s", resp.StatusCode(), name)
+		return nil, &ResponseError{Code: resp.StatusCode()}
 	}
 
 	return resp.Body(), nil
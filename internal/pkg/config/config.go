@@ -1,6 +1,9 @@
 package config
 
 import (
+	"fundaNotifier/internal/domain/listings"
+	"fundaNotifier/internal/domain/viewings"
+	"fundaNotifier/internal/domain/watched_listings"
 	"fundaNotifier/internal/infrastructure"
 	"fundaNotifier/internal/integration"
 	"fundaNotifier/internal/pkg/logger"
@@ -12,8 +15,11 @@ import (
 type Config struct {
 	Infra       infrastructure.Config
 	Integration integration.Config
+	Listings    listings.Config
 	Logger      logger.Config
 	TelegramBot tgbot.Config
+	Viewings    viewings.Config
+	Watched     watched_listings.Config
 }
 
 func NewConfig() *Config {
@@ -0,0 +1,279 @@
+package cron
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	fieldsNb          = 5
+	searchLimitYears  = 5
+	presetSeparator   = " "
+	listSeparator     = ","
+	rangeSeparator    = "-"
+	stepSeparator     = "/"
+	wildcard          = "*"
+	alternateWildcard = "?"
+)
+
+// presets maps human-friendly schedule names to cron expressions.
+var presets = map[string]string{
+	"hourly":              "0 * * * *",
+	"daily":               "0 9 * * *",
+	"aggressive mornings": "*/15 6-10 * * *",
+	"working hours":       "*/30 8-18 * * 1-5",
+	"evenings":            "*/30 18-23 * * *",
+	"weekends":            "0 9-22 * * 0,6",
+}
+
+type bounds struct {
+	min, max int
+	names    map[string]int
+}
+
+var (
+	minuteBounds = bounds{min: 0, max: 59}
+	hourBounds   = bounds{min: 0, max: 23}
+	domBounds    = bounds{min: 1, max: 31}
+	monthBounds  = bounds{min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	dowBounds = bounds{min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+// Schedule is a parsed five-field cron expression (minute, hour, day of month, month, day of week).
+type Schedule struct {
+	Expression string
+	minute     uint64
+	hour       uint64
+	dom        uint64
+	month      uint64
+	dow        uint64
+	domStar    bool
+	dowStar    bool
+}
+
+// Parse parses either a cron expression or a preset name.
+func Parse(expression string) (*Schedule, error) {
+	expression = strings.TrimSpace(expression)
+	if preset, ok := lookupPreset(expression); ok {
+		expression = preset
+	}
+
+	fields := strings.Fields(expression)
+	if len(fields) != fieldsNb {
+		return nil, fmt.Errorf("expected %d fields, got %d in %q", fieldsNb, len(fields), expression)
+	}
+
+	var (
+		schedule = Schedule{Expression: strings.Join(fields, presetSeparator)}
+		err      error
+	)
+	if schedule.minute, err = parseField(fields[0], minuteBounds); err != nil {
+		return nil, fmt.Errorf("invalid minute field: %w", err)
+	}
+	if schedule.hour, err = parseField(fields[1], hourBounds); err != nil {
+		return nil, fmt.Errorf("invalid hour field: %w", err)
+	}
+	if schedule.dom, err = parseField(fields[2], domBounds); err != nil {
+		return nil, fmt.Errorf("invalid day of month field: %w", err)
+	}
+	if schedule.month, err = parseField(fields[3], monthBounds); err != nil {
+		return nil, fmt.Errorf("invalid month field: %w", err)
+	}
+	if schedule.dow, err = parseField(fields[4], dowBounds); err != nil {
+		return nil, fmt.Errorf("invalid day of week field: %w", err)
+	}
+	// both 0 and 7 stand for Sunday
+	if schedule.dow&(1<<7) != 0 {
+		schedule.dow |= 1
+	}
+	schedule.domStar = isWildcard(fields[2])
+	schedule.dowStar = isWildcard(fields[4])
+
+	return &schedule, nil
+}
+
+// PresetNames returns names of all available presets in alphabetical order.
+func PresetNames() []string {
+	names := make([]string, 0, len(presets))
+	for name := range presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Next returns the first activation time strictly after t, evaluated in t's location.
+// A zero time is returned when no activation is found within the search limit.
+func (s *Schedule) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, loc).Add(time.Minute)
+	yearLimit := t.Year() + searchLimitYears
+
+WRAP:
+	if t.Year() > yearLimit {
+		return time.Time{}
+	}
+
+	for s.month&(1<<uint(t.Month())) == 0 {
+		t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+		if t.Month() == time.January {
+			goto WRAP
+		}
+	}
+
+	for !s.dayMatches(t) {
+		t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+		if t.Day() == 1 {
+			goto WRAP
+		}
+	}
+
+	for s.hour&(1<<uint(t.Hour())) == 0 {
+		t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+		if t.Hour() == 0 {
+			goto WRAP
+		}
+	}
+
+	for s.minute&(1<<uint(t.Minute())) == 0 {
+		t = t.Add(time.Minute)
+		if t.Minute() == 0 {
+			goto WRAP
+		}
+	}
+
+	return t
+}
+
+// NextN returns up to n consecutive activation times after t.
+func (s *Schedule) NextN(t time.Time, n int) []time.Time {
+	result := make([]time.Time, 0, n)
+	for len(result) < n {
+		t = s.Next(t)
+		if t.IsZero() {
+			break
+		}
+		result = append(result, t)
+	}
+	return result
+}
+
+// MinGap returns the shortest gap between n consecutive activations after t.
+func (s *Schedule) MinGap(t time.Time, n int) time.Duration {
+	runs := s.NextN(t, n)
+	var minGap time.Duration
+	for idx := 1; idx < len(runs); idx++ {
+		gap := runs[idx].Sub(runs[idx-1])
+		if minGap == 0 || gap < minGap {
+			minGap = gap
+		}
+	}
+	return minGap
+}
+
+func (s *Schedule) dayMatches(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domStar || s.dowStar {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
+
+func lookupPreset(name string) (string, bool) {
+	name = strings.ToLower(name)
+	name = strings.NewReplacer("_", presetSeparator, "-", presetSeparator).Replace(name)
+	name = strings.TrimPrefix(name, "@")
+	expression, ok := presets[strings.Join(strings.Fields(name), presetSeparator)]
+	return expression, ok
+}
+
+func isWildcard(field string) bool {
+	return field == wildcard || field == alternateWildcard
+}
+
+func parseField(field string, b bounds) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, listSeparator) {
+		partBits, err := parsePart(part, b)
+		if err != nil {
+			return 0, err
+		}
+		bits |= partBits
+	}
+	return bits, nil
+}
+
+func parsePart(part string, b bounds) (uint64, error) {
+	var (
+		lo, hi = b.min, b.max
+		step   = 1
+		err    error
+	)
+
+	rangeAndStep := strings.Split(part, stepSeparator)
+	if len(rangeAndStep) > 2 {
+		return 0, fmt.Errorf("invalid step in %q", part)
+	}
+	if len(rangeAndStep) == 2 {
+		step, err = strconv.Atoi(rangeAndStep[1])
+		if err != nil || step <= 0 {
+			return 0, fmt.Errorf("invalid step in %q", part)
+		}
+	}
+
+	switch rangePart := rangeAndStep[0]; {
+	case isWildcard(rangePart):
+	case strings.Contains(rangePart, rangeSeparator):
+		loHi := strings.Split(rangePart, rangeSeparator)
+		if len(loHi) != 2 {
+			return 0, fmt.Errorf("invalid range in %q", part)
+		}
+		if lo, err = parseValue(loHi[0], b); err != nil {
+			return 0, err
+		}
+		if hi, err = parseValue(loHi[1], b); err != nil {
+			return 0, err
+		}
+	default:
+		if lo, err = parseValue(rangePart, b); err != nil {
+			return 0, err
+		}
+		// a single value without a step means exactly that value, with a step it means "from value to max"
+		if len(rangeAndStep) == 1 {
+			hi = lo
+		}
+	}
+
+	if lo > hi {
+		return 0, fmt.Errorf("range start is greater than range end in %q", part)
+	}
+
+	var bits uint64
+	for value := lo; value <= hi; value += step {
+		bits |= 1 << uint(value)
+	}
+	return bits, nil
+}
+
+func parseValue(value string, b bounds) (int, error) {
+	if number, ok := b.names[strings.ToLower(value)]; ok {
+		return number, nil
+	}
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", value)
+	}
+	if number < b.min || number > b.max {
+		return 0, fmt.Errorf("value %d is out of range %d-%d", number, b.min, b.max)
+	}
+	return number, nil
+}
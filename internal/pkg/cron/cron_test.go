@@ -0,0 +1,235 @@
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func bitsOf(values ...int) uint64 {
+	var bits uint64
+	for _, value := range values {
+		bits |= 1 << uint(value)
+	}
+	return bits
+}
+
+func TestParseField(t *testing.T) {
+	tests := []struct {
+		name    string
+		field   string
+		bounds  bounds
+		want    uint64
+		wantErr bool
+	}{
+		{name: "single value", field: "5", bounds: minuteBounds, want: bitsOf(5)},
+		{name: "wildcard", field: "*", bounds: hourBounds, want: bitsOf(0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23)},
+		{name: "alternate wildcard", field: "?", bounds: monthBounds, want: bitsOf(1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12)},
+		{name: "range", field: "8-11", bounds: hourBounds, want: bitsOf(8, 9, 10, 11)},
+		{name: "wildcard with step", field: "*/15", bounds: minuteBounds, want: bitsOf(0, 15, 30, 45)},
+		{name: "range with step", field: "6-10/2", bounds: hourBounds, want: bitsOf(6, 8, 10)},
+		{name: "value with step runs to max", field: "50/5", bounds: minuteBounds, want: bitsOf(50, 55)},
+		{name: "list", field: "1,15,31", bounds: domBounds, want: bitsOf(1, 15, 31)},
+		{name: "list of ranges", field: "1-2,10-11", bounds: domBounds, want: bitsOf(1, 2, 10, 11)},
+		{name: "month names", field: "jan,Mar-may", bounds: monthBounds, want: bitsOf(1, 3, 4, 5)},
+		{name: "weekday names", field: "mon-fri", bounds: dowBounds, want: bitsOf(1, 2, 3, 4, 5)},
+		{name: "value out of range", field: "60", bounds: minuteBounds, wantErr: true},
+		{name: "value below range", field: "0", bounds: domBounds, wantErr: true},
+		{name: "reversed range", field: "10-5", bounds: hourBounds, wantErr: true},
+		{name: "zero step", field: "*/0", bounds: minuteBounds, wantErr: true},
+		{name: "double step", field: "*/2/3", bounds: minuteBounds, wantErr: true},
+		{name: "broken range", field: "1-2-3", bounds: hourBounds, wantErr: true},
+		{name: "unknown name", field: "foo", bounds: monthBounds, wantErr: true},
+		{name: "empty list item", field: "1,", bounds: minuteBounds, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseField(tt.field, tt.bounds)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseField(%q) error = %v, wantErr %v", tt.field, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseField(%q) = %b, want %b", tt.field, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name           string
+		expression     string
+		wantExpression string
+		wantErr        bool
+	}{
+		{name: "expression", expression: "  */30  8-18 * *   1-5 ", wantExpression: "*/30 8-18 * * 1-5"},
+		{name: "preset", expression: "hourly", wantExpression: "0 * * * *"},
+		{name: "preset with separators and prefix", expression: "@Working_Hours", wantExpression: "*/30 8-18 * * 1-5"},
+		{name: "preset with dashes", expression: "aggressive-mornings", wantExpression: "*/15 6-10 * * *"},
+		{name: "too few fields", expression: "* * * *", wantErr: true},
+		{name: "too many fields", expression: "* * * * * *", wantErr: true},
+		{name: "invalid day of week", expression: "0 9 * * 8", wantErr: true},
+		{name: "unknown preset", expression: "sometimes", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := Parse(tt.expression)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse(%q) error = %v, wantErr %v", tt.expression, err, tt.wantErr)
+			}
+			if err == nil && schedule.Expression != tt.wantExpression {
+				t.Errorf("Parse(%q).Expression = %q, want %q", tt.expression, schedule.Expression, tt.wantExpression)
+			}
+		})
+	}
+}
+
+func TestParseSundayAsSeven(t *testing.T) {
+	schedule, err := Parse("0 9 * * 7")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if schedule.dow&1 == 0 {
+		t.Errorf("day of week 7 does not stand for Sunday, bits = %b", schedule.dow)
+	}
+}
+
+func TestPresetNames(t *testing.T) {
+	names := PresetNames()
+	if len(names) != len(presets) {
+		t.Fatalf("PresetNames() returned %d names, want %d", len(names), len(presets))
+	}
+	for idx := range names {
+		if _, err := Parse(names[idx]); err != nil {
+			t.Errorf("preset %q does not parse: %v", names[idx], err)
+		}
+		if idx > 0 && names[idx-1] > names[idx] {
+			t.Errorf("PresetNames() is not sorted: %q before %q", names[idx-1], names[idx])
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	amsterdam, err := time.LoadLocation("Europe/Amsterdam")
+	if err != nil {
+		t.Skipf("timezone database is not available: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		expression string
+		from       time.Time
+		want       time.Time
+	}{
+		{
+			name:       "strictly after the given time",
+			expression: "0 * * * *",
+			from:       time.Date(2024, 5, 10, 9, 0, 0, 0, time.UTC),
+			want:       time.Date(2024, 5, 10, 10, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "seconds are dropped",
+			expression: "* * * * *",
+			from:       time.Date(2024, 5, 10, 9, 0, 59, 999, time.UTC),
+			want:       time.Date(2024, 5, 10, 9, 1, 0, 0, time.UTC),
+		},
+		{
+			name:       "across the end of a 30 day month",
+			expression: "0 9 * * *",
+			from:       time.Date(2024, 4, 30, 10, 0, 0, 0, time.UTC),
+			want:       time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "31st skips months without it",
+			expression: "0 0 31 * *",
+			from:       time.Date(2024, 3, 31, 1, 0, 0, 0, time.UTC),
+			want:       time.Date(2024, 5, 31, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "leap day",
+			expression: "0 12 29 2 *",
+			from:       time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+			want:       time.Date(2028, 2, 29, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "across the end of a year",
+			expression: "30 8 * * *",
+			from:       time.Date(2024, 12, 31, 23, 59, 0, 0, time.UTC),
+			want:       time.Date(2025, 1, 1, 8, 30, 0, 0, time.UTC),
+		},
+		{
+			name:       "day of month or day of week when both are restricted",
+			expression: "0 9 15 * mon",
+			from:       time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC), // Friday
+			want:       time.Date(2024, 5, 13, 9, 0, 0, 0, time.UTC),  // Monday, before the 15th
+		},
+		{
+			name:       "day of month only when day of week is a wildcard",
+			expression: "0 9 15 * *",
+			from:       time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC),
+			want:       time.Date(2024, 5, 15, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "day of week only when day of month is a wildcard",
+			expression: "0 9 * * sat,sun",
+			from:       time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC),
+			want:       time.Date(2024, 5, 11, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "evaluated in the location of the given time",
+			expression: "0 9 * * *",
+			from:       time.Date(2024, 5, 10, 8, 0, 0, 0, amsterdam),
+			want:       time.Date(2024, 5, 10, 9, 0, 0, 0, amsterdam),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := Parse(tt.expression)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.expression, err)
+			}
+			if got := schedule.Next(tt.from); !got.Equal(tt.want) {
+				t.Errorf("Next(%v) = %v, want %v", tt.from, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNextNeverMatching(t *testing.T) {
+	schedule, err := Parse("0 0 30 2 *")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if got := schedule.Next(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)); !got.IsZero() {
+		t.Errorf("Next() = %v, want zero time", got)
+	}
+}
+
+func TestNextNAndMinGap(t *testing.T) {
+	schedule, err := Parse("0,10 9 * * *")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	from := time.Date(2024, 5, 10, 0, 0, 0, 0, time.UTC)
+
+	want := []time.Time{
+		time.Date(2024, 5, 10, 9, 0, 0, 0, time.UTC),
+		time.Date(2024, 5, 10, 9, 10, 0, 0, time.UTC),
+		time.Date(2024, 5, 11, 9, 0, 0, 0, time.UTC),
+	}
+	got := schedule.NextN(from, len(want))
+	if len(got) != len(want) {
+		t.Fatalf("NextN() returned %d times, want %d", len(got), len(want))
+	}
+	for idx := range want {
+		if !got[idx].Equal(want[idx]) {
+			t.Errorf("NextN()[%d] = %v, want %v", idx, got[idx], want[idx])
+		}
+	}
+
+	if gap := schedule.MinGap(from, 3); gap != 10*time.Minute {
+		t.Errorf("MinGap() = %v, want %v", gap, 10*time.Minute)
+	}
+	if gap := schedule.MinGap(from, 1); gap != 0 {
+		t.Errorf("MinGap() of a single run = %v, want 0", gap)
+	}
+}
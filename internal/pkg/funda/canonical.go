@@ -0,0 +1,108 @@
+package funda
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// languagePrefixes are path prefixes Funda uses for localized pages, listings are the same objects regardless
+var languagePrefixes = []string{"en", "nl"}
+
+// objectIDRegex matches a Funda object ID either as a whole path segment (/detail/huur/utrecht/appartement-x/43012345/)
+// or embedded into a slug (/huur/utrecht/appartement-42123456-straat-1/)
+var objectIDRegex = regexp.MustCompile(`(?:^|-)(\d{6,})(?:-|$)`)
+
+// CanonicalSearchURL returns a stable form of a Funda search URL: no language prefix, no pinned page, parameters
+// encoded in a fixed way and sorted. Unlike building the URL from a parsed query it keeps every other parameter and
+// value as is, so nothing the user searched for is lost.
+func CanonicalSearchURL(rawURL string) (string, error) {
+	parsedURL, offering, err := parseSearchURL(rawURL)
+	if err != nil {
+		return "", err
+	}
+
+	params := parsedURL.Query()
+	params.Del(paramSearchResult)
+	for key := range params {
+		for idx := range params[key] {
+			params[key][idx] = canonicalSearchParam(key, params[key][idx])
+		}
+	}
+
+	result := url.URL{
+		Scheme:   searchScheme,
+		Host:     searchHost,
+		Path:     searchPath + offering,
+		RawQuery: params.Encode(),
+	}
+	return result.String(), nil
+}
+
+// canonicalSearchParam encodes values of parameters managed by SearchQuery the way Build does, other values are kept.
+func canonicalSearchParam(key, value string) string {
+	switch key {
+	case paramSelectedArea, paramObjectType:
+		return encodeList(decodeList(value))
+	case paramPrice, paramFloorArea, paramPublicationDate:
+		return encodeString(decodeString(value))
+	}
+	return value
+}
+
+// CanonicalListingURL returns a stable form of a Funda listing URL: https://www.funda.nl, no language prefix,
+// lowercase path with a trailing slash, no query parameters and no fragment. Relative URLs are resolved against Funda.
+func CanonicalListingURL(rawURL string) (string, error) {
+	parsedURL, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse URL: %w", err)
+	}
+	if parsedURL.Host != "" && !isFundaHost(parsedURL.Hostname()) {
+		return "", fmt.Errorf("unsupported host %q", parsedURL.Hostname())
+	}
+
+	segments := make([]string, 0, strings.Count(parsedURL.Path, "/"))
+	for _, segment := range strings.Split(strings.ToLower(parsedURL.Path), "/") {
+		if segment == "" {
+			continue
+		}
+		// language prefixes may be repeated, e.g. /en/en/detail/...
+		if len(segments) == 0 && slices.Contains(languagePrefixes, segment) {
+			continue
+		}
+		segments = append(segments, segment)
+	}
+	if len(segments) == 0 {
+		return "", fmt.Errorf("URL %q has no listing path", rawURL)
+	}
+
+	result := url.URL{
+		Scheme: searchScheme,
+		Host:   searchHost,
+		Path:   "/" + strings.Join(segments, "/") + "/",
+	}
+	return result.String(), nil
+}
+
+// ObjectID extracts the numeric Funda object ID from a listing URL, which survives slug and language changes.
+func ObjectID(rawURL string) (string, error) {
+	canonicalURL, err := CanonicalListingURL(rawURL)
+	if err != nil {
+		return "", err
+	}
+	parsedURL, err := url.Parse(canonicalURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse URL: %w", err)
+	}
+
+	// the ID is the last matching segment, street numbers in slugs are too short to match
+	segments := strings.Split(strings.Trim(parsedURL.Path, "/"), "/")
+	for idx := len(segments) - 1; idx >= 0; idx-- {
+		if match := objectIDRegex.FindStringSubmatch(segments[idx]); match != nil {
+			return match[1], nil
+		}
+	}
+	return "", fmt.Errorf("no object ID found in URL %q", rawURL)
+}
@@ -0,0 +1,122 @@
+package funda
+
+import "testing"
+
+func TestCanonicalSearchURL(t *testing.T) {
+	const canonical = "https://www.funda.nl/zoeken/huur?floor_area=%2250-75%22&object_type=%5B%22apartment%22%5D" +
+		"&price=%221000-2000%22&publication_date=%225%22&selected_area=%5B%22utrecht%22%2C%22amsterdam%22%5D" +
+		"&sort=%22date_down%22"
+
+	tests := []struct {
+		name    string
+		rawURL  string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "quoted values",
+			rawURL: `https://www.funda.nl/zoeken/huur?selected_area=["utrecht","amsterdam"]&price="1000-2000"&object_type=["apartment"]&floor_area="50-75"&publication_date="5"&sort="date_down"`,
+			want:   canonical,
+		},
+		{
+			name:   "plain values, another order, a pinned page and a language prefix",
+			rawURL: `https://funda.nl/en/zoeken/huur/?sort="date_down"&search_result=3&publication_date=5&floor_area=50-75&object_type=apartment&price=1000-2000&selected_area=utrecht,amsterdam`,
+			want:   canonical,
+		},
+		{
+			name:   "open ranges are kept as is",
+			rawURL: "https://funda.nl/en/zoeken/koop/?selected_area=utrecht&price=300000-&floor_area=-100",
+			want:   "https://www.funda.nl/zoeken/koop?floor_area=%22-100%22&price=%22300000-%22&selected_area=%5B%22utrecht%22%5D",
+		},
+		{
+			name:   "unknown and repeated parameters are kept",
+			rawURL: "https://www.funda.nl/zoeken/huur?energy_label=A&energy_label=B",
+			want:   "https://www.funda.nl/zoeken/huur?energy_label=A&energy_label=B",
+		},
+		{name: "unsupported host", rawURL: "https://www.example.com/zoeken/huur", wantErr: true},
+		{name: "not a search page", rawURL: "https://www.funda.nl/detail/huur/utrecht/appartement-straat-1/43012345/", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CanonicalSearchURL(tt.rawURL)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CanonicalSearchURL(%q) error = %v, wantErr %v", tt.rawURL, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("CanonicalSearchURL(%q) = %q, want %q", tt.rawURL, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCanonicalSearchURLIsStable(t *testing.T) {
+	rawURL := `https://funda.nl/nl/zoeken/huur/?selected_area=utrecht&price=-1500&sort="date_down"`
+	once, err := CanonicalSearchURL(rawURL)
+	if err != nil {
+		t.Fatalf("CanonicalSearchURL(%q) error = %v", rawURL, err)
+	}
+	twice, err := CanonicalSearchURL(once)
+	if err != nil {
+		t.Fatalf("CanonicalSearchURL(%q) error = %v", once, err)
+	}
+	if once != twice {
+		t.Errorf("canonical URL changed when canonicalized again: %q became %q", once, twice)
+	}
+}
+
+func TestCanonicalListingURLAndObjectID(t *testing.T) {
+	tests := []struct {
+		name      string
+		rawURL    string
+		canonical string
+		objectID  string
+		wantErr   bool
+	}{
+		{
+			name:      "language prefix, query and fragment",
+			rawURL:    "https://www.funda.nl/en/detail/huur/utrecht/appartement-biltstraat-12/43012345/?utm=x#top",
+			canonical: "https://www.funda.nl/detail/huur/utrecht/appartement-biltstraat-12/43012345/",
+			objectID:  "43012345",
+		},
+		{
+			name:      "relative URL with the ID in the slug",
+			rawURL:    "/huur/utrecht/appartement-42123456-straat-1/",
+			canonical: "https://www.funda.nl/huur/utrecht/appartement-42123456-straat-1/",
+			objectID:  "42123456",
+		},
+		{
+			name:      "repeated language prefix, mixed case and no trailing slash",
+			rawURL:    "https://www.funda.nl/en/en/Detail/Koop/Amsterdam/Huis-Straat-3/88888888",
+			canonical: "https://www.funda.nl/detail/koop/amsterdam/huis-straat-3/88888888/",
+			objectID:  "88888888",
+		},
+		{name: "unsupported host", rawURL: "https://example.com/detail/huur/utrecht/appartement-straat-1/43012345/", wantErr: true},
+		{name: "no listing path", rawURL: "https://www.funda.nl/en/", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			canonical, err := CanonicalListingURL(tt.rawURL)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CanonicalListingURL(%q) error = %v, wantErr %v", tt.rawURL, err, tt.wantErr)
+			}
+			if canonical != tt.canonical {
+				t.Errorf("CanonicalListingURL(%q) = %q, want %q", tt.rawURL, canonical, tt.canonical)
+			}
+
+			objectID, err := ObjectID(tt.rawURL)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ObjectID(%q) error = %v, wantErr %v", tt.rawURL, err, tt.wantErr)
+			}
+			if objectID != tt.objectID {
+				t.Errorf("ObjectID(%q) = %q, want %q", tt.rawURL, objectID, tt.objectID)
+			}
+		})
+	}
+}
+
+func TestObjectIDWithoutID(t *testing.T) {
+	// street numbers are too short to be taken for an object ID
+	if _, err := ObjectID("https://www.funda.nl/detail/huur/utrecht/appartement-biltstraat-12/"); err == nil {
+		t.Error("ObjectID() of a URL without an object ID returned no error")
+	}
+}
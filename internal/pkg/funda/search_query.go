@@ -0,0 +1,336 @@
+package funda
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	OfferingBuy  = "koop"
+	OfferingRent = "huur"
+
+	searchScheme   = "https"
+	searchHost     = "www.funda.nl"
+	searchPath     = "/zoeken/"
+	wholeCountry   = "nl"
+	rangeSeparator = "-"
+
+	paramSelectedArea    = "selected_area"
+	paramPrice           = "price"
+	paramObjectType      = "object_type"
+	paramFloorArea       = "floor_area"
+	paramPublicationDate = "publication_date"
+	paramSearchResult    = "search_result"
+)
+
+var (
+	ObjectTypes      = []string{"house", "apartment", "parking", "land", "storage_space", "berth", "pitch"}
+	PublicationDates = []int{1, 3, 5, 10, 30}
+	// knownParams are recognized Funda parameters which are passed through as is
+	knownParams = []string{"sort", "availability", "construction_period", "construction_type", "energy_label", "rooms", "bedrooms", "exterior_space_type", "exterior_space_garden_size", "plot_area", "zoning", "open_house", "free_text_search", "amenities", "type", "garage_type"}
+)
+
+// SearchQuery is a structured representation of a Funda search URL.
+type SearchQuery struct {
+	Offering        string
+	Areas           []string
+	PriceMin        int
+	PriceMax        int
+	ObjectTypes     []string
+	FloorAreaMin    int
+	FloorAreaMax    int
+	PublicationDate int
+	// Extra keeps parameters the builder does not manage so that parsing and building round-trips
+	Extra url.Values
+}
+
+func NewSearchQuery() *SearchQuery {
+	return &SearchQuery{
+		Offering: OfferingRent,
+		Areas:    []string{wholeCountry},
+	}
+}
+
+func (q *SearchQuery) Validate() error {
+	if q.Offering != OfferingBuy && q.Offering != OfferingRent {
+		return fmt.Errorf("offering must be either %q or %q, got %q", OfferingBuy, OfferingRent, q.Offering)
+	}
+	if len(q.Areas) == 0 {
+		return fmt.Errorf("at least one area is required")
+	}
+	if q.PriceMin < 0 || q.PriceMax < 0 {
+		return fmt.Errorf("price cannot be negative")
+	}
+	if q.PriceMax != 0 && q.PriceMin > q.PriceMax {
+		return fmt.Errorf("minimal price %d is greater than maximal price %d", q.PriceMin, q.PriceMax)
+	}
+	for idx := range q.ObjectTypes {
+		if !slices.Contains(ObjectTypes, q.ObjectTypes[idx]) {
+			return fmt.Errorf("unknown object type %q, expected one of %s", q.ObjectTypes[idx], strings.Join(ObjectTypes, ", "))
+		}
+	}
+	if q.FloorAreaMin < 0 || q.FloorAreaMax < 0 {
+		return fmt.Errorf("floor area cannot be negative")
+	}
+	if q.FloorAreaMax != 0 && q.FloorAreaMin > q.FloorAreaMax {
+		return fmt.Errorf("minimal floor area %d is greater than maximal floor area %d", q.FloorAreaMin, q.FloorAreaMax)
+	}
+	if q.PublicationDate != 0 && !slices.Contains(PublicationDates, q.PublicationDate) {
+		return fmt.Errorf("publication date must be one of %v days, got %d", PublicationDates, q.PublicationDate)
+	}
+	return nil
+}
+
+// Build produces a Funda search URL, it fails on invalid queries.
+func (q *SearchQuery) Build() (string, error) {
+	if err := q.Validate(); err != nil {
+		return "", err
+	}
+
+	params := url.Values{}
+	for key := range q.Extra {
+		params[key] = slices.Clone(q.Extra[key])
+	}
+	params.Set(paramSelectedArea, encodeList(q.Areas))
+	if len(q.ObjectTypes) > 0 {
+		params.Set(paramObjectType, encodeList(q.ObjectTypes))
+	}
+	if q.PriceMin > 0 || q.PriceMax > 0 {
+		params.Set(paramPrice, encodeString(encodeRange(q.PriceMin, q.PriceMax)))
+	}
+	if q.FloorAreaMin > 0 || q.FloorAreaMax > 0 {
+		params.Set(paramFloorArea, encodeString(encodeRange(q.FloorAreaMin, q.FloorAreaMax)))
+	}
+	if q.PublicationDate > 0 {
+		params.Set(paramPublicationDate, encodeString(strconv.Itoa(q.PublicationDate)))
+	}
+
+	result := url.URL{
+		Scheme:   searchScheme,
+		Host:     searchHost,
+		Path:     searchPath + q.Offering,
+		RawQuery: params.Encode(),
+	}
+	return result.String(), nil
+}
+
+// Parse turns a Funda search URL, as copied from a browser, back into its structured parameters.
+func Parse(rawURL string) (*SearchQuery, error) {
+	parsedURL, offering, err := parseSearchURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	query := SearchQuery{Offering: offering, Extra: url.Values{}}
+
+	params := parsedURL.Query()
+	for key := range params {
+		value := params.Get(key)
+		switch key {
+		case paramSelectedArea:
+			query.Areas = decodeList(value)
+		case paramObjectType:
+			query.ObjectTypes = decodeList(value)
+		case paramPrice:
+			if query.PriceMin, query.PriceMax, err = decodeRange(decodeString(value)); err != nil {
+				return nil, fmt.Errorf("invalid price: %w", err)
+			}
+		case paramFloorArea:
+			if query.FloorAreaMin, query.FloorAreaMax, err = decodeRange(decodeString(value)); err != nil {
+				return nil, fmt.Errorf("invalid floor area: %w", err)
+			}
+		case paramPublicationDate:
+			if query.PublicationDate, err = strconv.Atoi(decodeString(value)); err != nil {
+				return nil, fmt.Errorf("invalid publication date %q", value)
+			}
+		case paramSearchResult:
+			// pagination is managed by the crawler
+		default:
+			query.Extra[key] = params[key]
+		}
+	}
+	if len(query.Areas) == 0 {
+		query.Areas = []string{wholeCountry}
+	}
+
+	return &query, nil
+}
+
+// parseSearchURL parses a Funda search URL and tells its offering, it fails for anything but a Funda search page.
+func parseSearchURL(rawURL string) (*url.URL, string, error) {
+	parsedURL, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to parse URL: %w", err)
+	}
+	if parsedURL.Scheme != "http" && parsedURL.Scheme != "https" {
+		return nil, "", fmt.Errorf("unsupported scheme %q", parsedURL.Scheme)
+	}
+	if !isFundaHost(parsedURL.Hostname()) {
+		return nil, "", fmt.Errorf("unsupported host %q", parsedURL.Hostname())
+	}
+	offering, err := parseOffering(parsedURL.Path)
+	if err != nil {
+		return nil, "", err
+	}
+	return parsedURL, offering, nil
+}
+
+func isFundaHost(host string) bool {
+	return host == "funda.nl" || strings.HasSuffix(host, ".funda.nl")
+}
+
+func parseOffering(path string) (string, error) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for idx := range segments {
+		if segments[idx] != "zoeken" || idx+1 >= len(segments) {
+			continue
+		}
+		switch segments[idx+1] {
+		case OfferingBuy, "buy":
+			return OfferingBuy, nil
+		case OfferingRent, "rent":
+			return OfferingRent, nil
+		}
+	}
+	return "", fmt.Errorf("path %q is not a Funda search page", path)
+}
+
+func encodeList(values []string) string {
+	encoded, _ := json.Marshal(values)
+	return string(encoded)
+}
+
+func decodeList(value string) []string {
+	var values []string
+	if err := json.Unmarshal([]byte(value), &values); err == nil {
+		return values
+	}
+	// fall back to a plain comma-separated list
+	for _, item := range strings.Split(decodeString(value), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			values = append(values, item)
+		}
+	}
+	return values
+}
+
+func encodeString(value string) string {
+	return strconv.Quote(value)
+}
+
+func decodeString(value string) string {
+	if unquoted, err := strconv.Unquote(value); err == nil {
+		return unquoted
+	}
+	return value
+}
+
+func encodeRange(lo, hi int) string {
+	result := strconv.Itoa(lo) + rangeSeparator
+	if hi > 0 {
+		result += strconv.Itoa(hi)
+	}
+	return result
+}
+
+func decodeRange(value string) (lo, hi int, err error) {
+	loHi := strings.SplitN(value, rangeSeparator, 2)
+	if loHi[0] != "" {
+		if lo, err = strconv.Atoi(loHi[0]); err != nil {
+			return 0, 0, fmt.Errorf("invalid range %q", value)
+		}
+	}
+	if len(loHi) == 2 && loHi[1] != "" {
+		if hi, err = strconv.Atoi(loHi[1]); err != nil {
+			return 0, 0, fmt.Errorf("invalid range %q", value)
+		}
+	}
+	return lo, hi, nil
+}
+
+// Inspect parses a Funda search URL and reports parameters which are unknown, conflicting or ignored by the bot.
+func Inspect(rawURL string) (*SearchQuery, []string, error) {
+	query, err := Parse(rawURL)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var warnings []string
+	parsedURL, _ := url.Parse(strings.TrimSpace(rawURL))
+	if page := parsedURL.Query().Get(paramSearchResult); page != "" {
+		warnings = append(warnings, fmt.Sprintf("page %s is pinned via %s, it is ignored since the bot walks through all pages", decodeString(page), paramSearchResult))
+	}
+	if query.PriceMax != 0 && query.PriceMin > query.PriceMax {
+		warnings = append(warnings, fmt.Sprintf("minimal price %d is greater than maximal price %d, nothing will be found", query.PriceMin, query.PriceMax))
+	}
+	if query.FloorAreaMax != 0 && query.FloorAreaMin > query.FloorAreaMax {
+		warnings = append(warnings, fmt.Sprintf("minimal floor area %d is greater than maximal floor area %d, nothing will be found", query.FloorAreaMin, query.FloorAreaMax))
+	}
+	if slices.Contains(query.Areas, wholeCountry) && len(query.Areas) > 1 {
+		warnings = append(warnings, "the whole country is selected along with other areas, the other areas have no effect")
+	}
+	for idx := range query.ObjectTypes {
+		if !slices.Contains(ObjectTypes, query.ObjectTypes[idx]) {
+			warnings = append(warnings, fmt.Sprintf("object type %q is not recognized", query.ObjectTypes[idx]))
+		}
+	}
+	if query.PublicationDate != 0 && !slices.Contains(PublicationDates, query.PublicationDate) {
+		warnings = append(warnings, fmt.Sprintf("publication date %d is not one of %v days", query.PublicationDate, PublicationDates))
+	}
+
+	extraKeys := make([]string, 0, len(query.Extra))
+	for key := range query.Extra {
+		extraKeys = append(extraKeys, key)
+	}
+	slices.Sort(extraKeys)
+	for _, key := range extraKeys {
+		if !slices.Contains(knownParams, key) {
+			warnings = append(warnings, fmt.Sprintf("parameter %s=%s is not recognized", key, strings.Join(query.Extra[key], ",")))
+		}
+	}
+
+	return query, warnings, nil
+}
+
+// NarrowingSuggestions lists ways to reduce the number of matches of a search URL, based on what it does not restrict yet.
+func NarrowingSuggestions(rawURL string) []string {
+	query, err := Parse(rawURL)
+	if err != nil {
+		query = NewSearchQuery()
+	}
+
+	var suggestions []string
+	if len(query.Areas) == 0 || slices.Contains(query.Areas, wholeCountry) {
+		suggestions = append(suggestions, "select specific cities or areas instead of the whole country")
+	}
+	if query.PriceMax == 0 {
+		suggestions = append(suggestions, "set a maximal price")
+	}
+	if len(query.ObjectTypes) == 0 {
+		suggestions = append(suggestions, "restrict object types, e.g. apartment or house")
+	}
+	if query.FloorAreaMin == 0 {
+		suggestions = append(suggestions, "set a minimal floor area")
+	}
+	if query.PublicationDate == 0 || query.PublicationDate > PublicationDates[1] {
+		suggestions = append(suggestions, fmt.Sprintf("only include listings published within the last %d days", PublicationDates[1]))
+	}
+	if len(suggestions) == 0 {
+		suggestions = append(suggestions, "split the query into several narrower ones")
+	}
+	return suggestions
+}
+
+// ExtraParams lists parameters kept as is, formatted as key=value and sorted by key.
+func (q *SearchQuery) ExtraParams() []string {
+	result := make([]string, 0, len(q.Extra))
+	for key := range q.Extra {
+		result = append(result, key+"="+decodeString(strings.Join(q.Extra[key], ",")))
+	}
+	slices.Sort(result)
+	return result
+}
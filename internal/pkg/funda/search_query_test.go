@@ -0,0 +1,166 @@
+package funda
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		rawURL  string
+		want    *SearchQuery
+		wantErr bool
+	}{
+		{
+			name:   "all managed parameters",
+			rawURL: `https://www.funda.nl/zoeken/huur?selected_area=["utrecht","amsterdam"]&price="1000-2000"&object_type=["apartment"]&floor_area="50-75"&publication_date="5"&sort="date_down"&search_result=3`,
+			want: &SearchQuery{
+				Offering:        OfferingRent,
+				Areas:           []string{"utrecht", "amsterdam"},
+				PriceMin:        1000,
+				PriceMax:        2000,
+				ObjectTypes:     []string{"apartment"},
+				FloorAreaMin:    50,
+				FloorAreaMax:    75,
+				PublicationDate: 5,
+				Extra:           url.Values{"sort": {`"date_down"`}},
+			},
+		},
+		{
+			name:   "open ranges, plain values and a language prefix",
+			rawURL: "https://funda.nl/en/zoeken/buy/?selected_area=utrecht,leiden&price=300000-&floor_area=-100",
+			want: &SearchQuery{
+				Offering:     OfferingBuy,
+				Areas:        []string{"utrecht", "leiden"},
+				PriceMin:     300000,
+				FloorAreaMax: 100,
+				Extra:        url.Values{},
+			},
+		},
+		{
+			name:   "whole country by default",
+			rawURL: "https://www.funda.nl/zoeken/huur",
+			want:   &SearchQuery{Offering: OfferingRent, Areas: []string{wholeCountry}, Extra: url.Values{}},
+		},
+		{name: "unsupported scheme", rawURL: "ftp://www.funda.nl/zoeken/huur", wantErr: true},
+		{name: "unsupported host", rawURL: "https://www.example.com/zoeken/huur", wantErr: true},
+		{name: "look-alike host", rawURL: "https://notfunda.nl/zoeken/huur", wantErr: true},
+		{name: "not a search page", rawURL: "https://www.funda.nl/detail/huur/utrecht/appartement-straat-1/43012345/", wantErr: true},
+		{name: "invalid price", rawURL: `https://www.funda.nl/zoeken/huur?price="cheap"`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.rawURL)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse(%q) error = %v, wantErr %v", tt.rawURL, err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse(%q) = %+v, want %+v", tt.rawURL, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildParseRoundTrip(t *testing.T) {
+	queries := []*SearchQuery{
+		NewSearchQuery(),
+		{
+			Offering:        OfferingBuy,
+			Areas:           []string{"utrecht", "amsterdam"},
+			PriceMin:        250000,
+			PriceMax:        400000,
+			ObjectTypes:     []string{"house", "apartment"},
+			FloorAreaMin:    75,
+			FloorAreaMax:    150,
+			PublicationDate: 3,
+			Extra:           url.Values{"sort": {`"date_down"`}, "energy_label": {`["A","B"]`}},
+		},
+		{Offering: OfferingRent, Areas: []string{"leiden"}, PriceMax: 1500, FloorAreaMin: 50},
+		{Offering: OfferingRent, Areas: []string{"leiden"}, FloorAreaMax: 60},
+	}
+	for _, query := range queries {
+		built, err := query.Build()
+		if err != nil {
+			t.Fatalf("Build() of %+v error = %v", query, err)
+		}
+		parsed, err := Parse(built)
+		if err != nil {
+			t.Fatalf("Parse(%q) error = %v", built, err)
+		}
+		rebuilt, err := parsed.Build()
+		if err != nil {
+			t.Fatalf("Build() of %+v error = %v", parsed, err)
+		}
+		if rebuilt != built {
+			t.Errorf("round trip changed the URL: %q became %q", built, rebuilt)
+		}
+
+		if parsed.Offering != query.Offering || !reflect.DeepEqual(parsed.Areas, query.Areas) ||
+			parsed.PriceMin != query.PriceMin || parsed.PriceMax != query.PriceMax ||
+			parsed.FloorAreaMin != query.FloorAreaMin || parsed.FloorAreaMax != query.FloorAreaMax ||
+			parsed.PublicationDate != query.PublicationDate || len(parsed.ObjectTypes) != len(query.ObjectTypes) {
+			t.Errorf("Parse(Build()) = %+v, want %+v", parsed, query)
+		}
+		for key := range query.Extra {
+			if !reflect.DeepEqual(parsed.Extra[key], query.Extra[key]) {
+				t.Errorf("extra parameter %s = %v, want %v", key, parsed.Extra[key], query.Extra[key])
+			}
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   SearchQuery
+		wantErr bool
+	}{
+		{name: "default", query: *NewSearchQuery()},
+		{name: "unknown offering", query: SearchQuery{Offering: "lease", Areas: []string{wholeCountry}}, wantErr: true},
+		{name: "no areas", query: SearchQuery{Offering: OfferingRent}, wantErr: true},
+		{name: "negative price", query: SearchQuery{Offering: OfferingRent, Areas: []string{wholeCountry}, PriceMin: -1}, wantErr: true},
+		{name: "reversed price", query: SearchQuery{Offering: OfferingRent, Areas: []string{wholeCountry}, PriceMin: 2000, PriceMax: 1000}, wantErr: true},
+		{name: "open price range", query: SearchQuery{Offering: OfferingRent, Areas: []string{wholeCountry}, PriceMin: 2000}},
+		{name: "unknown object type", query: SearchQuery{Offering: OfferingRent, Areas: []string{wholeCountry}, ObjectTypes: []string{"castle"}}, wantErr: true},
+		{name: "negative floor area", query: SearchQuery{Offering: OfferingRent, Areas: []string{wholeCountry}, FloorAreaMax: -1}, wantErr: true},
+		{name: "reversed floor area", query: SearchQuery{Offering: OfferingRent, Areas: []string{wholeCountry}, FloorAreaMin: 100, FloorAreaMax: 50}, wantErr: true},
+		{name: "unsupported publication date", query: SearchQuery{Offering: OfferingRent, Areas: []string{wholeCountry}, PublicationDate: 2}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.query.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if _, err := tt.query.Build(); (err != nil) != tt.wantErr {
+				t.Errorf("Build() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDecodeRange(t *testing.T) {
+	tests := []struct {
+		value   string
+		lo, hi  int
+		wantErr bool
+	}{
+		{value: "100-200", lo: 100, hi: 200},
+		{value: "100-", lo: 100},
+		{value: "-200", hi: 200},
+		{value: "100", lo: 100},
+		{value: "", lo: 0},
+		{value: "a-200", wantErr: true},
+		{value: "100-b", wantErr: true},
+	}
+	for _, tt := range tests {
+		lo, hi, err := decodeRange(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("decodeRange(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+		}
+		if lo != tt.lo || hi != tt.hi {
+			t.Errorf("decodeRange(%q) = %d, %d, want %d, %d", tt.value, lo, hi, tt.lo, tt.hi)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+// Package ical renders events as an iCalendar (RFC 5545) document importable into calendar apps.
+package ical
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	lineBreak     = "\r\n"
+	maxLineOctets = 75
+	utcLayout     = "20060102T150405Z"
+	productID     = "-//fundaNotifier//viewings//EN"
+)
+
+type Event struct {
+	UID         string
+	Start       time.Time
+	End         time.Time
+	Summary     string
+	Location    string
+	Description string
+	URL         string
+}
+
+// Encode renders events into a VCALENDAR document, stamp is the time the document is generated at.
+func Encode(events []Event, stamp time.Time) []byte {
+	var b strings.Builder
+	writeLine(&b, "BEGIN:VCALENDAR")
+	writeLine(&b, "VERSION:2.0")
+	writeLine(&b, "PRODID:"+productID)
+	writeLine(&b, "CALSCALE:GREGORIAN")
+	writeLine(&b, "METHOD:PUBLISH")
+	for idx := range events {
+		writeLine(&b, "BEGIN:VEVENT")
+		writeLine(&b, "UID:"+escapeText(events[idx].UID))
+		writeLine(&b, "DTSTAMP:"+stamp.UTC().Format(utcLayout))
+		writeLine(&b, "DTSTART:"+events[idx].Start.UTC().Format(utcLayout))
+		writeLine(&b, "DTEND:"+events[idx].End.UTC().Format(utcLayout))
+		writeLine(&b, "SUMMARY:"+escapeText(events[idx].Summary))
+		if events[idx].Location != "" {
+			writeLine(&b, "LOCATION:"+escapeText(events[idx].Location))
+		}
+		if events[idx].Description != "" {
+			writeLine(&b, "DESCRIPTION:"+escapeText(events[idx].Description))
+		}
+		if events[idx].URL != "" {
+			writeLine(&b, "URL:"+events[idx].URL)
+		}
+		writeLine(&b, "END:VEVENT")
+	}
+	writeLine(&b, "END:VCALENDAR")
+	return []byte(b.String())
+}
+
+func escapeText(text string) string {
+	replacer := strings.NewReplacer(
+		"\\", "\\\\",
+		";", "\\;",
+		",", "\\,",
+		"\r\n", "\\n",
+		"\n", "\\n",
+	)
+	return replacer.Replace(text)
+}
+
+// writeLine folds content lines longer than 75 octets without splitting multibyte characters.
+func writeLine(b *strings.Builder, line string) {
+	limit := maxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString(lineBreak + " ")
+		line = line[cut:]
+		// continuation lines start with a space which counts towards the limit
+		limit = maxLineOctets - 1
+	}
+	b.WriteString(line)
+	b.WriteString(lineBreak)
+}
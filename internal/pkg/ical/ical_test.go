@@ -0,0 +1,135 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestEscapeText(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{text: "Biltstraat 12", want: "Biltstraat 12"},
+		{text: "Biltstraat 12, Utrecht", want: `Biltstraat 12\, Utrecht`},
+		{text: "viewing; bring ID", want: `viewing\; bring ID`},
+		{text: `C:\path`, want: `C:\\path`},
+		{text: "line one\nline two", want: `line one\nline two`},
+		{text: "line one\r\nline two", want: `line one\nline two`},
+		{text: `\,;`, want: `\\\,\;`},
+	}
+	for _, tt := range tests {
+		if got := escapeText(tt.text); got != tt.want {
+			t.Errorf("escapeText(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestWriteLine(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+	}{
+		{name: "short", line: "SUMMARY:viewing"},
+		{name: "exactly at the limit", line: strings.Repeat("x", maxLineOctets)},
+		{name: "one over the limit", line: strings.Repeat("x", maxLineOctets+1)},
+		{name: "several continuation lines", line: "DESCRIPTION:" + strings.Repeat("abcdefghij", 30)},
+		{name: "multibyte characters", line: "LOCATION:" + strings.Repeat("é€", 40)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b strings.Builder
+			writeLine(&b, tt.line)
+			folded := b.String()
+
+			if !strings.HasSuffix(folded, lineBreak) {
+				t.Fatalf("writeLine() = %q, want a trailing CRLF", folded)
+			}
+			lines := strings.Split(strings.TrimSuffix(folded, lineBreak), lineBreak)
+			for idx, line := range lines {
+				if len(line) > maxLineOctets {
+					t.Errorf("line %d is %d octets, want at most %d", idx, len(line), maxLineOctets)
+				}
+				if !utf8.ValidString(line) {
+					t.Errorf("line %d splits a multibyte character: %q", idx, line)
+				}
+				if idx > 0 && !strings.HasPrefix(line, " ") {
+					t.Errorf("continuation line %d does not start with a space: %q", idx, line)
+				}
+			}
+			if len(tt.line) <= maxLineOctets && len(lines) != 1 {
+				t.Errorf("writeLine() folded a line of %d octets into %d lines", len(tt.line), len(lines))
+			}
+
+			// unfolding as described in RFC 5545 restores the original line
+			if unfolded := strings.ReplaceAll(strings.TrimSuffix(folded, lineBreak), lineBreak+" ", ""); unfolded != tt.line {
+				t.Errorf("unfolded line = %q, want %q", unfolded, tt.line)
+			}
+		})
+	}
+}
+
+func TestEncode(t *testing.T) {
+	stamp := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	amsterdam := time.FixedZone("CEST", 2*60*60)
+	events := []Event{
+		{
+			UID:         "viewing-1@fundaNotifier",
+			Start:       time.Date(2024, 5, 14, 18, 30, 0, 0, amsterdam),
+			End:         time.Date(2024, 5, 14, 19, 0, 0, 0, amsterdam),
+			Summary:     "Viewing: Biltstraat 12, Utrecht",
+			Location:    "Biltstraat 12, Utrecht",
+			Description: "Bring ID; ask about the deposit\nCall on arrival",
+			URL:         "https://www.funda.nl/detail/huur/utrecht/appartement-biltstraat-12/43012345/",
+		},
+		{
+			UID:     "viewing-2@fundaNotifier",
+			Start:   time.Date(2024, 5, 15, 9, 0, 0, 0, time.UTC),
+			End:     time.Date(2024, 5, 15, 9, 30, 0, 0, time.UTC),
+			Summary: "Viewing",
+		},
+	}
+
+	want := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:" + productID,
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+		"BEGIN:VEVENT",
+		"UID:viewing-1@fundaNotifier",
+		"DTSTAMP:20240510T120000Z",
+		"DTSTART:20240514T163000Z",
+		"DTEND:20240514T170000Z",
+		`SUMMARY:Viewing: Biltstraat 12\, Utrecht`,
+		`LOCATION:Biltstraat 12\, Utrecht`,
+		`DESCRIPTION:Bring ID\; ask about the deposit\nCall on arrival`,
+		"URL:https://www.funda.nl/detail/huur/utrecht/appartement-biltstraat-12/4301",
+		" 2345/",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:viewing-2@fundaNotifier",
+		"DTSTAMP:20240510T120000Z",
+		"DTSTART:20240515T090000Z",
+		"DTEND:20240515T093000Z",
+		"SUMMARY:Viewing",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, lineBreak) + lineBreak
+
+	if got := string(Encode(events, stamp)); got != want {
+		t.Errorf("Encode() = %q, want %q", got, want)
+	}
+}
+
+func TestEncodeWithoutEvents(t *testing.T) {
+	got := string(Encode(nil, time.Now()))
+	if strings.Contains(got, "BEGIN:VEVENT") {
+		t.Errorf("Encode() of no events = %q, want no VEVENT", got)
+	}
+	if !strings.HasPrefix(got, "BEGIN:VCALENDAR"+lineBreak) || !strings.HasSuffix(got, "END:VCALENDAR"+lineBreak) {
+		t.Errorf("Encode() of no events = %q, want an empty VCALENDAR", got)
+	}
+}
@@ -0,0 +1,115 @@
+// Package callback implements the versioned encoding of inline button callback data, `v1|action|id|arg1|arg2...`.
+package callback
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	version   = "v1"
+	separator = "|"
+	// MaxLen is the limit Telegram puts on callback data, in bytes
+	MaxLen = 64
+)
+
+const (
+	// ActionNoop is used by buttons which only show a state, such as an already saved favorite
+	ActionNoop = "noop"
+	// ActionFavorite adds a listing given by its UUID to favorites, card actions inside the listing browser carry the
+	// browser state as args
+	ActionFavorite = "fav"
+	// ActionNote adds a listing given by its UUID to favorites and asks for a note
+	ActionNote = "note"
+	// ActionPriceHistory shows price changes of a listing given by its UUID
+	ActionPriceHistory = "prices"
+	// ActionDetails shows everything stored about a listing given by its UUID
+	ActionDetails = "details"
+	// ActionHide hides a listing given by its UUID from counts and listings
+	ActionHide = "hide"
+	// ActionUnhide restores a hidden listing given by its object ID, the optional arg is the UUID of the card to restore
+	ActionUnhide = "unhide"
+	// ActionFavorites manages the favorites list, the ID is an object ID and args are the operation and the page
+	ActionFavorites = "favs"
+	// ActionStage moves a favorite given by its object ID to the pipeline stage given as the arg
+	ActionStage = "stage"
+	// ActionSeen marks a listing given by its UUID as seen and shows the next unseen one
+	ActionSeen = "seen"
+	// ActionThumbsUp and ActionThumbsDown give feedback on a listing given by its UUID, args are the same as with
+	// ActionFavorite
+	ActionThumbsUp   = "up"
+	ActionThumbsDown = "down"
+	// ActionBrowse renders the listing browser, the ID is the page and args are the sort order and filter toggles
+	ActionBrowse = "browse"
+	// ActionQueryBuilder drives the guided query builder, args are the step action and its value
+	ActionQueryBuilder = "qb"
+)
+
+var (
+	ErrMalformed      = errors.New("malformed callback data")
+	ErrUnknownVersion = errors.New("unknown callback data version")
+	ErrTooLong        = errors.New("callback data exceeds the limit")
+)
+
+type Data struct {
+	Action string
+	ID     string
+	Args   []string
+}
+
+func New(action, id string, args ...string) Data {
+	return Data{
+		Action: action,
+		ID:     id,
+		Args:   args,
+	}
+}
+
+// Arg returns an argument by its index or an empty string when it is absent.
+func (d *Data) Arg(idx int) string {
+	if idx < 0 || idx >= len(d.Args) {
+		return ""
+	}
+	return d.Args[idx]
+}
+
+func (d *Data) Encode() (string, error) {
+	fields := append([]string{version, d.Action, d.ID}, d.Args...)
+	for idx := 1; idx < len(fields); idx++ {
+		if strings.Contains(fields[idx], separator) {
+			return "", fmt.Errorf("field %q contains a separator: %w", fields[idx], ErrMalformed)
+		}
+	}
+	if d.Action == "" {
+		return "", fmt.Errorf("action is empty: %w", ErrMalformed)
+	}
+
+	encoded := strings.Join(fields, separator)
+	if len(encoded) > MaxLen {
+		return "", fmt.Errorf("%d bytes of %q: %w", len(encoded), encoded, ErrTooLong)
+	}
+	return encoded, nil
+}
+
+func Decode(raw string) (Data, error) {
+	fields := strings.Split(raw, separator)
+	if len(fields) < 3 {
+		return Data{}, ErrMalformed
+	}
+	if fields[0] != version {
+		return Data{}, fmt.Errorf("%q: %w", fields[0], ErrUnknownVersion)
+	}
+	if fields[1] == "" {
+		return Data{}, fmt.Errorf("action is empty: %w", ErrMalformed)
+	}
+
+	data := Data{
+		Action: fields[1],
+		ID:     fields[2],
+	}
+	if len(fields) > 3 {
+		data.Args = fields[3:]
+	}
+	return data, nil
+}
@@ -0,0 +1,117 @@
+package callback
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    Data
+		encoded string
+	}{
+		{name: "without args", data: New(ActionDetails, "7b0c5a4e-9d1f-4f6e-8a34-2f0b1c9d8e7a"), encoded: "v1|details|7b0c5a4e-9d1f-4f6e-8a34-2f0b1c9d8e7a"},
+		{name: "with args", data: New(ActionBrowse, "3", "price", "new"), encoded: "v1|browse|3|price|new"},
+		{name: "empty id", data: New(ActionNoop, ""), encoded: "v1|noop|"},
+		{name: "empty arg", data: New(ActionQueryBuilder, "", "set", ""), encoded: "v1|qb||set|"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoded, err := tt.data.Encode()
+			if err != nil {
+				t.Fatalf("Encode() error = %v", err)
+			}
+			if encoded != tt.encoded {
+				t.Errorf("Encode() = %q, want %q", encoded, tt.encoded)
+			}
+
+			decoded, err := Decode(encoded)
+			if err != nil {
+				t.Fatalf("Decode(%q) error = %v", encoded, err)
+			}
+			if !reflect.DeepEqual(decoded, tt.data) {
+				t.Errorf("Decode(%q) = %+v, want %+v", encoded, decoded, tt.data)
+			}
+		})
+	}
+}
+
+func TestEncodeErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    Data
+		wantErr error
+	}{
+		{name: "empty action", data: New("", "id"), wantErr: ErrMalformed},
+		{name: "separator in id", data: New(ActionDetails, "a|b"), wantErr: ErrMalformed},
+		{name: "separator in arg", data: New(ActionBrowse, "1", "a|b"), wantErr: ErrMalformed},
+		{name: "over the limit", data: New(ActionBrowse, "1", strings.Repeat("x", MaxLen)), wantErr: ErrTooLong},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.data.Encode(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Encode() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEncodeLimit(t *testing.T) {
+	prefix := "v1|" + ActionDetails + "|"
+	atLimit := New(ActionDetails, strings.Repeat("x", MaxLen-len(prefix)))
+	encoded, err := atLimit.Encode()
+	if err != nil {
+		t.Fatalf("Encode() of %d bytes error = %v", MaxLen, err)
+	}
+	if len(encoded) != MaxLen {
+		t.Fatalf("Encode() = %d bytes, want %d", len(encoded), MaxLen)
+	}
+
+	overLimit := New(ActionDetails, atLimit.ID+"x")
+	if _, err = overLimit.Encode(); !errors.Is(err, ErrTooLong) {
+		t.Errorf("Encode() of %d bytes error = %v, want %v", MaxLen+1, err, ErrTooLong)
+	}
+
+	// the limit is in bytes, not in characters
+	multiByte := New(ActionDetails, strings.Repeat("é", (MaxLen-len(prefix))/2+1))
+	if _, err = multiByte.Encode(); !errors.Is(err, ErrTooLong) {
+		t.Errorf("Encode() of multi-byte data error = %v, want %v", err, ErrTooLong)
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		wantErr error
+	}{
+		{name: "empty", raw: "", wantErr: ErrMalformed},
+		{name: "too few fields", raw: "v1|details", wantErr: ErrMalformed},
+		{name: "legacy format", raw: "details:7b0c5a4e", wantErr: ErrMalformed},
+		{name: "unknown version", raw: "v2|details|id", wantErr: ErrUnknownVersion},
+		{name: "empty action", raw: "v1||id", wantErr: ErrMalformed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Decode(tt.raw); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Decode(%q) error = %v, want %v", tt.raw, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestArg(t *testing.T) {
+	data := New(ActionBrowse, "1", "price")
+	if got := data.Arg(0); got != "price" {
+		t.Errorf("Arg(0) = %q, want %q", got, "price")
+	}
+	if got := data.Arg(1); got != "" {
+		t.Errorf("Arg(1) = %q, want empty", got)
+	}
+	if got := data.Arg(-1); got != "" {
+		t.Errorf("Arg(-1) = %q, want empty", got)
+	}
+}
@@ -0,0 +1,237 @@
+package tgbot
+
+import (
+	"context"
+	"fundaNotifier/internal/pkg/tgbot/callback"
+	"fundaNotifier/internal/pkg/tgbot/commands"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/google/uuid"
+)
+
+// callbackHandler processes a single callback action, it is responsible for answering the callback query.
+type callbackHandler func(ctx context.Context, update tgbotapi.Update, data *callback.Data)
+
+func (b *TelegramBot) newCallbackHandlers() map[string]callbackHandler {
+	return map[string]callbackHandler{
+		callback.ActionNoop:         b.handleNoopCallback,
+		callback.ActionFavorite:     b.handleFavoriteCallback,
+		callback.ActionNote:         b.handleNoteCallback,
+		callback.ActionPriceHistory: b.handlePriceHistoryCallback,
+		callback.ActionDetails:      b.handleDetailsCallback,
+		callback.ActionHide:         b.handleHideCallback,
+		callback.ActionUnhide:       b.handleUnhideCallback,
+		callback.ActionFavorites:    b.handleFavoritesCallback,
+		callback.ActionStage:        b.handleStageCallback,
+		callback.ActionSeen:         b.handleSeenCallback,
+		callback.ActionThumbsUp:     b.handleThumbsUpCallback,
+		callback.ActionThumbsDown:   b.handleThumbsDownCallback,
+		callback.ActionBrowse:       b.handleBrowseCallback,
+		callback.ActionQueryBuilder: b.handleQueryBuilderCallback,
+	}
+}
+
+func (b *TelegramBot) updateCallbackHandler(ctx context.Context, update tgbotapi.Update) {
+	user := update.CallbackQuery.From
+	chatID := update.CallbackQuery.Message.Chat.ID
+	b.log.Debug().Str("userID", user.UserName).Int64("chatID", chatID).Msg("received callback from")
+
+	// check that user is in whitelist
+	if !b.isAuthorizedUser(user.UserName, chatID) {
+		b.answerCallback(user.UserName, chatID, update, "💥You are not authorized")
+		return
+	}
+
+	data, err := callback.Decode(update.CallbackQuery.Data)
+	if err != nil {
+		// buttons of messages sent before callback data was versioned carry a bare listing UUID or a disabled marker
+		switch _, errParse := uuid.Parse(update.CallbackQuery.Data); {
+		case update.CallbackQuery.Data == disabledButtonCallbackData:
+			data = callback.New(callback.ActionNoop, "")
+		case errParse == nil:
+			data = callback.New(callback.ActionFavorite, update.CallbackQuery.Data)
+		}
+	}
+
+	handler, ok := b.callbackHandlers[data.Action]
+	if !ok {
+		b.log.Warn().Str("userID", user.UserName).Str("data", update.CallbackQuery.Data).Msg("received callback with unknown action")
+		b.answerCallback(user.UserName, chatID, update, "🤷This button is not supported anymore")
+		return
+	}
+	handler(ctx, update, &data)
+}
+
+func (b *TelegramBot) handleNoopCallback(_ context.Context, update tgbotapi.Update, _ *callback.Data) {
+	b.answerCallback(update.CallbackQuery.From.UserName, update.CallbackQuery.Message.Chat.ID, update, "🤷Nothing to do here")
+}
+
+func (b *TelegramBot) handleFavoriteCallback(ctx context.Context, update tgbotapi.Update, data *callback.Data) {
+	if b.addFavoriteFromCallback(ctx, update, data) {
+		b.answerCallback(update.CallbackQuery.From.UserName, update.CallbackQuery.Message.Chat.ID, update, "✅")
+	}
+}
+
+func (b *TelegramBot) handleNoteCallback(ctx context.Context, update tgbotapi.Update, data *callback.Data) {
+	if b.addFavoriteFromCallback(ctx, update, data) {
+		b.answerCallback(update.CallbackQuery.From.UserName, update.CallbackQuery.Message.Chat.ID, update, "📝Saved to favorites, reply to the listing message with your note")
+	}
+}
+
+func (b *TelegramBot) handlePriceHistoryCallback(ctx context.Context, update tgbotapi.Update, data *callback.Data) {
+	user := update.CallbackQuery.From
+	chatID := update.CallbackQuery.Message.Chat.ID
+	b.answerCallback(user.UserName, chatID, update, "")
+	if !b.canDo(ctx, user.UserName, chatID) {
+		return
+	}
+	b.commands.ShowPriceHistory(ctx, user.UserName, chatID, data.ID)
+}
+
+func (b *TelegramBot) handleDetailsCallback(ctx context.Context, update tgbotapi.Update, data *callback.Data) {
+	user := update.CallbackQuery.From
+	chatID := update.CallbackQuery.Message.Chat.ID
+	b.answerCallback(user.UserName, chatID, update, "")
+	if !b.canDo(ctx, user.UserName, chatID) {
+		return
+	}
+	b.commands.ShowListingDetails(ctx, user.UserName, chatID, data.ID)
+}
+
+func (b *TelegramBot) handleHideCallback(ctx context.Context, update tgbotapi.Update, data *callback.Data) {
+	user := update.CallbackQuery.From
+	chatID := update.CallbackQuery.Message.Chat.ID
+	b.answerCallback(user.UserName, chatID, update, "🙈Hidden")
+	if !b.canDo(ctx, user.UserName, chatID) {
+		return
+	}
+	b.commands.HideListing(ctx, user.UserName, chatID, update.CallbackQuery.Message.MessageID, data)
+}
+
+func (b *TelegramBot) handleUnhideCallback(ctx context.Context, update tgbotapi.Update, data *callback.Data) {
+	user := update.CallbackQuery.From
+	chatID := update.CallbackQuery.Message.Chat.ID
+	b.answerCallback(user.UserName, chatID, update, "")
+	if !b.canDo(ctx, user.UserName, chatID) {
+		return
+	}
+	b.commands.UnhideListing(ctx, user.UserName, chatID, update.CallbackQuery.Message.MessageID, data)
+}
+
+func (b *TelegramBot) handleFavoritesCallback(ctx context.Context, update tgbotapi.Update, data *callback.Data) {
+	user := update.CallbackQuery.From
+	chatID := update.CallbackQuery.Message.Chat.ID
+	b.answerCallback(user.UserName, chatID, update, "")
+	if !b.canDo(ctx, user.UserName, chatID) {
+		return
+	}
+	b.commands.HandleFavoritesCallback(ctx, user.UserName, chatID, update.CallbackQuery.Message.MessageID, data)
+}
+
+func (b *TelegramBot) handleStageCallback(ctx context.Context, update tgbotapi.Update, data *callback.Data) {
+	user := update.CallbackQuery.From
+	chatID := update.CallbackQuery.Message.Chat.ID
+	b.answerCallback(user.UserName, chatID, update, "")
+	if !b.canDo(ctx, user.UserName, chatID) {
+		return
+	}
+	b.commands.HandleStageCallback(ctx, user.UserName, chatID, update.CallbackQuery.Message.MessageID, data)
+}
+
+func (b *TelegramBot) handleSeenCallback(ctx context.Context, update tgbotapi.Update, data *callback.Data) {
+	user := update.CallbackQuery.From
+	chatID := update.CallbackQuery.Message.Chat.ID
+	b.answerCallback(user.UserName, chatID, update, "👁Seen")
+	if !b.canDo(ctx, user.UserName, chatID) {
+		return
+	}
+	b.commands.MarkSeen(ctx, user.UserName, chatID, update.CallbackQuery.Message.MessageID, data)
+}
+
+func (b *TelegramBot) handleThumbsUpCallback(ctx context.Context, update tgbotapi.Update, data *callback.Data) {
+	user := update.CallbackQuery.From
+	chatID := update.CallbackQuery.Message.Chat.ID
+	b.answerCallback(user.UserName, chatID, update, "👍Noted, listings like this one will rank higher")
+	if !b.canDo(ctx, user.UserName, chatID) {
+		return
+	}
+	b.commands.RateListing(ctx, user.UserName, chatID, update.CallbackQuery.Message.MessageID, data, true)
+}
+
+func (b *TelegramBot) handleThumbsDownCallback(ctx context.Context, update tgbotapi.Update, data *callback.Data) {
+	user := update.CallbackQuery.From
+	chatID := update.CallbackQuery.Message.Chat.ID
+	b.answerCallback(user.UserName, chatID, update, "👎Noted, listings like this one will rank lower")
+	if !b.canDo(ctx, user.UserName, chatID) {
+		return
+	}
+	b.commands.RateListing(ctx, user.UserName, chatID, update.CallbackQuery.Message.MessageID, data, false)
+}
+
+func (b *TelegramBot) handleBrowseCallback(ctx context.Context, update tgbotapi.Update, data *callback.Data) {
+	user := update.CallbackQuery.From
+	chatID := update.CallbackQuery.Message.Chat.ID
+	b.answerCallback(user.UserName, chatID, update, "")
+	if !b.canDo(ctx, user.UserName, chatID) {
+		return
+	}
+	b.commands.HandleBrowseCallback(ctx, user.UserName, chatID, update.CallbackQuery.Message.MessageID, data)
+}
+
+func (b *TelegramBot) handleQueryBuilderCallback(ctx context.Context, update tgbotapi.Update, data *callback.Data) {
+	user := update.CallbackQuery.From
+	chatID := update.CallbackQuery.Message.Chat.ID
+	b.answerCallback(user.UserName, chatID, update, "")
+	if !b.canDo(ctx, user.UserName, chatID) {
+		return
+	}
+	b.commands.HandleQueryBuilderCallback(ctx, user.UserName, chatID, update.CallbackQuery.Message.MessageID, data)
+}
+
+// addFavoriteFromCallback saves a listing given by its UUID to favorites and marks the card as saved, or renders the
+// listing browser anew when the card belongs to it, it reports whether the listing was saved, errors are answered to
+// the callback.
+func (b *TelegramBot) addFavoriteFromCallback(ctx context.Context, update tgbotapi.Update, data *callback.Data) bool {
+	user := update.CallbackQuery.From
+	chatID := update.CallbackQuery.Message.Chat.ID
+	msgID := update.CallbackQuery.Message.MessageID
+
+	listing, err := b.listingsService.GetListingByUUID(ctx, data.ID)
+	if err != nil || listing.UserID != user.UserName {
+		b.log.Error().Err(err).Str("userID", user.UserName).Msg("failed to get a listing inside a callback query")
+		msgTxt := "💥Failed to get a listing inside a callback query"
+		b.sendMessage(chatID, user.UserName, msgTxt, false)
+		b.reactToCallbackError(user.UserName, chatID, update)
+		return false
+	}
+
+	err = b.listingsService.AddFavoriteListing(ctx, listing)
+	if err != nil {
+		b.log.Error().Err(err).Str("userID", user.UserName).Msg("failed to add a favorite listing")
+		msgTxt := "💥Failed to add a favorite listing"
+		b.sendMessage(chatID, user.UserName, msgTxt, false)
+		b.reactToCallbackError(user.UserName, chatID, update)
+		return false
+	}
+
+	if len(data.Args) != 0 {
+		b.commands.RefreshCard(ctx, user.UserName, chatID, msgID, data.Args)
+		return true
+	}
+	edit := tgbotapi.NewEditMessageReplyMarkup(chatID, msgID, *commands.ListingCardKeyboard(listing, true))
+	if _, err = b.bot.Request(edit); err != nil {
+		b.log.Error().Err(err).Str("userID", user.UserName).Int64("chatID", chatID).Msg("failed to update button")
+	}
+	return true
+}
+
+func (b *TelegramBot) answerCallback(userID string, chatID int64, update tgbotapi.Update, text string) {
+	_, err := b.bot.Request(tgbotapi.NewCallback(update.CallbackQuery.ID, text))
+	if err != nil {
+		b.log.Error().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to send callback call to")
+	}
+}
+
+func (b *TelegramBot) reactToCallbackError(userID string, chatID int64, update tgbotapi.Update) {
+	b.answerCallback(userID, chatID, update, "💥either an error or you have already added it to favorites")
+}
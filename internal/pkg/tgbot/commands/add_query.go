@@ -0,0 +1,35 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"fundaNotifier/internal/domain/search_queries"
+	"strings"
+)
+
+func (c *TelegramBotCommands) AddQuery(ctx context.Context, userID string, chatID int64, args string) {
+	fields := strings.Fields(args)
+	if len(fields) != 2 {
+		msgTxt := "⚠️Usage: /add_query <name> <URL> (e.g. `/add_query utrecht_rent https://www.funda.nl/zoeken/huur?...`)"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	queryName := search_queries.NormalizeName(fields[0])
+	if !validateURL(fields[1]) {
+		c.log.Warn().Str("userID", userID).Int64("chatID", chatID).Msg("failed to validate URL")
+		msgTxt := "⚠️The provided URL is invalid, copy a search page URL directly from browser or use /build_query, the URL cannot target any domain other than funda.nl"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	if err := c.searchQueriesService.UpsertSearchQuery(ctx, userID, queryName, fields[1]); err != nil {
+		c.log.Error().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to add search query")
+		msgTxt := "💥Failed to add search query"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	msgTxt := fmt.Sprintf("✅Search query %s was saved, set its own filters via /set_query_filters", queryName)
+	c.sendMessage(chatID, userID, msgTxt, false)
+}
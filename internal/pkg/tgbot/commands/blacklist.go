@@ -0,0 +1,65 @@
+package commands
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"fundaNotifier/internal/domain/blacklist"
+	"strings"
+)
+
+func (c *TelegramBotCommands) Blacklist(ctx context.Context, userID string, chatID int64, args string) {
+	kind, value, ok := parseBlacklistArgs(args)
+	if !ok {
+		msgTxt := fmt.Sprintf("⚠️Usage: /blacklist <%s> <value>, e.g. /blacklist postcode 1012", strings.Join(blacklist.Kinds, "|"))
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	rule, err := c.blacklistService.AddRule(ctx, userID, kind, value)
+	if err != nil {
+		c.log.Error().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to add blacklist rule")
+		msgTxt := "💥Failed to add blacklist rule"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	msgTxt := fmt.Sprintf("✅Listings matching %s %q are hidden from now on, run /show_hidden to review them", rule.Kind, rule.Value)
+	c.sendMessage(chatID, userID, msgTxt, false)
+}
+
+func (c *TelegramBotCommands) Unblacklist(ctx context.Context, userID string, chatID int64, args string) {
+	kind, value, ok := parseBlacklistArgs(args)
+	if !ok {
+		msgTxt := fmt.Sprintf("⚠️Usage: /unblacklist <%s> <value>, run /show_hidden to see blacklist rules", strings.Join(blacklist.Kinds, "|"))
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	err := c.blacklistService.RemoveRule(ctx, userID, kind, value)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			msgTxt := "🤷There is no such blacklist rule, run /show_hidden to see blacklist rules"
+			c.sendMessage(chatID, userID, msgTxt, false)
+			return
+		}
+		c.log.Error().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to remove blacklist rule")
+		msgTxt := "💥Failed to remove blacklist rule"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	msgTxt := "✅Blacklist rule removed"
+	c.sendMessage(chatID, userID, msgTxt, false)
+}
+
+// parseBlacklistArgs splits `<kind> <value>` command arguments, the value may contain spaces.
+func parseBlacklistArgs(args string) (string, string, bool) {
+	kind, value, _ := strings.Cut(strings.TrimSpace(args), " ")
+	kind, value = strings.ToLower(kind), strings.TrimSpace(value)
+	if !blacklist.IsValidKind(kind) || value == "" {
+		return "", "", false
+	}
+	return kind, value, true
+}
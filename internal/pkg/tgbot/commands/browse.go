@@ -0,0 +1,226 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"fundaNotifier/internal/domain/listings"
+	"fundaNotifier/internal/pkg/tgbot/callback"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	browserSortDate  = "date"
+	browserSortPrice = "price"
+	browserSortArea  = "area"
+	browserSortScore = "score"
+	browserSortMatch = "match"
+
+	// browser filter toggles are single letters concatenated into a callback argument
+	browserFilterNew     = "n"
+	browserFilterUnsaved = "u"
+)
+
+var (
+	browserSorts = []struct{ key, label string }{
+		{browserSortDate, "🕒Newest"},
+		{browserSortPrice, "💶Cheapest"},
+		{browserSortArea, "📐Largest"},
+		{browserSortScore, "⭐Best"},
+		{browserSortMatch, "🎯Match"},
+	}
+	browserFilters = []struct{ key, label string }{
+		{browserFilterNew, "🆕New only"},
+		{browserFilterUnsaved, "💚Not saved"},
+	}
+)
+
+// browserState is everything the listing browser needs to render a page, it travels in callback data.
+type browserState struct {
+	page    int
+	sort    string
+	filters string
+}
+
+// parseBrowserState restores a browser state from callback args laid out as page, sort order and filters.
+func parseBrowserState(args []string) browserState {
+	state := browserState{sort: browserSortDate}
+	if len(args) > 0 {
+		state.page, _ = strconv.Atoi(args[0])
+	}
+	if len(args) > 1 && args[1] != "" {
+		state.sort = args[1]
+	}
+	if len(args) > 2 {
+		state.filters = args[2]
+	}
+	return state
+}
+
+func (s browserState) args() []string {
+	return []string{strconv.Itoa(s.page), s.sort, s.filters}
+}
+
+func (s browserState) hasFilter(filter string) bool {
+	return strings.Contains(s.filters, filter)
+}
+
+func (s browserState) toggleFilter(filter string) browserState {
+	if s.hasFilter(filter) {
+		s.filters = strings.ReplaceAll(s.filters, filter, "")
+	} else {
+		s.filters += filter
+	}
+	s.page = 0
+	return s
+}
+
+func (s browserState) button(label string) tgbotapi.InlineKeyboardButton {
+	return callbackButton(label, callback.New(callback.ActionBrowse, strconv.Itoa(s.page), s.sort, s.filters))
+}
+
+func (c *TelegramBotCommands) Browse(ctx context.Context, userID string, chatID int64) {
+	msgTxt, keyboard, ok := c.renderBrowser(ctx, userID, chatID, browserState{sort: browserSortDate})
+	if !ok {
+		return
+	}
+	c.sendMessageWithKeyboard(chatID, userID, msgTxt, keyboard, false)
+}
+
+// HandleBrowseCallback renders the browser page given by the callback data in place.
+func (c *TelegramBotCommands) HandleBrowseCallback(ctx context.Context, userID string, chatID int64, msgID int, data *callback.Data) {
+	c.RefreshBrowser(ctx, userID, chatID, msgID, append([]string{data.ID}, data.Args...))
+}
+
+// RefreshBrowser renders the browser anew after a card action taken inside it, args are the browser state.
+func (c *TelegramBotCommands) RefreshBrowser(ctx context.Context, userID string, chatID int64, msgID int, args []string) {
+	msgTxt, keyboard, ok := c.renderBrowser(ctx, userID, chatID, parseBrowserState(args))
+	if !ok {
+		return
+	}
+	c.editMessage(chatID, userID, msgID, msgTxt, keyboard)
+}
+
+func (c *TelegramBotCommands) renderBrowser(ctx context.Context, userID string, chatID int64, state browserState) (string, *tgbotapi.InlineKeyboardMarkup, bool) {
+	session, err := c.sessionsService.GetSessionByUserID(ctx, userID)
+	if err != nil {
+		c.log.Error().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to get session details")
+		msgTxt := "💥Failed to get your session details"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return "", nil, false
+	}
+
+	allListings, err := c.listingsService.MGetListingByUserID(ctx, userID, state.hasFilter(browserFilterNew))
+	if err != nil {
+		c.log.Error().Err(err).Msg("failed to get all listings")
+		msgTxt := "💥Failed to get all listings"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return "", nil, false
+	}
+	allListings, err = c.filterListings(ctx, session, allListings)
+	if err != nil {
+		msgTxt := "💥Failed to get your blacklist"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return "", nil, false
+	}
+
+	favorites, err := c.listingsService.MGetFavoriteListingByUserID(ctx, userID)
+	if err != nil {
+		c.log.Error().Err(err).Msg("failed to get favorite listings")
+		msgTxt := "💥Failed to get favorite listings"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return "", nil, false
+	}
+	favoritesMap := favorites.MapByObjectID()
+	if state.hasFilter(browserFilterUnsaved) {
+		unsavedListings := make(listings.Listings, 0, len(allListings))
+		for idx := range allListings {
+			if _, ok := favoritesMap[allListings[idx].ObjectID]; !ok {
+				unsavedListings = append(unsavedListings, allListings[idx])
+			}
+		}
+		allListings = unsavedListings
+	}
+
+	rating := c.rating(ctx, session)
+	switch state.sort {
+	case browserSortScore:
+		sortByScore(rating.scorer, allListings)
+	case browserSortMatch:
+		rating.sortByMatch(allListings)
+	case browserSortPrice:
+		allListings.SortByPrice()
+	case browserSortArea:
+		allListings.SortByLivingArea()
+	default:
+		state.sort = browserSortDate
+		allListings.Sort()
+	}
+
+	rows := make([][]tgbotapi.InlineKeyboardButton, 0)
+	var msgTxt string
+	if len(allListings) == 0 {
+		state.page = 0
+		msgTxt = "🤷Nothing to browse, try other filters or call /update_now"
+	} else {
+		state.page = min(max(state.page, 0), len(allListings)-1)
+		listing := &allListings[state.page]
+		_, isFavorite := favoritesMap[listing.ObjectID]
+		msgTxt = fmt.Sprintf("🔎%d/%d\n\n%s", state.page+1, len(allListings), formatBrowsedListing(listing, session.Location(), rating.label(listing)))
+		rows = append(rows, listingCardRows(listing, isFavorite, state.args()...)...)
+		c.markSeen(ctx, userID, []string{listing.ObjectID})
+
+		navigation := make([]tgbotapi.InlineKeyboardButton, 0, 2)
+		if state.page > 0 {
+			prev := state
+			prev.page--
+			navigation = append(navigation, prev.button("◀️"))
+		}
+		if state.page < len(allListings)-1 {
+			next := state
+			next.page++
+			navigation = append(navigation, next.button("▶️"))
+		}
+		if len(navigation) != 0 {
+			rows = append(rows, navigation)
+		}
+	}
+
+	sortRow := make([]tgbotapi.InlineKeyboardButton, 0, len(browserSorts))
+	for _, sort := range browserSorts {
+		label, sorted := sort.label, state
+		if sort.key == state.sort {
+			label = pipelineCurrentMarker + label
+		}
+		sorted.sort, sorted.page = sort.key, 0
+		sortRow = append(sortRow, sorted.button(label))
+	}
+	filterRow := make([]tgbotapi.InlineKeyboardButton, 0, len(browserFilters))
+	for _, filter := range browserFilters {
+		label := filter.label
+		if state.hasFilter(filter.key) {
+			label = pipelineCurrentMarker + label
+		}
+		filterRow = append(filterRow, state.toggleFilter(filter.key).button(label))
+	}
+	rows = append(rows, sortRow, filterRow)
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(rows...)
+	return msgTxt, &keyboard, true
+}
+
+// formatBrowsedListing renders a listing as plain text, label is its rating or empty when it is not rated.
+func formatBrowsedListing(listing *listings.Listing, loc *time.Location, label string) string {
+	msgTxt := fmt.Sprintf("%s🏠%s\n💶%.0f %s", label, listing.Name, listing.Offers.Price, listing.Offers.PriceCurrency)
+	if listing.LivingArea != 0 {
+		msgTxt += fmt.Sprintf("\n📐%d m²", listing.LivingArea)
+	}
+	msgTxt += fmt.Sprintf("\n📍%s, %s, %s\n🕒%s", listing.Address.AddressRegion, listing.Address.AddressLocality, listing.Address.StreetAddress, listing.CreatedAt.In(loc).Format(time.RFC850))
+	if listing.IsNew {
+		msgTxt += "\n🆕New since the last sync"
+	}
+	return msgTxt + "\n" + listing.URL
+}
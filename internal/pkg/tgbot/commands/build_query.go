@@ -0,0 +1,360 @@
+package commands
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"fundaNotifier/internal/domain/search_queries"
+	"fundaNotifier/internal/pkg/funda"
+	"fundaNotifier/internal/pkg/tgbot/callback"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	queryBuilderAny  = "any"
+	queryBuilderDone = "done"
+)
+
+type queryBuilderStep int
+
+const (
+	stepOffering queryBuilderStep = iota
+	stepArea
+	stepPrice
+	stepObjectType
+	stepFloorArea
+	stepPublicationDate
+	stepSummary
+)
+
+var (
+	rentPriceRanges = [][2]int{{0, 1000}, {1000, 1500}, {1500, 2000}, {2000, 0}}
+	buyPriceRanges  = [][2]int{{0, 300000}, {300000, 500000}, {500000, 750000}, {750000, 0}}
+	floorAreaRanges = [][2]int{{30, 50}, {50, 75}, {75, 100}, {100, 150}, {150, 0}}
+)
+
+type queryDraft struct {
+	name  string
+	msgID int
+	step  queryBuilderStep
+	query *funda.SearchQuery
+}
+
+// queryDrafts keeps in-memory drafts of the guided query builder by user ID, drafts do not survive restarts.
+type queryDrafts struct {
+	mu   sync.Mutex
+	data map[string]*queryDraft
+}
+
+func newQueryDrafts() *queryDrafts {
+	return &queryDrafts{data: make(map[string]*queryDraft)}
+}
+
+func (d *queryDrafts) get(userID string) (*queryDraft, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	draft, ok := d.data[userID]
+	return draft, ok
+}
+
+func (d *queryDrafts) set(userID string, draft *queryDraft) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.data[userID] = draft
+}
+
+func (d *queryDrafts) remove(userID string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.data, userID)
+}
+
+func (c *TelegramBotCommands) BuildQuery(ctx context.Context, userID string, chatID int64, queryName string) {
+	queryName = search_queries.NormalizeName(queryName)
+	if queryName == "" {
+		queryName = search_queries.DefaultQueryName
+	}
+
+	draft := queryDraft{name: queryName, step: stepOffering, query: funda.NewSearchQuery()}
+
+	// start from the existing query so that it can be adjusted rather than rebuilt from scratch
+	existingQuery, err := c.searchQueriesService.GetSearchQueryByName(ctx, userID, queryName)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		c.log.Error().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to get search query")
+		msgTxt := "💥Failed to get search query"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+	if existingQuery != nil {
+		if parsedQuery, errParse := funda.Parse(existingQuery.URL); errParse == nil {
+			draft.query = parsedQuery
+		}
+	}
+
+	c.sendQueryDraft(userID, chatID, &draft)
+}
+
+// HandleQueryBuilderCallback processes callbacks of the guided query builder, args are the step action and its value.
+func (c *TelegramBotCommands) HandleQueryBuilderCallback(ctx context.Context, userID string, chatID int64, msgID int, data *callback.Data) {
+	draft, ok := c.queryDrafts.get(userID)
+	if !ok || draft.msgID != msgID {
+		c.editMessage(chatID, userID, msgID, "🤷This query builder has expired, run /build_query to start over", nil)
+		return
+	}
+
+	value := data.Arg(1)
+	switch data.Arg(0) {
+	case "offering":
+		draft.query.Offering = value
+		draft.query.PriceMin, draft.query.PriceMax = 0, 0
+		draft.step = stepArea
+	case "area":
+		draft.query.Areas = []string{value}
+		draft.step = stepPrice
+	case "price":
+		draft.query.PriceMin, draft.query.PriceMax = 0, 0
+		if value != queryBuilderAny {
+			lo, hi, _ := strings.Cut(value, "-")
+			draft.query.PriceMin, _ = strconv.Atoi(lo)
+			draft.query.PriceMax, _ = strconv.Atoi(hi)
+		}
+		draft.step = stepObjectType
+	case "type":
+		if value == queryBuilderDone {
+			draft.step = stepFloorArea
+			break
+		}
+		if idx := slices.Index(draft.query.ObjectTypes, value); idx >= 0 {
+			draft.query.ObjectTypes = slices.Delete(draft.query.ObjectTypes, idx, idx+1)
+		} else {
+			draft.query.ObjectTypes = append(draft.query.ObjectTypes, value)
+		}
+	case "floor":
+		draft.query.FloorAreaMin, draft.query.FloorAreaMax = 0, 0
+		if value != queryBuilderAny {
+			lo, hi, _ := strings.Cut(value, "-")
+			draft.query.FloorAreaMin, _ = strconv.Atoi(lo)
+			draft.query.FloorAreaMax, _ = strconv.Atoi(hi)
+		}
+		draft.step = stepPublicationDate
+	case "pub":
+		draft.query.PublicationDate, _ = strconv.Atoi(value)
+		draft.step = stepSummary
+	case "restart":
+		draft.step = stepOffering
+	case "cancel":
+		c.queryDrafts.remove(userID)
+		c.editMessage(chatID, userID, msgID, "❌Query builder was cancelled", nil)
+		return
+	case "save":
+		c.saveQueryDraft(ctx, userID, chatID, draft)
+		return
+	}
+
+	text, keyboard := renderQueryDraft(draft)
+	c.editMessage(chatID, userID, msgID, text, keyboard)
+}
+
+// HandleQueryBuilderText consumes free-text area input, it reports whether the message was consumed.
+func (c *TelegramBotCommands) HandleQueryBuilderText(userID string, chatID int64, text string) bool {
+	draft, ok := c.queryDrafts.get(userID)
+	if !ok || draft.step != stepArea {
+		return false
+	}
+
+	areas := make([]string, 0)
+	for _, area := range strings.Split(text, ",") {
+		area = strings.Join(strings.Fields(strings.ToLower(area)), "-")
+		if area != "" && !slices.Contains(areas, area) {
+			areas = append(areas, area)
+		}
+	}
+	if len(areas) == 0 {
+		msgTxt := "⚠️Please type at least one area"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return true
+	}
+
+	draft.query.Areas = areas
+	draft.step = stepPrice
+	c.sendQueryDraft(userID, chatID, draft)
+	return true
+}
+
+func (c *TelegramBotCommands) saveQueryDraft(ctx context.Context, userID string, chatID int64, draft *queryDraft) {
+	URL, err := draft.query.Build()
+	if err != nil {
+		c.log.Warn().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to build search query")
+		c.editMessage(chatID, userID, draft.msgID, "⚠️Invalid search query: "+err.Error(), restartKeyboard())
+		return
+	}
+
+	if err = c.searchQueriesService.UpsertSearchQuery(ctx, userID, draft.name, URL); err != nil {
+		c.log.Error().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to save search query")
+		c.editMessage(chatID, userID, draft.msgID, "💥Failed to save search query", restartKeyboard())
+		return
+	}
+
+	c.queryDrafts.remove(userID)
+	msgTxt := fmt.Sprintf("✅Search query %s was saved\n%s\n%s", draft.name, describeSearchQuery(draft.query), URL)
+	c.editMessage(chatID, userID, draft.msgID, msgTxt, nil)
+}
+
+func (c *TelegramBotCommands) sendQueryDraft(userID string, chatID int64, draft *queryDraft) {
+	text, keyboard := renderQueryDraft(draft)
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.DisableWebPagePreview = true
+	if keyboard != nil {
+		msg.ReplyMarkup = keyboard
+	}
+	sentMsg, err := c.bot.Send(msg)
+	if err != nil {
+		c.log.Error().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to send query builder to")
+		return
+	}
+
+	draft.msgID = sentMsg.MessageID
+	c.queryDrafts.set(userID, draft)
+}
+
+func (c *TelegramBotCommands) editMessage(chatID int64, userID string, msgID int, text string, keyboard *tgbotapi.InlineKeyboardMarkup) {
+	var edit tgbotapi.EditMessageTextConfig
+	if keyboard != nil {
+		edit = tgbotapi.NewEditMessageTextAndMarkup(chatID, msgID, text, *keyboard)
+	} else {
+		edit = tgbotapi.NewEditMessageText(chatID, msgID, text)
+	}
+	edit.DisableWebPagePreview = true
+	if _, err := c.bot.Request(edit); err != nil {
+		c.log.Error().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to edit message")
+	}
+}
+
+func renderQueryDraft(draft *queryDraft) (string, *tgbotapi.InlineKeyboardMarkup) {
+	header := fmt.Sprintf("🛠️Building search query %s\n%s\n\n", draft.name, describeSearchQuery(draft.query))
+	var rows [][]tgbotapi.InlineKeyboardButton
+
+	switch draft.step {
+	case stepOffering:
+		header += "Do you want to buy or to rent?"
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			queryBuilderButton("🔑Rent", "offering", funda.OfferingRent),
+			queryBuilderButton("🏡Buy", "offering", funda.OfferingBuy),
+		))
+	case stepArea:
+		header += "📍Type area(s) as a comma-separated list of cities or neighbourhoods (e.g. `utrecht, den haag`) or search the whole country"
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(queryBuilderButton("🇳🇱Whole country", "area", "nl")))
+	case stepPrice:
+		header += "💶Pick a price range"
+		priceRanges := rentPriceRanges
+		if draft.query.Offering == funda.OfferingBuy {
+			priceRanges = buyPriceRanges
+		}
+		var row []tgbotapi.InlineKeyboardButton
+		for idx := range priceRanges {
+			lo, hi := priceRanges[idx][0], priceRanges[idx][1]
+			row = append(row, queryBuilderButton(formatRange(lo, hi), "price", fmt.Sprintf("%d-%d", lo, hi)))
+		}
+		rows = append(rows, row, tgbotapi.NewInlineKeyboardRow(queryBuilderButton("Any price", "price", queryBuilderAny)))
+	case stepObjectType:
+		header += "🏘️Toggle object types, none means all of them"
+		var row []tgbotapi.InlineKeyboardButton
+		for idx := range funda.ObjectTypes {
+			label := funda.ObjectTypes[idx]
+			if slices.Contains(draft.query.ObjectTypes, label) {
+				label = "✅" + label
+			}
+			row = append(row, queryBuilderButton(label, "type", funda.ObjectTypes[idx]))
+			if len(row) == 3 {
+				rows = append(rows, row)
+				row = nil
+			}
+		}
+		if len(row) > 0 {
+			rows = append(rows, row)
+		}
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(queryBuilderButton("➡️Next", "type", queryBuilderDone)))
+	case stepFloorArea:
+		header += "📐Pick a floor area range"
+		var row []tgbotapi.InlineKeyboardButton
+		for idx := range floorAreaRanges {
+			lo, hi := floorAreaRanges[idx][0], floorAreaRanges[idx][1]
+			row = append(row, queryBuilderButton(formatRange(lo, hi)+"m²", "floor", fmt.Sprintf("%d-%d", lo, hi)))
+		}
+		rows = append(rows, row, tgbotapi.NewInlineKeyboardRow(queryBuilderButton("Any floor area", "floor", queryBuilderAny)))
+	case stepPublicationDate:
+		header += "📅Pick how recent listings should be"
+		var row []tgbotapi.InlineKeyboardButton
+		for idx := range funda.PublicationDates {
+			row = append(row, queryBuilderButton(fmt.Sprintf("%dd", funda.PublicationDates[idx]), "pub", strconv.Itoa(funda.PublicationDates[idx])))
+		}
+		rows = append(rows, row, tgbotapi.NewInlineKeyboardRow(queryBuilderButton("Any time", "pub", "0")))
+	case stepSummary:
+		URL, err := draft.query.Build()
+		if err != nil {
+			header += "⚠️Invalid search query: " + err.Error()
+		} else {
+			header += URL
+		}
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			queryBuilderButton("💾Save", "save", ""),
+			queryBuilderButton("🔁Start over", "restart", ""),
+		))
+	}
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(queryBuilderButton("❌Cancel", "cancel", "")))
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(rows...)
+	return header, &keyboard
+}
+
+func restartKeyboard() *tgbotapi.InlineKeyboardMarkup {
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+		queryBuilderButton("🔁Start over", "restart", ""),
+		queryBuilderButton("❌Cancel", "cancel", ""),
+	))
+	return &keyboard
+}
+
+func queryBuilderButton(label, action, value string) tgbotapi.InlineKeyboardButton {
+	return callbackButton(label, callback.New(callback.ActionQueryBuilder, "", action, value))
+}
+
+// describeSearchQuery renders a structured search query as a short human-readable summary.
+func describeSearchQuery(q *funda.SearchQuery) string {
+	offering := "🔑Rent"
+	if q.Offering == funda.OfferingBuy {
+		offering = "🏡Buy"
+	}
+	objectTypes := "all"
+	if len(q.ObjectTypes) > 0 {
+		objectTypes = strings.Join(q.ObjectTypes, ", ")
+	}
+	floorArea := formatRange(q.FloorAreaMin, q.FloorAreaMax)
+	if q.FloorAreaMin > 0 || q.FloorAreaMax > 0 {
+		floorArea += "m²"
+	}
+	publicationDate := "any time"
+	if q.PublicationDate > 0 {
+		publicationDate = fmt.Sprintf("last %d day(s)", q.PublicationDate)
+	}
+	return fmt.Sprintf("%s in %s\n💶Price: %s\n🏘️Object types: %s\n📐Floor area: %s\n📅Published: %s", offering, strings.Join(q.Areas, ", "), formatRange(q.PriceMin, q.PriceMax), objectTypes, floorArea, publicationDate)
+}
+
+func formatRange(lo, hi int) string {
+	switch {
+	case lo == 0 && hi == 0:
+		return "any"
+	case hi == 0:
+		return fmt.Sprintf("%d+", lo)
+	case lo == 0:
+		return fmt.Sprintf("up to %d", hi)
+	default:
+		return fmt.Sprintf("%d-%d", lo, hi)
+	}
+}
@@ -2,18 +2,27 @@ package commands
 
 import (
 	"fundaNotifier/internal/pkg/geo"
+	"fundaNotifier/internal/pkg/tgbot/callback"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/rs/zerolog"
 )
 
 type TelegramBotCommands struct {
-	log                  *zerolog.Logger
-	bot                  *tgbotapi.BotAPI
-	listingsService      ListingsService
-	sessionsService      SessionsService
-	searchQueriesService SearchQueriesService
-	cityData             *geo.CityData
+	log                    *zerolog.Logger
+	bot                    *tgbotapi.BotAPI
+	listingsService        ListingsService
+	sessionsService        SessionsService
+	searchQueriesService   SearchQueriesService
+	dndWindowsService      DNDWindowsService
+	urgentRulesService     UrgentRulesService
+	watchedListingsService WatchedListingsService
+	blacklistService       BlacklistService
+	viewingsService        ViewingsService
+	scoringService         ScoringService
+	preferencesService     PreferencesService
+	cityData               *geo.CityData
+	queryDrafts            *queryDrafts
 }
 
 func NewTelegramBotCommands(
@@ -22,15 +31,30 @@ func NewTelegramBotCommands(
 	listingsService ListingsService,
 	sessionsService SessionsService,
 	searchQueriesService SearchQueriesService,
+	dndWindowsService DNDWindowsService,
+	urgentRulesService UrgentRulesService,
+	watchedListingsService WatchedListingsService,
+	blacklistService BlacklistService,
+	viewingsService ViewingsService,
+	scoringService ScoringService,
+	preferencesService PreferencesService,
 	cityData *geo.CityData,
 ) *TelegramBotCommands {
 	return &TelegramBotCommands{
-		log:                  log,
-		bot:                  bot,
-		listingsService:      listingsService,
-		sessionsService:      sessionsService,
-		searchQueriesService: searchQueriesService,
-		cityData:             cityData,
+		log:                    log,
+		bot:                    bot,
+		listingsService:        listingsService,
+		sessionsService:        sessionsService,
+		searchQueriesService:   searchQueriesService,
+		dndWindowsService:      dndWindowsService,
+		urgentRulesService:     urgentRulesService,
+		watchedListingsService: watchedListingsService,
+		blacklistService:       blacklistService,
+		viewingsService:        viewingsService,
+		scoringService:         scoringService,
+		preferencesService:     preferencesService,
+		cityData:               cityData,
+		queryDrafts:            newQueryDrafts(),
 	}
 }
 
@@ -58,3 +82,14 @@ func (c *TelegramBotCommands) sendMessageWithKeyboard(chatID int64, userID, mess
 		c.log.Error().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to send message with keyboard to")
 	}
 }
+
+// callbackButton builds an inline button carrying versioned callback data, data which cannot be encoded turns the
+// button into a no-op one.
+func callbackButton(label string, data callback.Data) tgbotapi.InlineKeyboardButton {
+	encoded, err := data.Encode()
+	if err != nil {
+		noop := callback.New(callback.ActionNoop, "")
+		encoded, _ = noop.Encode()
+	}
+	return tgbotapi.NewInlineKeyboardButtonData(label, encoded)
+}
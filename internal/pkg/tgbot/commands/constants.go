@@ -1,9 +1,16 @@
 package commands
 
 const (
-	minPollingIntervalSeconds = 900
-	defaultDNDStart           = "00:00"
-	defaultDNDEnd             = "00:00"
-	DNDLayout                 = "15:04"
-	messageMaxCharLen         = 4096
+	minPollingIntervalSeconds  = 900
+	DNDLayout                  = "15:04"
+	DNDModeSkip                = "skip"
+	DNDModeQueue               = "queue"
+	messageMaxCharLen          = 4096
+	scheduleLayout             = "Mon 02 Jan 15:04"
+	scheduleRunsToShow         = 5
+	scheduleRunsToValidate     = 500
+	defaultTimezone            = "UTC"
+	defaultUrgentDailyCap      = 3
+	maxUrgentDailyCap          = 20
+	queryHistoryVersionsToShow = 10
 )
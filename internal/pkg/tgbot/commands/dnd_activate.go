@@ -24,6 +24,6 @@ func (c *TelegramBotCommands) ActivateDND(ctx context.Context, userID string, ch
 		return
 	}
 
-	msgTxt := "🌝️You have activated the DND, API polling will be disabled within the DND interval"
+	msgTxt := "🌝️You have activated the DND, API polling will be disabled within the DND windows"
 	c.sendMessage(chatID, userID, msgTxt, false)
 }
@@ -0,0 +1,72 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"fundaNotifier/internal/domain/dnd_windows"
+	"strings"
+	"time"
+)
+
+func (c *TelegramBotCommands) AddDNDWindow(ctx context.Context, userID string, chatID int64, args string) {
+	fields := strings.Fields(args)
+	if len(fields) < 2 {
+		msgTxt := "⚠️Usage: /dnd_add_window <name> [weekdays] HH:MM-HH:MM (e.g. `/dnd_add_window nights mon-fri 23:00-07:00`)"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	name := fields[0]
+	timeRange := strings.Split(fields[len(fields)-1], "-")
+	if len(timeRange) != 2 {
+		msgTxt := "⚠️Invalid DND window time range, expected HH:MM-HH:MM"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	if err := isValidTimeRange(timeRange[0], timeRange[1]); err != nil {
+		c.log.Warn().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to validate DND window")
+		msgTxt := "⚠️Invalid DND window time range, expected HH:MM-HH:MM"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	weekdays, err := dnd_windows.ParseWeekdays(strings.Join(fields[1:len(fields)-1], ","))
+	if err != nil {
+		c.log.Warn().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to validate DND window weekdays")
+		msgTxt := "⚠️Invalid weekdays, use `daily`, `weekdays`, `weekends` or a comma-separated list of days and ranges (e.g. `mon-fri`, `sat,sun`)"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	err = c.dndWindowsService.AddWindow(ctx, userID, name, weekdays, dayTimeToMinutesAfterMidnight(timeRange[0]), dayTimeToMinutesAfterMidnight(timeRange[1]))
+	if err != nil {
+		c.log.Error().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to add DND window")
+		msgTxt := "💥Failed to add DND window"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	msgTxt := fmt.Sprintf("✅DND window %s was saved", strings.ToLower(name))
+	c.sendMessage(chatID, userID, msgTxt, false)
+	c.ShowDNDSchedule(ctx, userID, chatID)
+}
+
+func isValidTimeRange(start, end string) error {
+	_, err := time.Parse(DNDLayout, start)
+	if err != nil {
+		return fmt.Errorf("invalid start time format: %w", err)
+	}
+
+	_, err = time.Parse(DNDLayout, end)
+	if err != nil {
+		return fmt.Errorf("invalid end time format: %w", err)
+	}
+
+	return nil
+}
+
+func dayTimeToMinutesAfterMidnight(timeStr string) int {
+	t, _ := time.Parse(DNDLayout, timeStr)
+	return t.Hour()*60 + t.Minute()
+}
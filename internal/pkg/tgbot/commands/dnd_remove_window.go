@@ -0,0 +1,34 @@
+package commands
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+func (c *TelegramBotCommands) RemoveDNDWindow(ctx context.Context, userID string, chatID int64, name string) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		msgTxt := "⚠️Usage: /dnd_remove_window <name>, run /dnd_show_schedule to see window names"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	err := c.dndWindowsService.RemoveWindow(ctx, userID, name)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			msgTxt := fmt.Sprintf("🤷DND window %s does not exist", name)
+			c.sendMessage(chatID, userID, msgTxt, false)
+			return
+		}
+		c.log.Error().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to remove DND window")
+		msgTxt := "💥Failed to remove DND window"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	msgTxt := fmt.Sprintf("✅DND window %s was removed", name)
+	c.sendMessage(chatID, userID, msgTxt, false)
+}
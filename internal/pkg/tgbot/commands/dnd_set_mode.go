@@ -0,0 +1,35 @@
+package commands
+
+import (
+	"context"
+	"strings"
+)
+
+func (c *TelegramBotCommands) SetDNDMode(ctx context.Context, userID string, chatID int64, mode string) {
+	var queue bool
+	switch strings.ToLower(strings.TrimSpace(mode)) {
+	case DNDModeSkip:
+		queue = false
+	case DNDModeQueue:
+		queue = true
+	default:
+		msgTxt := "⚠️Invalid DND mode, use `skip` to pause API polling within DND windows or `queue` to hold notifications until DND windows end"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	if err := c.sessionsService.UpdateDNDQueue(ctx, userID, queue); err != nil {
+		c.log.Error().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to update DND mode")
+		msgTxt := "💥Failed to update DND mode"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	var msgTxt string
+	if queue {
+		msgTxt = "✅DND mode was set to queue, API polling continues within DND windows and notifications are delivered as one message once DND is over"
+	} else {
+		msgTxt = "✅DND mode was set to skip, API polling is paused within DND windows"
+	}
+	c.sendMessage(chatID, userID, msgTxt, false)
+}
@@ -3,6 +3,8 @@ package commands
 import (
 	"context"
 	"fmt"
+	"fundaNotifier/internal/domain/dnd_windows"
+	"time"
 )
 
 func (c *TelegramBotCommands) ShowDNDSchedule(ctx context.Context, userID string, chatID int64) {
@@ -14,20 +16,46 @@ func (c *TelegramBotCommands) ShowDNDSchedule(ctx context.Context, userID string
 		return
 	}
 
+	windows, err := c.dndWindowsService.MGetWindowByUserID(ctx, userID)
+	if err != nil {
+		c.log.Error().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to get DND windows")
+		msgTxt := "💥Failed to get DND windows"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	if len(windows) == 0 {
+		msgTxt := "⚠️No DND windows are set, run /dnd_add_window to add one"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	mode := DNDModeSkip
+	if session.DNDQueue {
+		mode = DNDModeQueue
+	}
+
 	var msgTxt string
-	if session.DNDStart != 0 || session.DNDEnd != 0 {
-		if session.DNDActive {
-			msgTxt = fmt.Sprintf("🌝DND is ON\n⏳Schedule: %s UTC - %s UTC", minutesAfterMidnightToDayTime(session.DNDStart), minutesAfterMidnightToDayTime(session.DNDEnd))
-		} else {
-			msgTxt = fmt.Sprintf("🌚DND is OFF\n⏳Schedule: %s UTC - %s UTC", minutesAfterMidnightToDayTime(session.DNDStart), minutesAfterMidnightToDayTime(session.DNDEnd))
-		}
+	if session.DNDActive {
+		msgTxt = fmt.Sprintf("🌝DND is ON, mode: %s (%s)", mode, session.Location().String())
 	} else {
-		msgTxt = "⚠️DND schedule is not set, run /dnd_set_schedule to set it"
+		msgTxt = fmt.Sprintf("🌚DND is OFF, mode: %s (%s)", mode, session.Location().String())
 	}
-
+	if windows.Contains(time.Now().In(session.Location())) {
+		msgTxt += "\n🔕You are within a DND window right now"
+	}
+	msgTxt += formatDNDWindows(windows)
 	c.sendMessage(chatID, userID, msgTxt, false)
 }
 
+func formatDNDWindows(windows dnd_windows.Windows) string {
+	var msgTxt string
+	for idx := range windows {
+		msgTxt += fmt.Sprintf("\n⏳%s: %s %s - %s", windows[idx].Name, windows[idx].FormatWeekdays(), minutesAfterMidnightToDayTime(windows[idx].Start), minutesAfterMidnightToDayTime(windows[idx].End))
+	}
+	return msgTxt
+}
+
 func minutesAfterMidnightToDayTime(minutes int) string {
 	h := minutes / 60
 	m := minutes % 60
@@ -0,0 +1,53 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"fundaNotifier/internal/domain/search_queries"
+	"strings"
+)
+
+func (c *TelegramBotCommands) ListQueries(ctx context.Context, userID string, chatID int64) {
+	searchQueries, err := c.searchQueriesService.MGetSearchQueryByUserID(ctx, userID)
+	if err != nil {
+		c.log.Error().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to get search queries")
+		msgTxt := "💥Failed to get search queries"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	if len(searchQueries) == 0 {
+		msgTxt := "🤷No search queries are set, use /set_search_query or /add_query to add one"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	msgTxt := "🔎Search queries:" + formatSearchQueries(searchQueries)
+	c.sendMessage(chatID, userID, msgTxt, false)
+}
+
+func formatSearchQueries(searchQueries search_queries.SearchQueries) string {
+	if len(searchQueries) == 0 {
+		return " not set"
+	}
+
+	var msgTxt string
+	for idx := range searchQueries {
+		var (
+			state      = "▶️active"
+			msgRegions = "all"
+			msgCities  = "all"
+		)
+		if !searchQueries[idx].IsActive {
+			state = "⏸️paused"
+		}
+		if len(searchQueries[idx].Regions) > 0 {
+			msgRegions = strings.Join(searchQueries[idx].Regions, ", ")
+		}
+		if len(searchQueries[idx].Cities) > 0 {
+			msgCities = strings.Join(searchQueries[idx].Cities, ", ")
+		}
+		msgTxt += fmt.Sprintf("\n\n%s (%s)\n🌍Regions: %s\n📍Cities: %s\n%s", searchQueries[idx].Name, state, msgRegions, msgCities, searchQueries[idx].URL)
+	}
+	return msgTxt
+}
@@ -0,0 +1,114 @@
+package commands
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"fundaNotifier/internal/domain/listings"
+	"fundaNotifier/internal/pkg/tgbot/callback"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const priceHistoryLayout = "02 Jan 2006"
+
+// ListingCardKeyboard builds the buttons shown under a listing card, a saved favorite gets a no-op button instead.
+func ListingCardKeyboard(listing *listings.Listing, isFavorite bool) *tgbotapi.InlineKeyboardMarkup {
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(listingCardRows(listing, isFavorite)...)
+	return &keyboard
+}
+
+// listingCardRows builds the card buttons, args are passed along with the actions which change the card itself so
+// that the message they belong to can be rendered anew.
+func listingCardRows(listing *listings.Listing, isFavorite bool, args ...string) [][]tgbotapi.InlineKeyboardButton {
+	favoriteButton := callbackButton("️➕Save", callback.New(callback.ActionFavorite, listing.UUID, args...))
+	if isFavorite {
+		favoriteButton = callbackButton("💚", callback.New(callback.ActionNoop, ""))
+	}
+
+	return [][]tgbotapi.InlineKeyboardButton{
+		tgbotapi.NewInlineKeyboardRow(
+			favoriteButton,
+			callbackButton("📝Note", callback.New(callback.ActionNote, listing.UUID, args...)),
+			callbackButton("🙈Hide", callback.New(callback.ActionHide, listing.UUID, args...)),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			callbackButton("📈Prices", callback.New(callback.ActionPriceHistory, listing.UUID)),
+			callbackButton("ℹ️Details", callback.New(callback.ActionDetails, listing.UUID)),
+			callbackButton("👍", callback.New(callback.ActionThumbsUp, listing.UUID, args...)),
+			callbackButton("👎", callback.New(callback.ActionThumbsDown, listing.UUID, args...)),
+		),
+	}
+}
+
+func (c *TelegramBotCommands) ShowListingDetails(ctx context.Context, userID string, chatID int64, UUID string) {
+	listing, ok := c.getListingByUUID(ctx, userID, chatID, UUID)
+	if !ok {
+		return
+	}
+	c.sendListingDetails(ctx, userID, chatID, listing)
+}
+
+func (c *TelegramBotCommands) ShowPriceHistory(ctx context.Context, userID string, chatID int64, UUID string) {
+	listing, ok := c.getListingByUUID(ctx, userID, chatID, UUID)
+	if !ok {
+		return
+	}
+
+	session, err := c.sessionsService.GetSessionByUserID(ctx, userID)
+	if err != nil {
+		c.log.Error().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to get session details")
+		msgTxt := "💥Failed to get your session details"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	points, err := c.listingsService.MGetPricePoint(ctx, userID, listing.ObjectID)
+	if err != nil {
+		c.log.Error().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to get price history")
+		msgTxt := "💥Failed to get price history"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+	if len(points) == 0 {
+		msgTxt := fmt.Sprintf("🤷No prices were recorded for %s yet", listing.Name)
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	msgTxt := fmt.Sprintf("📈Price history of %s%s", listing.Name, formatPricePoints(points, session.Location()))
+	c.sendMessage(chatID, userID, msgTxt, false)
+}
+
+// formatPricePoints renders one line per recorded price along with its change from the previous one.
+func formatPricePoints(points listings.PricePoints, loc *time.Location) string {
+	var msgTxt string
+	for idx := range points {
+		msgTxt += fmt.Sprintf("\n%s: %.0f %s", points[idx].RecordedAt.In(loc).Format(priceHistoryLayout), points[idx].Price, points[idx].Currency)
+		if idx > 0 && points[idx-1].Price != 0 {
+			msgTxt += fmt.Sprintf(" (%+.1f%%)", (points[idx].Price-points[idx-1].Price)/points[idx-1].Price*100)
+		}
+	}
+	return msgTxt
+}
+
+func (c *TelegramBotCommands) getListingByUUID(ctx context.Context, userID string, chatID int64, UUID string) (*listings.Listing, bool) {
+	listing, err := c.listingsService.GetListingByUUID(ctx, UUID)
+	if err == nil && listing.UserID != userID {
+		err = fmt.Errorf("listing %s belongs to another user: %w", UUID, sql.ErrNoRows)
+	}
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			msgTxt := "🤷This listing is not stored anymore"
+			c.sendMessage(chatID, userID, msgTxt, false)
+			return nil, false
+		}
+		c.log.Error().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to get listing")
+		msgTxt := "💥Failed to get listing"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return nil, false
+	}
+	return listing, true
+}
@@ -0,0 +1,176 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"fundaNotifier/internal/domain/listings"
+	"fundaNotifier/internal/domain/sessions"
+	"fundaNotifier/internal/pkg/tgbot/callback"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// nextCardArg is passed along with card actions taken in the /next stepper, browser state args never start with it
+const nextCardArg = "next"
+
+// UnseenListings returns stored listings passing the active filters which are neither seen nor saved to favorites, the
+// oldest first.
+func (c *TelegramBotCommands) UnseenListings(ctx context.Context, session *sessions.Session) (listings.Listings, error) {
+	allListings, err := c.listingsService.MGetListingByUserID(ctx, session.UserID, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get all listings: %w", err)
+	}
+	allListings, err = c.filterListings(ctx, session, allListings)
+	if err != nil {
+		return nil, fmt.Errorf("failed to filter listings: %w", err)
+	}
+
+	favorites, err := c.listingsService.MGetFavoriteListingByUserID(ctx, session.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get favorite listings: %w", err)
+	}
+	seenObjectIDs, err := c.listingsService.MGetSeenObjectID(ctx, session.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get seen listings: %w", err)
+	}
+
+	skip := make(map[string]bool, len(favorites)+len(seenObjectIDs))
+	for _, objectID := range append(favorites.ObjectIDs(), seenObjectIDs...) {
+		skip[objectID] = true
+	}
+	unseenListings := make(listings.Listings, 0, len(allListings))
+	for idx := range allListings {
+		if !skip[allListings[idx].ObjectID] {
+			unseenListings = append(unseenListings, allListings[idx])
+		}
+	}
+	unseenListings.SortOldestFirst()
+	return unseenListings, nil
+}
+
+// UnseenCounter renders the unseen listings line of sync messages, it is left out when the count is not available.
+func (c *TelegramBotCommands) UnseenCounter(ctx context.Context, session *sessions.Session) string {
+	unseenListings, err := c.UnseenListings(ctx, session)
+	if err != nil {
+		c.log.Error().Err(err).Str("userID", session.UserID).Msg("failed to count unseen listings")
+		return ""
+	}
+	return fmt.Sprintf("\n📬Unseen listings: %d, see /next", len(unseenListings))
+}
+
+// markSeen remembers rendered listings as seen, a failure is only logged since the listings were shown anyway.
+func (c *TelegramBotCommands) markSeen(ctx context.Context, userID string, objectIDs []string) {
+	if len(objectIDs) == 0 {
+		return
+	}
+	err := c.listingsService.MarkListingsSeen(ctx, userID, objectIDs)
+	if err != nil {
+		c.log.Error().Err(err).Str("userID", userID).Msg("failed to mark shown listings as seen")
+	}
+}
+
+func (c *TelegramBotCommands) Next(ctx context.Context, userID string, chatID int64) {
+	msgTxt, keyboard, ok := c.renderNext(ctx, userID, chatID)
+	if !ok {
+		return
+	}
+	c.sendMessageWithKeyboard(chatID, userID, msgTxt, keyboard, false)
+}
+
+// MarkSeen marks a listing given by its card UUID as seen and shows the next unseen one in place.
+func (c *TelegramBotCommands) MarkSeen(ctx context.Context, userID string, chatID int64, msgID int, data *callback.Data) {
+	listing, ok := c.getListingByUUID(ctx, userID, chatID, data.ID)
+	if !ok {
+		return
+	}
+
+	err := c.listingsService.MarkListingsSeen(ctx, userID, []string{listing.ObjectID})
+	if err != nil {
+		c.log.Error().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to mark listing as seen")
+		msgTxt := "💥Failed to mark listing as seen"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+	c.refreshNext(ctx, userID, chatID, msgID)
+}
+
+func (c *TelegramBotCommands) MarkAllSeen(ctx context.Context, userID string, chatID int64) {
+	session, err := c.sessionsService.GetSessionByUserID(ctx, userID)
+	if err != nil {
+		c.log.Error().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to get session details")
+		msgTxt := "💥Failed to get your session details"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	unseenListings, err := c.UnseenListings(ctx, session)
+	if err != nil {
+		c.log.Error().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to get unseen listings")
+		msgTxt := "💥Failed to get unseen listings"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+	if len(unseenListings) == 0 {
+		msgTxt := "🤷No unseen listings"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	err = c.listingsService.MarkListingsSeen(ctx, userID, unseenListings.ObjectIDs())
+	if err != nil {
+		c.log.Error().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to mark listings as seen")
+		msgTxt := "💥Failed to mark listings as seen"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+	msgTxt := fmt.Sprintf("✅Marked %d listing(s) as seen", len(unseenListings))
+	c.sendMessage(chatID, userID, msgTxt, false)
+}
+
+// RefreshCard renders the message a listing card belongs to anew after a card action, args tell the /next stepper
+// apart from the listing browser.
+func (c *TelegramBotCommands) RefreshCard(ctx context.Context, userID string, chatID int64, msgID int, args []string) {
+	if len(args) != 0 && args[0] == nextCardArg {
+		c.refreshNext(ctx, userID, chatID, msgID)
+		return
+	}
+	c.RefreshBrowser(ctx, userID, chatID, msgID, args)
+}
+
+func (c *TelegramBotCommands) refreshNext(ctx context.Context, userID string, chatID int64, msgID int) {
+	msgTxt, keyboard, ok := c.renderNext(ctx, userID, chatID)
+	if !ok {
+		return
+	}
+	c.editMessage(chatID, userID, msgID, msgTxt, keyboard)
+}
+
+func (c *TelegramBotCommands) renderNext(ctx context.Context, userID string, chatID int64) (string, *tgbotapi.InlineKeyboardMarkup, bool) {
+	session, err := c.sessionsService.GetSessionByUserID(ctx, userID)
+	if err != nil {
+		c.log.Error().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to get session details")
+		msgTxt := "💥Failed to get your session details"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return "", nil, false
+	}
+
+	unseenListings, err := c.UnseenListings(ctx, session)
+	if err != nil {
+		c.log.Error().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to get unseen listings")
+		msgTxt := "💥Failed to get unseen listings"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return "", nil, false
+	}
+	if len(unseenListings) == 0 {
+		return "🎉All caught up, no unseen listings left", nil, true
+	}
+
+	listing := &unseenListings[0]
+	msgTxt := fmt.Sprintf("📬%d unseen\n\n%s", len(unseenListings), formatBrowsedListing(listing, session.Location(), c.rating(ctx, session).label(listing)))
+	rows := listingCardRows(listing, false, nextCardArg)
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		callbackButton("👁Seen, next", callback.New(callback.ActionSeen, listing.UUID)),
+	))
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(rows...)
+	return msgTxt, &keyboard, true
+}
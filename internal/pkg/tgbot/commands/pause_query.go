@@ -0,0 +1,47 @@
+package commands
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"fundaNotifier/internal/domain/search_queries"
+)
+
+func (c *TelegramBotCommands) PauseQuery(ctx context.Context, userID string, chatID int64, queryName string) {
+	c.setQueryActive(ctx, userID, chatID, queryName, false)
+}
+
+func (c *TelegramBotCommands) ResumeQuery(ctx context.Context, userID string, chatID int64, queryName string) {
+	c.setQueryActive(ctx, userID, chatID, queryName, true)
+}
+
+func (c *TelegramBotCommands) setQueryActive(ctx context.Context, userID string, chatID int64, queryName string, isActive bool) {
+	queryName = search_queries.NormalizeName(queryName)
+	if queryName == "" {
+		msgTxt := "⚠️Query name is required, run /list_queries to see query names"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	err := c.searchQueriesService.SetSearchQueryActive(ctx, userID, queryName, isActive)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			msgTxt := fmt.Sprintf("🤷Search query %s does not exist", queryName)
+			c.sendMessage(chatID, userID, msgTxt, false)
+			return
+		}
+		c.log.Error().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to update search query state")
+		msgTxt := "💥Failed to update search query state"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	var msgTxt string
+	if isActive {
+		msgTxt = fmt.Sprintf("▶️Search query %s was resumed", queryName)
+	} else {
+		msgTxt = fmt.Sprintf("⏸️Search query %s was paused, it will be skipped by API polling until /resume_query", queryName)
+	}
+	c.sendMessage(chatID, userID, msgTxt, false)
+}
@@ -0,0 +1,132 @@
+package commands
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"fundaNotifier/internal/domain/listings"
+	"fundaNotifier/internal/pkg/tgbot/callback"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	pipelineLayout        = "02 Jan 15:04"
+	pipelineStagesPerRow  = 3
+	pipelineCurrentMarker = "✔️"
+)
+
+var stageLabels = map[string]string{
+	listings.StageInterested: "👀Interested",
+	listings.StageContacted:  "📞Contacted",
+	listings.StageViewing:    "🗓Viewing scheduled",
+	listings.StageApplied:    "✍️Applied/bid",
+	listings.StageRejected:   "❌Rejected",
+	listings.StageWon:        "🏆Won",
+}
+
+func (c *TelegramBotCommands) Pipeline(ctx context.Context, userID string, chatID int64) {
+	favorites, err := c.listingsService.MGetFavoriteListingByUserID(ctx, userID)
+	if err != nil {
+		c.log.Error().Err(err).Msg("failed to get favorite listings")
+		msgTxt := "💥Failed to get favorite listings"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+	if len(favorites) == 0 {
+		msgTxt := "🤷Nothing to show, you need to add a favorite first"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+	favorites.SortByRank()
+
+	session, err := c.sessionsService.GetSessionByUserID(ctx, userID)
+	if err != nil {
+		c.log.Error().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to get session details")
+		msgTxt := "💥Failed to get your session details"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	byStage := make(map[string]listings.Listings, len(listings.Stages))
+	for idx := range favorites {
+		byStage[favorites[idx].Stage] = append(byStage[favorites[idx].Stage], favorites[idx])
+	}
+
+	msgTxt := "🧭Pipeline, open a card with 🃏 in /show_favorites to change the stage of a favorite"
+	for _, stage := range listings.Stages {
+		addMsgTxt := fmt.Sprintf("\n\n%s: %d", stageLabel(stage), len(byStage[stage]))
+		for idx := range byStage[stage] {
+			favorite := &byStage[stage][idx]
+			addMsgTxt += fmt.Sprintf("\n#%d %s, %s, since %s 🆔%s", favorite.Rank, favorite.Name, favorite.Address.AddressLocality, favorite.StageChangedAt.In(session.Location()).Format(pipelineLayout), favorite.ObjectID)
+		}
+		if utf8.RuneCountInString(msgTxt+addMsgTxt) > messageMaxCharLen {
+			c.sendMessage(chatID, userID, msgTxt, false)
+			msgTxt = ""
+		}
+		msgTxt += addMsgTxt
+	}
+	c.sendMessage(chatID, userID, msgTxt, false)
+}
+
+// HandleStageCallback moves a favorite to the stage given as the arg and renders its card anew.
+func (c *TelegramBotCommands) HandleStageCallback(ctx context.Context, userID string, chatID int64, msgID int, data *callback.Data) {
+	_, err := c.listingsService.SetFavoriteListingStage(ctx, userID, data.ID, data.Arg(0))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			msgTxt := "🤷This listing is not in your favorites anymore"
+			c.sendMessage(chatID, userID, msgTxt, false)
+			return
+		}
+		c.log.Error().Err(err).Str("userID", userID).Str("objectID", data.ID).Msg("failed to set favorite listing stage")
+		msgTxt := "💥Failed to set pipeline stage"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	msgTxt, keyboard, ok := c.renderFavoriteCard(ctx, userID, chatID, data.ID)
+	if !ok {
+		return
+	}
+	c.editMessage(chatID, userID, msgID, msgTxt, keyboard)
+}
+
+// pipelineKeyboard offers every stage of the pipeline, the current one is marked.
+func pipelineKeyboard(favorite *listings.Listing) *tgbotapi.InlineKeyboardMarkup {
+	rows := make([][]tgbotapi.InlineKeyboardButton, 0, len(listings.Stages)/pipelineStagesPerRow+1)
+	for idx, stage := range listings.Stages {
+		if idx%pipelineStagesPerRow == 0 {
+			rows = append(rows, tgbotapi.NewInlineKeyboardRow())
+		}
+		label := stageLabel(stage)
+		if stage == favorite.Stage {
+			label = pipelineCurrentMarker + label
+		}
+		rows[len(rows)-1] = append(rows[len(rows)-1], callbackButton(label, callback.New(callback.ActionStage, favorite.ObjectID, stage)))
+	}
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(rows...)
+	return &keyboard
+}
+
+// formatStageHistory renders when a favorite entered each stage, e.g. `🧭👀Interested 12 Oct 10:00 → 📞Contacted ...`.
+func formatStageHistory(events listings.StageEvents, loc *time.Location) string {
+	if len(events) == 0 {
+		return "🧭No pipeline history"
+	}
+	steps := make([]string, 0, len(events))
+	for idx := range events {
+		steps = append(steps, fmt.Sprintf("%s %s", stageLabel(events[idx].Stage), events[idx].CreatedAt.In(loc).Format(pipelineLayout)))
+	}
+	return "🧭" + strings.Join(steps, " → ")
+}
+
+func stageLabel(stage string) string {
+	if label, ok := stageLabels[stage]; ok {
+		return label
+	}
+	return stage
+}
@@ -0,0 +1,251 @@
+package commands
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"fundaNotifier/internal/domain/listings"
+	"fundaNotifier/internal/domain/preferences"
+	"fundaNotifier/internal/domain/scoring"
+	"fundaNotifier/internal/domain/sessions"
+	"fundaNotifier/internal/domain/urgent_rules"
+	"fundaNotifier/internal/pkg/tgbot/callback"
+	"strconv"
+	"strings"
+)
+
+// preferencesShownFeatures is how many of the most liked and disliked features /preferences shows
+const preferencesShownFeatures = 5
+
+// featureLabels describe numeric features, the first label applies to a positive weight and the second to a negative one
+var featureLabels = map[string][2]string{
+	preferences.FeaturePrice:         {"💶more expensive", "💶cheaper"},
+	preferences.FeaturePricePerMeter: {"📊pricier per m²", "📊cheaper per m²"},
+	preferences.FeatureArea:          {"📐larger", "📐smaller"},
+	preferences.FeatureRooms:         {"🚪more rooms", "🚪fewer rooms"},
+	preferences.FeatureEnergy:        {"🔋better energy label", "🔋worse energy label"},
+}
+
+// RateListing stores a thumbs up or down given to a listing card, the browser and /next render the card anew so that its
+// match probability is updated.
+func (c *TelegramBotCommands) RateListing(ctx context.Context, userID string, chatID int64, msgID int, data *callback.Data, liked bool) {
+	listing, ok := c.getListingByUUID(ctx, userID, chatID, data.ID)
+	if !ok {
+		return
+	}
+
+	err := c.preferencesService.SetFeedback(ctx, userID, listing, liked)
+	if err != nil {
+		c.log.Error().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to store feedback")
+		msgTxt := "💥Failed to store feedback"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	if len(data.Args) != 0 {
+		c.RefreshCard(ctx, userID, chatID, msgID, data.Args)
+	}
+}
+
+func (c *TelegramBotCommands) ShowPreferences(ctx context.Context, userID string, chatID int64) {
+	model, err := c.preferencesService.GetModel(ctx, userID)
+	if err != nil {
+		c.log.Error().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to get preference model")
+		msgTxt := "💥Failed to get your learned preferences"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+	settings, err := c.preferencesService.GetSettings(ctx, userID)
+	if err != nil {
+		c.log.Error().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to get preference settings")
+		msgTxt := "💥Failed to get your preference settings"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	msgTxt := fmt.Sprintf("🎯Learned preferences\n👍%d 👎%d", model.Likes, model.Dislikes)
+	if !model.IsTrained() {
+		msgTxt += fmt.Sprintf("\n\nGive at least %d 👍 and %d 👎 on listing cards for the bot to learn what you like, %d 👍 and %d 👎 to go", preferences.MinClassFeedback, preferences.MinClassFeedback, max(preferences.MinClassFeedback-model.Likes, 0), max(preferences.MinClassFeedback-model.Dislikes, 0))
+	} else {
+		liked, disliked := make([]string, 0, preferencesShownFeatures), make([]string, 0, preferencesShownFeatures)
+		weights := model.Weights()
+		for idx := 0; idx < len(weights) && len(liked) < preferencesShownFeatures && weights[idx].Weight > 0; idx++ {
+			liked = append(liked, formatFeature(weights[idx]))
+		}
+		for idx := len(weights) - 1; idx >= 0 && len(disliked) < preferencesShownFeatures && weights[idx].Weight < 0; idx-- {
+			disliked = append(disliked, formatFeature(weights[idx]))
+		}
+		if len(liked) != 0 {
+			msgTxt += "\n\nYou seem to like: " + strings.Join(liked, ", ")
+		}
+		if len(disliked) != 0 {
+			msgTxt += "\nYou seem to dislike: " + strings.Join(disliked, ", ")
+		}
+		msgTxt += "\nListings are labeled with their 🎯match probability, /browse sorts by it"
+	}
+
+	if settings.Threshold == 0 {
+		msgTxt += "\n\n🔔Threshold: off, all new listings are notified about"
+	} else {
+		msgTxt += fmt.Sprintf("\n\n🔕Threshold: %d%%, new listings less likely to match are not notified about", settings.Threshold)
+	}
+	msgTxt += "\nChange it with /set_match_threshold or start over with /reset_preferences"
+	c.sendMessage(chatID, userID, msgTxt, false)
+}
+
+func (c *TelegramBotCommands) SetMatchThreshold(ctx context.Context, userID string, chatID int64, args string) {
+	threshold, err := strconv.Atoi(strings.TrimSuffix(strings.TrimSpace(args), "%"))
+	if err != nil || threshold < 0 || threshold > preferences.MaxThreshold {
+		msgTxt := fmt.Sprintf("⚠️Usage: /set_match_threshold <0-%d> (e.g. `/set_match_threshold 30`), new listings with a lower match probability are not notified about; 0 turns it off", preferences.MaxThreshold)
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	err = c.preferencesService.SetThreshold(ctx, userID, threshold)
+	if err != nil {
+		c.log.Error().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to set match threshold")
+		msgTxt := "💥Failed to set match threshold"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	if threshold == 0 {
+		msgTxt := "✅Match threshold was turned off, all new listings are notified about"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+	msgTxt := fmt.Sprintf("✅Match threshold was set to %d%%, it applies once the bot has learned your preferences, see /preferences", threshold)
+	c.sendMessage(chatID, userID, msgTxt, false)
+}
+
+func (c *TelegramBotCommands) ResetPreferences(ctx context.Context, userID string, chatID int64) {
+	err := c.preferencesService.ResetModel(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			msgTxt := "🤷There is no feedback to forget"
+			c.sendMessage(chatID, userID, msgTxt, false)
+			return
+		}
+		c.log.Error().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to reset preferences")
+		msgTxt := "💥Failed to reset your learned preferences"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	msgTxt := "✅All feedback was forgotten, the bot learns your preferences from scratch"
+	c.sendMessage(chatID, userID, msgTxt, false)
+}
+
+// SuppressUnlikely drops listings below the match threshold of a user from notifications and returns how many were
+// dropped, nothing is dropped until the preferences are learned. Listings matching the urgent rule, which may be nil,
+// are never dropped since the user asked for them explicitly.
+func (c *TelegramBotCommands) SuppressUnlikely(ctx context.Context, session *sessions.Session, l listings.Listings, urgentRule *urgent_rules.Rule) (listings.Listings, int) {
+	if len(l) == 0 {
+		return l, 0
+	}
+	settings, err := c.preferencesService.GetSettings(ctx, session.UserID)
+	if err != nil {
+		c.log.Error().Err(err).Str("userID", session.UserID).Msg("failed to get preference settings")
+		return l, 0
+	}
+	if settings.Threshold == 0 {
+		return l, 0
+	}
+	model := c.model(ctx, session.UserID)
+	if model == nil {
+		return l, 0
+	}
+
+	if urgentRule == nil {
+		kept, suppressed := model.Suppress(l, settings.Threshold)
+		return kept, len(suppressed)
+	}
+	urgentListings := urgentRule.Filter(l)
+	urgentObjectIDs := make(map[string]bool, len(urgentListings))
+	for _, objectID := range urgentListings.ObjectIDs() {
+		urgentObjectIDs[objectID] = true
+	}
+	otherListings := make(listings.Listings, 0, len(l))
+	for idx := range l {
+		if !urgentObjectIDs[l[idx].ObjectID] {
+			otherListings = append(otherListings, l[idx])
+		}
+	}
+	kept, suppressed := model.Suppress(otherListings, settings.Threshold)
+	return append(urgentListings, kept...), len(suppressed)
+}
+
+// SuppressedCounter renders the line of sync messages about listings left out by SuppressUnlikely.
+func SuppressedCounter(count int) string {
+	if count == 0 {
+		return ""
+	}
+	return fmt.Sprintf("\n🔕Not notified as unlikely matches: %d, see /browse", count)
+}
+
+// model is nil when preferences of the user are not learned yet or are not available.
+func (c *TelegramBotCommands) model(ctx context.Context, userID string) *preferences.Model {
+	model, err := c.preferencesService.GetModel(ctx, userID)
+	if err != nil {
+		c.log.Error().Err(err).Str("userID", userID).Msg("failed to get preference model")
+		return nil
+	}
+	if !model.IsTrained() {
+		return nil
+	}
+	return model
+}
+
+// rating labels and sorts listings by the scoring profile and by the preferences learned from feedback, either of
+// which is nil when it is not set up.
+type rating struct {
+	scorer *scoring.Scorer
+	model  *preferences.Model
+}
+
+func (c *TelegramBotCommands) rating(ctx context.Context, session *sessions.Session) rating {
+	return rating{
+		scorer: c.scorer(ctx, session),
+		model:  c.model(ctx, session.UserID),
+	}
+}
+
+// label is empty when the listing is neither scored nor rated by the learned preferences.
+func (r rating) label(listing *listings.Listing) string {
+	label := formatScore(r.scorer, listing)
+	if r.model != nil {
+		probability, _ := r.model.Probability(listing)
+		label += fmt.Sprintf("🎯%d%% ", probability)
+	}
+	return label
+}
+
+// sort sorts listings by the score when a scoring profile is set up, and by the match probability otherwise.
+func (r rating) sort(l listings.Listings) {
+	if r.scorer == nil {
+		r.sortByMatch(l)
+		return
+	}
+	sortByScore(r.scorer, l)
+}
+
+// sortByMatch sorts listings by the match probability, or by the default order when preferences are not learned.
+func (r rating) sortByMatch(l listings.Listings) {
+	if r.model == nil {
+		l.Sort()
+		return
+	}
+	r.model.Sort(l)
+}
+
+func formatFeature(feature preferences.FeatureWeight) string {
+	if word, ok := strings.CutPrefix(feature.Name, preferences.WordFeaturePrefix); ok {
+		return "💬" + word
+	}
+	labels := featureLabels[feature.Name]
+	if feature.Weight < 0 {
+		return labels[1]
+	}
+	return labels[0]
+}
@@ -0,0 +1,78 @@
+package commands
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"fundaNotifier/internal/domain/search_queries"
+	"fundaNotifier/internal/pkg/funda"
+	"strings"
+	"time"
+)
+
+func (c *TelegramBotCommands) PreviewQuery(ctx context.Context, userID string, chatID int64, args string) {
+	searchQuery := strings.TrimSpace(args)
+	if searchQuery == "" {
+		msgTxt := "⚠️Usage: /preview_query <URL> or /preview_query <query name>"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	// a stored query can be previewed by its name
+	if !strings.Contains(searchQuery, "://") {
+		storedQuery, err := c.searchQueriesService.GetSearchQueryByName(ctx, userID, searchQuery)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				msgTxt := fmt.Sprintf("🤷Search query %s does not exist", search_queries.NormalizeName(searchQuery))
+				c.sendMessage(chatID, userID, msgTxt, false)
+				return
+			}
+			c.log.Error().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to get search query")
+			msgTxt := "💥Failed to get search query"
+			c.sendMessage(chatID, userID, msgTxt, false)
+			return
+		}
+		searchQuery = storedQuery.URL
+	}
+
+	if !validateURL(searchQuery) {
+		c.log.Warn().Str("userID", userID).Int64("chatID", chatID).Msg("failed to validate URL")
+		msgTxt := "⚠️The provided URL is invalid, copy a search page URL directly from browser or use /build_query, the URL cannot target any domain other than funda.nl"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	msgTxt := "⏳Walking through search result pages, nothing will be saved..."
+	c.sendMessage(chatID, userID, msgTxt, false)
+
+	preview, err := c.listingsService.PreviewListings(ctx, searchQuery)
+	if err != nil {
+		c.log.Error().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to preview search query")
+		msgTxt = "💥Failed to preview search query"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	msgTxt = fmt.Sprintf("🔎Found %d listing(s) on %d page(s) in %s\n⏱️A sync of this query would take about %s", preview.TotalCount, preview.PagesCount, preview.PaginationDuration.Round(time.Second).String(), preview.EstimatedSyncDuration.Round(time.Second).String())
+	if preview.IsTruncated {
+		msgTxt += "\n\n" + TooBroadWarning(preview.TotalCount, preview.TrackedCount, searchQuery)
+	}
+	if len(preview.Items) > 0 {
+		msgTxt += fmt.Sprintf("\n\nFirst %d listing(s):", len(preview.Items))
+		for idx := range preview.Items {
+			msgTxt += "\n🏠" + preview.Items[idx].URL
+		}
+	}
+	c.sendMessage(chatID, userID, msgTxt, false)
+}
+
+// TooBroadWarning explains that only a part of a search query's matches is tracked and how to narrow the query down.
+func TooBroadWarning(totalCount, trackedCount int, searchQuery string) string {
+	msgTxt := fmt.Sprintf("⚠️The query is too broad: %d matches, only the first %d are tracked and removed listings are not detected. To narrow it down:", totalCount, trackedCount)
+	for _, suggestion := range funda.NarrowingSuggestions(searchQuery) {
+		msgTxt += "\n• " + suggestion
+	}
+	msgTxt += "\nEdit it via /build_query or split it via /add_query"
+	return msgTxt
+}
@@ -0,0 +1,51 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"fundaNotifier/internal/domain/search_queries"
+	"time"
+)
+
+func (c *TelegramBotCommands) QueryHistory(ctx context.Context, userID string, chatID int64, queryName string) {
+	queryName = search_queries.NormalizeName(queryName)
+	if queryName == "" {
+		queryName = search_queries.DefaultQueryName
+	}
+
+	session, err := c.sessionsService.GetSessionByUserID(ctx, userID)
+	if err != nil {
+		c.log.Error().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to get session details")
+		msgTxt := "💥Failed to get your session details"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	versions, err := c.searchQueriesService.MGetSearchQueryVersion(ctx, userID, queryName)
+	if err != nil {
+		c.log.Error().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to get search query history")
+		msgTxt := "💥Failed to get search query history"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	if len(versions) == 0 {
+		msgTxt := fmt.Sprintf("🤷Search query %s has no history, run /list_queries to see query names", queryName)
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	msgTxt := fmt.Sprintf("🕰️History of search query %s, newest first:", queryName)
+	for idx := range versions[:min(len(versions), queryHistoryVersionsToShow)] {
+		current := ""
+		if idx == 0 {
+			current = " (current)"
+		}
+		msgTxt += fmt.Sprintf("\n\n#%d%s, set at %s\n🏠Stored listings first found by it: %d\n%s", versions[idx].Number, current, versions[idx].CreatedAt.In(session.Location()).Format(time.RFC3339), versions[idx].ListingsCount, versions[idx].URL)
+	}
+	if len(versions) > queryHistoryVersionsToShow {
+		msgTxt += fmt.Sprintf("\n\n…and %d older versions", len(versions)-queryHistoryVersionsToShow)
+	}
+	msgTxt += "\n\nUse /restore_query followed by a version number to go back to it"
+	c.sendMessage(chatID, userID, msgTxt, false)
+}
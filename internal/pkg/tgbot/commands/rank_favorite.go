@@ -0,0 +1,41 @@
+package commands
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+func (c *TelegramBotCommands) RankFavorite(ctx context.Context, userID string, chatID int64, args string) {
+	fields := strings.Fields(args)
+	if len(fields) != 2 {
+		msgTxt := "⚠️Usage: /rank_favorite <ID> <position>, run /show_favorites to see listing IDs"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+	rank, err := strconv.Atoi(strings.TrimPrefix(fields[1], "#"))
+	if err != nil || rank <= 0 {
+		msgTxt := fmt.Sprintf("⚠️Invalid position %s, it must be a positive number", fields[1])
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	err = c.listingsService.SetFavoriteListingRank(ctx, userID, fields[0], rank)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			msgTxt := fmt.Sprintf("🤷Listing %s is not in your favorites", fields[0])
+			c.sendMessage(chatID, userID, msgTxt, false)
+			return
+		}
+		c.log.Error().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to rank favorite listing")
+		msgTxt := "💥Failed to rank favorite listing"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	msgTxt := "✅Favorites reordered, run /show_favorites to see them"
+	c.sendMessage(chatID, userID, msgTxt, false)
+}
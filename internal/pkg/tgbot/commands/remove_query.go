@@ -0,0 +1,34 @@
+package commands
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"fundaNotifier/internal/domain/search_queries"
+)
+
+func (c *TelegramBotCommands) RemoveQuery(ctx context.Context, userID string, chatID int64, queryName string) {
+	queryName = search_queries.NormalizeName(queryName)
+	if queryName == "" {
+		msgTxt := "⚠️Usage: /remove_query <name>, run /list_queries to see query names"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	err := c.searchQueriesService.RemoveSearchQuery(ctx, userID, queryName)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			msgTxt := fmt.Sprintf("🤷Search query %s does not exist", queryName)
+			c.sendMessage(chatID, userID, msgTxt, false)
+			return
+		}
+		c.log.Error().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to remove search query")
+		msgTxt := "💥Failed to remove search query"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	msgTxt := fmt.Sprintf("✅Search query %s and its listings were removed", queryName)
+	c.sendMessage(chatID, userID, msgTxt, false)
+}
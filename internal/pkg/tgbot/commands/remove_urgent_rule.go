@@ -0,0 +1,25 @@
+package commands
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+func (c *TelegramBotCommands) RemoveUrgentRule(ctx context.Context, userID string, chatID int64) {
+	err := c.urgentRulesService.RemoveRule(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			msgTxt := "🤷No urgent rule is set"
+			c.sendMessage(chatID, userID, msgTxt, false)
+			return
+		}
+		c.log.Error().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to remove urgent rule")
+		msgTxt := "💥Failed to remove urgent rule"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	msgTxt := "✅Urgent rule was removed"
+	c.sendMessage(chatID, userID, msgTxt, false)
+}
@@ -0,0 +1,53 @@
+package commands
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"fundaNotifier/internal/domain/search_queries"
+	"strconv"
+	"strings"
+)
+
+func (c *TelegramBotCommands) RestoreQuery(ctx context.Context, userID string, chatID int64, args string) {
+	queryName := search_queries.DefaultQueryName
+	fields := strings.Fields(args)
+	if len(fields) == 2 {
+		queryName = search_queries.NormalizeName(fields[0])
+		fields = fields[1:]
+	}
+	if len(fields) != 1 {
+		msgTxt := "⚠️Usage: /restore_query [name] <version>, run /query_history to see version numbers"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+	number, err := strconv.Atoi(strings.TrimPrefix(fields[0], "#"))
+	if err != nil || number <= 0 {
+		msgTxt := fmt.Sprintf("⚠️Invalid version number %s, run /query_history to see version numbers", fields[0])
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	restored, current, err := c.searchQueriesService.RestoreSearchQueryVersion(ctx, userID, queryName, number)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			msgTxt := fmt.Sprintf("🤷Search query %s has no version #%d", queryName, number)
+			c.sendMessage(chatID, userID, msgTxt, false)
+			return
+		}
+		c.log.Error().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to restore search query")
+		msgTxt := "💥Failed to restore search query"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	if current == nil {
+		msgTxt := fmt.Sprintf("🤷Search query %s already matches version #%d", queryName, number)
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	msgTxt := fmt.Sprintf("✅Search query %s was restored to version #%d and saved as version #%d, listings will be updated upon the next API polling run\n%s", queryName, restored.Number, current.Number, current.URL)
+	c.sendMessage(chatID, userID, msgTxt, false)
+}
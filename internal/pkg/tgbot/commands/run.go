@@ -36,9 +36,9 @@ func (c *TelegramBotCommands) Run(ctx context.Context, userID string, chatID int
 }
 
 func (c *TelegramBotCommands) searchQueryIsSet(ctx context.Context, userID string) bool {
-	_, err := c.searchQueriesService.GetSearchQuery(ctx, userID)
+	searchQueries, err := c.searchQueriesService.MGetSearchQueryByUserID(ctx, userID)
 	if err != nil {
 		return false
 	}
-	return true
+	return len(searchQueries) > 0
 }
@@ -0,0 +1,282 @@
+package commands
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"fundaNotifier/internal/domain/listings"
+	"fundaNotifier/internal/domain/scoring"
+	"fundaNotifier/internal/domain/sessions"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// scoreSummaryLimit is how many of the best scored new listings sync messages mention
+const scoreSummaryLimit = 3
+
+var (
+	criterionLabels = map[string]string{
+		scoring.CriterionPrice:         "💶price - the cheaper the better",
+		scoring.CriterionPricePerMeter: "📊price_m2 - the cheaper per m² the better",
+		scoring.CriterionRooms:         "🚪rooms - the more the better",
+		scoring.CriterionArea:          "📐area - the larger the better",
+		scoring.CriterionEnergy:        "🔋energy - the better the energy label the better",
+		scoring.CriterionDistance:      "📍distance - the closer to your points of interest the better",
+		scoring.CriterionKeywords:      "💬keywords - the more keywords in the description the better",
+	}
+	// coordinatesRegexp matches coordinates ending the arguments, e.g. "52.0907, 5.1214"
+	coordinatesRegexp = regexp.MustCompile(`(-?\d+(?:\.\d+)?)\s*,\s*(-?\d+(?:\.\d+)?)\s*$`)
+)
+
+func (c *TelegramBotCommands) ShowScoring(ctx context.Context, userID string, chatID int64) {
+	profile, err := c.scoringService.GetProfile(ctx, userID)
+	if err != nil {
+		c.log.Error().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to get scoring profile")
+		msgTxt := "💥Failed to get your scoring profile"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	msgTxt := "⭐Scoring profile"
+	if profile.IsEmpty() {
+		msgTxt += ", not set up yet: listings are not scored"
+	}
+	msgTxt += "\n\nWeights:"
+	for _, criterion := range scoring.Criteria {
+		msgTxt += fmt.Sprintf("\n%d/%d %s", profile.Weights[criterion], scoring.MaxWeight, criterionLabels[criterion])
+	}
+
+	msgTxt += "\n\nKeywords: "
+	if len(profile.Keywords) == 0 {
+		msgTxt += "none"
+	} else {
+		msgTxt += strings.Join(profile.Keywords, ", ")
+	}
+
+	msgTxt += "\nPoints of interest:"
+	if len(profile.Points) == 0 {
+		msgTxt += " none"
+	}
+	for idx := range profile.Points {
+		msgTxt += fmt.Sprintf("\n📍%s %s, %s", profile.Points[idx].Name, strconv.FormatFloat(profile.Points[idx].Latitude, 'f', -1, 64), strconv.FormatFloat(profile.Points[idx].Longitude, 'f', -1, 64))
+	}
+
+	msgTxt += fmt.Sprintf("\n\nListings are scored from 0 to %d against your other listings, criteria a listing lacks data for are left out.", scoring.MaxScore)
+	msgTxt += "\nChange it with /set_weight, /set_keywords, /add_poi, /remove_poi or start over with /reset_scoring"
+	c.sendMessage(chatID, userID, msgTxt, false)
+}
+
+func (c *TelegramBotCommands) SetWeight(ctx context.Context, userID string, chatID int64, args string) {
+	fields := strings.Fields(strings.ToLower(args))
+	usage := fmt.Sprintf("⚠️Usage: /set_weight <criterion> <0-%d> (e.g. `/set_weight price 5`), 0 excludes the criterion; criteria: %s", scoring.MaxWeight, strings.Join(scoring.Criteria, ", "))
+	if len(fields) != 2 {
+		c.sendMessage(chatID, userID, usage, false)
+		return
+	}
+	weight, err := strconv.Atoi(fields[1])
+	if err != nil {
+		c.sendMessage(chatID, userID, usage, false)
+		return
+	}
+
+	profile, err := c.scoringService.SetWeight(ctx, userID, fields[0], weight)
+	if err != nil {
+		if errors.Is(err, scoring.ErrUnknownCriterion) || errors.Is(err, scoring.ErrInvalidWeight) {
+			c.sendMessage(chatID, userID, usage, false)
+			return
+		}
+		c.log.Error().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to set weight")
+		msgTxt := "💥Failed to set weight"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	msgTxt := fmt.Sprintf("✅Weight of %s was set to %d", fields[0], weight)
+	if fields[0] == scoring.CriterionDistance && len(profile.Points) == 0 {
+		msgTxt += ", add a point of interest with /add_poi for it to apply"
+	}
+	if fields[0] == scoring.CriterionKeywords && len(profile.Keywords) == 0 {
+		msgTxt += ", set keywords with /set_keywords for it to apply"
+	}
+	c.sendMessage(chatID, userID, msgTxt, false)
+}
+
+func (c *TelegramBotCommands) SetScoringKeywords(ctx context.Context, userID string, chatID int64, args string) {
+	args = strings.TrimSpace(args)
+	if args == "" {
+		msgTxt := "⚠️Usage: /set_keywords <keyword>, <keyword> (e.g. `/set_keywords balkon, tuin, dakterras`), `/set_keywords -` clears them"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+	var keywords []string
+	if args != "-" {
+		keywords = strings.Split(args, ",")
+	}
+
+	profile, err := c.scoringService.SetKeywords(ctx, userID, keywords)
+	if err != nil {
+		c.log.Error().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to set keywords")
+		msgTxt := "💥Failed to set keywords"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	if len(profile.Keywords) == 0 {
+		msgTxt := "✅Keywords were cleared"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+	msgTxt := fmt.Sprintf("✅Keywords were set to %s", strings.Join(profile.Keywords, ", "))
+	if profile.Weights[scoring.CriterionKeywords] == 0 {
+		msgTxt += ", give them a weight with /set_weight keywords <1-10> for them to apply"
+	}
+	c.sendMessage(chatID, userID, msgTxt, false)
+}
+
+func (c *TelegramBotCommands) AddPointOfInterest(ctx context.Context, userID string, chatID int64, args string) {
+	usage := "⚠️Usage: /add_poi <name> <latitude>,<longitude> (e.g. `/add_poi work 52.0907,5.1214`), a point with the same name is moved"
+	matches := coordinatesRegexp.FindStringSubmatch(args)
+	if matches == nil {
+		c.sendMessage(chatID, userID, usage, false)
+		return
+	}
+	name := strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(args), strings.TrimSpace(matches[0])))
+	latitude, errLat := strconv.ParseFloat(matches[1], 64)
+	longitude, errLon := strconv.ParseFloat(matches[2], 64)
+	if name == "" || errLat != nil || errLon != nil || latitude < -90 || latitude > 90 || longitude < -180 || longitude > 180 {
+		c.sendMessage(chatID, userID, usage, false)
+		return
+	}
+
+	profile, err := c.scoringService.SetPoint(ctx, userID, scoring.PointOfInterest{Name: name, Latitude: latitude, Longitude: longitude})
+	if err != nil {
+		if errors.Is(err, scoring.ErrInvalidName) {
+			msgTxt := "⚠️Point of interest name must not contain : or ;"
+			c.sendMessage(chatID, userID, msgTxt, false)
+			return
+		}
+		c.log.Error().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to add point of interest")
+		msgTxt := "💥Failed to add point of interest"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	msgTxt := fmt.Sprintf("✅Point of interest %s was added", name)
+	if profile.Weights[scoring.CriterionDistance] == 0 {
+		msgTxt += ", give distance a weight with /set_weight distance <1-10> for it to apply"
+	}
+	c.sendMessage(chatID, userID, msgTxt, false)
+}
+
+func (c *TelegramBotCommands) RemovePointOfInterest(ctx context.Context, userID string, chatID int64, args string) {
+	name := strings.TrimSpace(args)
+	if name == "" {
+		msgTxt := "⚠️Usage: /remove_poi <name> (e.g. `/remove_poi work`), see /show_scoring for your points of interest"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	_, err := c.scoringService.RemovePoint(ctx, userID, name)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			msgTxt := fmt.Sprintf("🤷There is no point of interest named %s", name)
+			c.sendMessage(chatID, userID, msgTxt, false)
+			return
+		}
+		c.log.Error().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to remove point of interest")
+		msgTxt := "💥Failed to remove point of interest"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	msgTxt := fmt.Sprintf("✅Point of interest %s was removed", name)
+	c.sendMessage(chatID, userID, msgTxt, false)
+}
+
+func (c *TelegramBotCommands) ResetScoring(ctx context.Context, userID string, chatID int64) {
+	err := c.scoringService.ResetProfile(ctx, userID)
+	if err != nil {
+		c.log.Error().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to reset scoring profile")
+		msgTxt := "💥Failed to reset your scoring profile"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	msgTxt := "✅Scoring profile was reset, listings are not scored anymore"
+	c.sendMessage(chatID, userID, msgTxt, false)
+}
+
+// scorer scores listings of a user against every stored listing passing the active filters, so a listing gets the
+// same score wherever it is shown. It is nil when the user has no scoring profile or it is not available.
+func (c *TelegramBotCommands) scorer(ctx context.Context, session *sessions.Session) *scoring.Scorer {
+	profile, err := c.scoringService.GetProfile(ctx, session.UserID)
+	if err != nil {
+		c.log.Error().Err(err).Str("userID", session.UserID).Msg("failed to get scoring profile")
+		return nil
+	}
+	if profile.IsEmpty() {
+		return nil
+	}
+
+	pool, err := c.listingsService.MGetListingByUserID(ctx, session.UserID, false)
+	if err != nil {
+		c.log.Error().Err(err).Str("userID", session.UserID).Msg("failed to get listings to score against")
+		return nil
+	}
+	pool, err = c.filterListings(ctx, session, pool)
+	if err != nil {
+		return nil
+	}
+	return profile.NewScorer(pool)
+}
+
+// sortByScore sorts listings by the score, or by the default order when they are not scored.
+func sortByScore(scorer *scoring.Scorer, l listings.Listings) {
+	if scorer == nil {
+		l.Sort()
+		return
+	}
+	scorer.Sort(l)
+}
+
+// formatScore renders the score label of a listing, it is empty when the listing is not scored.
+func formatScore(scorer *scoring.Scorer, listing *listings.Listing) string {
+	if scorer == nil {
+		return ""
+	}
+	score, ok := scorer.Score(listing)
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf("⭐%d ", score)
+}
+
+// ScoreSummary renders the best scored of filtered added listings for sync messages, it is left out when nothing is
+// scored.
+func (c *TelegramBotCommands) ScoreSummary(ctx context.Context, session *sessions.Session, added listings.Listings) string {
+	if len(added) == 0 {
+		return ""
+	}
+	scorer := c.scorer(ctx, session)
+	if scorer == nil {
+		return ""
+	}
+
+	best := slices.Clone(added)
+	scorer.Sort(best)
+	var msgTxt string
+	for idx := 0; idx < len(best) && idx < scoreSummaryLimit; idx++ {
+		score := formatScore(scorer, &best[idx])
+		if score == "" {
+			break
+		}
+		msgTxt += fmt.Sprintf("\n%s%s, %s", score, best[idx].Name, best[idx].Address.AddressLocality)
+	}
+	if msgTxt == "" {
+		return ""
+	}
+	return "\n⭐Best new by your score:" + msgTxt
+}
@@ -0,0 +1,88 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"fundaNotifier/internal/domain/listings"
+	"strings"
+	"unicode/utf8"
+)
+
+const searchResultsLimit = 10
+
+func (c *TelegramBotCommands) Search(ctx context.Context, userID string, chatID int64, args string) {
+	if listings.MatchQuery(args) == "" {
+		msgTxt := "⚠️Usage: /search <terms> (e.g. `/search balkon centrum`), listings matching all terms by name, description or address are shown"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	session, err := c.sessionsService.GetSessionByUserID(ctx, userID)
+	if err != nil {
+		c.log.Error().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to get session details")
+		msgTxt := "💥Failed to get your session details"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	matches, err := c.listingsService.SearchListings(ctx, userID, args)
+	if err != nil {
+		c.log.Error().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to search listings")
+		msgTxt := "💥Failed to search listings"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	// filtering does not keep the order, so matches which pass it are taken in the order of relevance, once per listing
+	passed := make(map[string]bool)
+	filteredListings, err := c.filterListings(ctx, session, matches.Listings())
+	if err != nil {
+		msgTxt := "💥Failed to get your blacklist"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+	for idx := range filteredListings {
+		passed[filteredListings[idx].ObjectID] = true
+	}
+	filteredMatches := make(listings.Matches, 0, len(filteredListings))
+	for idx := range matches {
+		if passed[matches[idx].Listing.ObjectID] {
+			filteredMatches = append(filteredMatches, matches[idx])
+			delete(passed, matches[idx].Listing.ObjectID)
+		}
+	}
+
+	if len(filteredMatches) == 0 {
+		msgTxt := fmt.Sprintf("🤷No stored listings match %s", strings.Join(strings.Fields(args), " "))
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	msgTxt := fmt.Sprintf("🔎%d listing\\(s\\) match", len(filteredMatches))
+	if len(filteredMatches) > searchResultsLimit {
+		msgTxt += fmt.Sprintf(", showing the best %d", searchResultsLimit)
+		filteredMatches = filteredMatches[:searchResultsLimit]
+	}
+	msgTxt += "\n"
+	for idx := range filteredMatches {
+		addMsgTxt := "\n" + formatMatch(&filteredMatches[idx])
+		if utf8.RuneCountInString(msgTxt+addMsgTxt) > messageMaxCharLen {
+			c.sendMessage(chatID, userID, msgTxt, true)
+			msgTxt = ""
+		}
+		msgTxt += addMsgTxt
+	}
+	c.sendMessage(chatID, userID, msgTxt, true)
+}
+
+// formatMatch renders a match as MarkdownV2 with matched terms of the snippet in bold.
+func formatMatch(match *listings.Match) string {
+	listing := &match.Listing
+	msgTxt := fmt.Sprintf("🏠[%.0f %s %s](%s)\n%s, %s, %s\n🆔%s\n", listing.Offers.Price, listing.Offers.PriceCurrency, escapeMarkdownV2(listing.Name), escapeMarkdownV2(listing.URL), escapeMarkdownV2(listing.Address.AddressRegion), escapeMarkdownV2(listing.Address.AddressLocality), escapeMarkdownV2(listing.Address.StreetAddress), escapeMarkdownV2(listing.ObjectID))
+	snippet := strings.Join(strings.Fields(match.Snippet), " ")
+	if snippet == "" {
+		return msgTxt
+	}
+	replacer := strings.NewReplacer(listings.SnippetOpen, "*", listings.SnippetClose, "*")
+	return msgTxt + "💬" + replacer.Replace(escapeMarkdownV2(snippet)) + "\n"
+}
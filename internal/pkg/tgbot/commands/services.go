@@ -2,15 +2,37 @@ package commands
 
 import (
 	"context"
+	"fundaNotifier/internal/domain/blacklist"
+	"fundaNotifier/internal/domain/dnd_windows"
 	"fundaNotifier/internal/domain/listings"
+	"fundaNotifier/internal/domain/preferences"
+	"fundaNotifier/internal/domain/scoring"
+	"fundaNotifier/internal/domain/search_queries"
 	"fundaNotifier/internal/domain/sessions"
+	"fundaNotifier/internal/domain/urgent_rules"
+	"fundaNotifier/internal/domain/viewings"
+	"fundaNotifier/internal/domain/watched_listings"
 	"time"
 )
 
 type ListingsService interface {
 	MGetListingByUserID(ctx context.Context, userID string, showOnlyNew bool) (listings.Listings, error)
-	UpdateAndCompareListings(ctx context.Context, userID, searchQuery string) (addedListings, removedListings, leftoverListings listings.Listings, err error)
+	UpdateAndCompareListings(ctx context.Context, userID, queryName string, queryVersion int, searchQuery string) (*listings.Update, error)
+	GetListingByUUID(ctx context.Context, UUID string) (*listings.Listing, error)
+	GetListingByObjectID(ctx context.Context, userID, objectID string) (*listings.Listing, error)
+	SearchListings(ctx context.Context, userID, terms string) (listings.Matches, error)
+	MarkListingsSeen(ctx context.Context, userID string, objectIDs []string) error
+	MGetSeenObjectID(ctx context.Context, userID string) ([]string, error)
 	MGetFavoriteListingByUserID(ctx context.Context, userID string) (listings.Listings, error)
+	GetFavoriteListing(ctx context.Context, userID, objectID string) (*listings.Listing, error)
+	RemoveFavoriteListing(ctx context.Context, userID, objectID string) error
+	SetFavoriteListingNote(ctx context.Context, userID, objectID, note string) error
+	SetFavoriteListingRank(ctx context.Context, userID, objectID string, rank int) error
+	SetFavoriteListingStage(ctx context.Context, userID, objectID, stage string) (*listings.Listing, error)
+	MGetStageEvent(ctx context.Context, userID, objectID string) (listings.StageEvents, error)
+	CheckFavoriteListings(ctx context.Context, userID string) (listings.Changes, error)
+	MGetPricePoint(ctx context.Context, userID, objectID string) (listings.PricePoints, error)
+	PreviewListings(ctx context.Context, searchQuery string) (*listings.Preview, error)
 }
 type SessionsService interface {
 	CreateDefaultSession(ctx context.Context, userID string, chatID int64) error
@@ -18,17 +40,73 @@ type SessionsService interface {
 	ActivateSession(ctx context.Context, userID string) error
 	DeactivateSession(ctx context.Context, userID string) error
 	UpdatePollingInterval(ctx context.Context, userID string, pollingIntervalSeconds int) error
+	UpdateSchedule(ctx context.Context, userID string, schedule string) error
+	UpdateTimezone(ctx context.Context, userID string, timezone string) error
 	UpdateRegions(ctx context.Context, userID string, regions string) error
 	AddRegion(ctx context.Context, userID string, region string) error
 	UpdateCities(ctx context.Context, userID string, cities string) error
 	AddCity(ctx context.Context, userID string, city string) error
 	RemoveEverythingByUserID(ctx context.Context, userID string) error
 	UpdateLastSyncedAt(ctx context.Context, userID string, lastSyncedAt time.Time) error
-	SetDNDSchedule(ctx context.Context, userID string, DNDStart, DNDEnd int) error
 	ActivateDND(ctx context.Context, userID string) error
 	DeactivateDND(ctx context.Context, userID string) error
+	UpdateDNDQueue(ctx context.Context, userID string, queue bool) error
 }
 type SearchQueriesService interface {
-	GetSearchQuery(ctx context.Context, userID string) (URL string, err error)
-	UpsertSearchQueryByUserID(ctx context.Context, userID, searchQuery string) error
+	UpsertSearchQuery(ctx context.Context, userID, queryName, URL string) error
+	GetSearchQueryByName(ctx context.Context, userID, queryName string) (*search_queries.SearchQuery, error)
+	MGetSearchQueryByUserID(ctx context.Context, userID string) (search_queries.SearchQueries, error)
+	SetSearchQueryActive(ctx context.Context, userID, queryName string, isActive bool) error
+	UpdateSearchQueryFilters(ctx context.Context, userID, queryName, regions, cities string) error
+	RemoveSearchQuery(ctx context.Context, userID, queryName string) error
+	MGetSearchQueryVersion(ctx context.Context, userID, queryName string) (search_queries.Versions, error)
+	RestoreSearchQueryVersion(ctx context.Context, userID, queryName string, number int) (restored, current *search_queries.Version, err error)
+}
+type DNDWindowsService interface {
+	AddWindow(ctx context.Context, userID, name string, weekdays []time.Weekday, start, end int) error
+	MGetWindowByUserID(ctx context.Context, userID string) (dnd_windows.Windows, error)
+	RemoveWindow(ctx context.Context, userID, name string) error
+}
+type UrgentRulesService interface {
+	SetRule(ctx context.Context, userID, regions, cities string, maxPrice float64, dailyCap int) error
+	GetRuleByUserID(ctx context.Context, userID string) (*urgent_rules.Rule, error)
+	RemoveRule(ctx context.Context, userID string) error
+}
+type WatchedListingsService interface {
+	Watch(ctx context.Context, userID, URL string) (*watched_listings.WatchedListing, error)
+	Unwatch(ctx context.Context, userID, listing string) error
+	MGetWatchedListingByUserID(ctx context.Context, userID string) (watched_listings.WatchedListings, error)
+	CheckWatchedListings(ctx context.Context, userID string) (listings.Changes, error)
+}
+type BlacklistService interface {
+	Hide(ctx context.Context, userID string, listing *listings.Listing) error
+	Unhide(ctx context.Context, userID, objectID string) error
+	MGetHiddenListingByUserID(ctx context.Context, userID string) (blacklist.HiddenListings, error)
+	AddRule(ctx context.Context, userID, kind, value string) (*blacklist.Rule, error)
+	RemoveRule(ctx context.Context, userID, kind, value string) error
+	MGetRuleByUserID(ctx context.Context, userID string) (blacklist.Rules, error)
+	GetFilter(ctx context.Context, userID string) (*blacklist.Filter, error)
+}
+
+type ViewingsService interface {
+	Schedule(ctx context.Context, favorite *listings.Listing, startsAt time.Time, remindBefore time.Duration) (*viewings.Viewing, error)
+	Cancel(ctx context.Context, userID, objectID string) error
+	MGetUpcomingViewing(ctx context.Context, userID string) (viewings.Viewings, error)
+}
+
+type ScoringService interface {
+	GetProfile(ctx context.Context, userID string) (*scoring.Profile, error)
+	SetWeight(ctx context.Context, userID, criterion string, weight int) (*scoring.Profile, error)
+	SetKeywords(ctx context.Context, userID string, keywords []string) (*scoring.Profile, error)
+	SetPoint(ctx context.Context, userID string, point scoring.PointOfInterest) (*scoring.Profile, error)
+	RemovePoint(ctx context.Context, userID, name string) (*scoring.Profile, error)
+	ResetProfile(ctx context.Context, userID string) error
+}
+
+type PreferencesService interface {
+	SetFeedback(ctx context.Context, userID string, listing *listings.Listing, liked bool) error
+	GetModel(ctx context.Context, userID string) (*preferences.Model, error)
+	GetSettings(ctx context.Context, userID string) (*preferences.Settings, error)
+	SetThreshold(ctx context.Context, userID string, threshold int) error
+	ResetModel(ctx context.Context, userID string) error
 }
@@ -0,0 +1,62 @@
+package commands
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"fundaNotifier/internal/domain/search_queries"
+	"strings"
+)
+
+const queryFiltersUsage = "⚠️Usage: /set_query_filters <name> regions=utrecht; cities=utrecht,amersfoort (invoke with a name only to reset)"
+
+func (c *TelegramBotCommands) SetQueryFilters(ctx context.Context, userID string, chatID int64, args string) {
+	nameAndFilters := strings.SplitN(strings.TrimSpace(args), " ", 2)
+	queryName := search_queries.NormalizeName(nameAndFilters[0])
+	if queryName == "" {
+		c.sendMessage(chatID, userID, queryFiltersUsage, false)
+		return
+	}
+
+	var regions, cities string
+	if len(nameAndFilters) == 2 {
+		for _, part := range strings.Split(nameAndFilters[1], ";") {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+			keyValue := strings.SplitN(part, "=", 2)
+			if len(keyValue) != 2 {
+				c.sendMessage(chatID, userID, queryFiltersUsage, false)
+				return
+			}
+			switch strings.ToLower(strings.TrimSpace(keyValue[0])) {
+			case "regions":
+				regions = keyValue[1]
+			case "cities":
+				cities = keyValue[1]
+			default:
+				c.sendMessage(chatID, userID, queryFiltersUsage, false)
+				return
+			}
+		}
+	}
+
+	err := c.searchQueriesService.UpdateSearchQueryFilters(ctx, userID, queryName, regions, cities)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			msgTxt := fmt.Sprintf("🤷Search query %s does not exist", queryName)
+			c.sendMessage(chatID, userID, msgTxt, false)
+			return
+		}
+		c.log.Error().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to update search query filters")
+		msgTxt := "💥Failed to update search query filters"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	msgTxt := fmt.Sprintf("✅Filters of search query %s were updated", queryName)
+	c.sendMessage(chatID, userID, msgTxt, false)
+	c.ListQueries(ctx, userID, chatID)
+}
@@ -0,0 +1,67 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"fundaNotifier/internal/pkg/cron"
+	"strings"
+	"time"
+)
+
+func (c *TelegramBotCommands) SetSchedule(ctx context.Context, userID string, chatID int64, expression string) {
+	expression = strings.TrimSpace(expression)
+	if expression == "" {
+		if err := c.sessionsService.UpdateSchedule(ctx, userID, ""); err != nil {
+			c.log.Error().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to reset schedule")
+			msgTxt := "💥Failed to reset schedule"
+			c.sendMessage(chatID, userID, msgTxt, false)
+			return
+		}
+		msgTxt := "✅Schedule was reset, API polling will follow the polling interval"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	schedule, err := cron.Parse(expression)
+	if err != nil {
+		c.log.Warn().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("invalid schedule expression")
+		msgTxt := fmt.Sprintf("⚠️Invalid schedule: %s\nUse a cron expression (e.g. `*/20 7-23 * * 1-5`) or one of the presets: %s", err.Error(), strings.Join(cron.PresetNames(), ", "))
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	session, err := c.sessionsService.GetSessionByUserID(ctx, userID)
+	if err != nil {
+		c.log.Error().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to get session details")
+		msgTxt := "💥Failed to get your session details"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	nowTs := time.Now().In(session.Location())
+	nextRuns := schedule.NextN(nowTs, scheduleRunsToShow)
+	if len(nextRuns) == 0 {
+		msgTxt := "⚠️The schedule never fires, please check the expression"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	if minGap := schedule.MinGap(nowTs, scheduleRunsToValidate); minGap > 0 && minGap.Seconds() < minPollingIntervalSeconds {
+		msgTxt := fmt.Sprintf("⚠️Scheduled runs cannot be closer than %d seconds to each other, the schedule fires every %s", minPollingIntervalSeconds, minGap.String())
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	if err = c.sessionsService.UpdateSchedule(ctx, userID, schedule.Expression); err != nil {
+		c.log.Error().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to update schedule")
+		msgTxt := "💥Failed to update schedule"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	msgTxt := fmt.Sprintf("✅Schedule was set to: %s\n⏭️Next runs (%s):", schedule.Expression, session.Location().String())
+	for idx := range nextRuns {
+		msgTxt += "\n\t" + nextRuns[idx].Format(scheduleLayout)
+	}
+	c.sendMessage(chatID, userID, msgTxt, false)
+}
@@ -2,42 +2,32 @@ package commands
 
 import (
 	"context"
-	"net/url"
-	"strings"
+	"fmt"
+	"fundaNotifier/internal/domain/search_queries"
+	"fundaNotifier/internal/pkg/funda"
 )
 
 func (c *TelegramBotCommands) SetSearchQuery(ctx context.Context, userID string, chatID int64, searchQuery string) {
 	if !validateURL(searchQuery) {
 		c.log.Warn().Str("userID", userID).Int64("chatID", chatID).Msg("failed to validate URL")
-		msgTxt := "⚠️The provided URL is invalid, copy URL directly from browser, the URL cannot target any domain other than funda.nl"
+		msgTxt := "⚠️The provided URL is invalid, copy a search page URL directly from browser or use /build_query, the URL cannot target any domain other than funda.nl"
 		c.sendMessage(chatID, userID, msgTxt, false)
 		return
 	}
 
-	if err := c.searchQueriesService.UpsertSearchQueryByUserID(ctx, userID, searchQuery); err != nil {
+	if err := c.searchQueriesService.UpsertSearchQuery(ctx, userID, search_queries.DefaultQueryName, searchQuery); err != nil {
 		c.log.Error().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to update search query")
 		msgTxt := "💥Failed to update search query"
 		c.sendMessage(chatID, userID, msgTxt, false)
 		return
 	}
 
-	msgTxt := "✅New search query was set"
+	msgTxt := fmt.Sprintf("✅New search query was set as %s, use /add_query to track more queries", search_queries.DefaultQueryName)
 	c.sendMessage(chatID, userID, msgTxt, false)
 }
 
+// validateURL accepts only Funda search pages which can be parsed into structured parameters.
 func validateURL(str string) bool {
-	parsedURL, err := url.Parse(str)
-	if err != nil {
-		return false
-	}
-
-	if parsedURL.Scheme != "http" && parsedURL.Scheme != "https" {
-		return false
-	}
-
-	if !strings.HasSuffix(parsedURL.Hostname(), "funda.nl") {
-		return false
-	}
-
-	return true
+	_, err := funda.Parse(str)
+	return err == nil
 }
@@ -0,0 +1,33 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+func (c *TelegramBotCommands) SetTimezone(ctx context.Context, userID string, chatID int64, timezone string) {
+	timezone = strings.TrimSpace(timezone)
+	if timezone == "" {
+		timezone = defaultTimezone
+	}
+
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		c.log.Warn().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("invalid timezone")
+		msgTxt := "⚠️Invalid timezone, use an IANA timezone name (e.g. `Europe/Amsterdam`)"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	if err = c.sessionsService.UpdateTimezone(ctx, userID, loc.String()); err != nil {
+		c.log.Error().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to update timezone")
+		msgTxt := "💥Failed to update timezone"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	msgTxt := fmt.Sprintf("✅Timezone was set to %s, your local time is %s", loc.String(), time.Now().In(loc).Format(DNDLayout))
+	c.sendMessage(chatID, userID, msgTxt, false)
+}
@@ -0,0 +1,73 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const urgentRuleUsage = "⚠️Usage: /set_urgent_rule max_price=1400; cities=utrecht,amersfoort; regions=utrecht; cap=3\nAll parts are optional, but at least one filter must be set; cap limits urgent alerts per day"
+
+func (c *TelegramBotCommands) SetUrgentRule(ctx context.Context, userID string, chatID int64, args string) {
+	var (
+		regions, cities string
+		maxPrice        float64
+		dailyCap        = defaultUrgentDailyCap
+		err             error
+	)
+
+	for _, part := range strings.Split(args, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		keyValue := strings.SplitN(part, "=", 2)
+		if len(keyValue) != 2 {
+			c.sendMessage(chatID, userID, urgentRuleUsage, false)
+			return
+		}
+		value := strings.TrimSpace(keyValue[1])
+		switch strings.ToLower(strings.TrimSpace(keyValue[0])) {
+		case "max_price", "price":
+			maxPrice, err = strconv.ParseFloat(value, 64)
+			if err != nil || maxPrice <= 0 {
+				msgTxt := "⚠️Invalid max_price, expected a positive number"
+				c.sendMessage(chatID, userID, msgTxt, false)
+				return
+			}
+		case "cities":
+			cities = value
+		case "regions":
+			regions = value
+		case "cap":
+			dailyCap, err = strconv.Atoi(value)
+			if err != nil || dailyCap <= 0 || dailyCap > maxUrgentDailyCap {
+				msgTxt := fmt.Sprintf("⚠️Invalid cap, expected a number between 1 and %d", maxUrgentDailyCap)
+				c.sendMessage(chatID, userID, msgTxt, false)
+				return
+			}
+		default:
+			c.sendMessage(chatID, userID, urgentRuleUsage, false)
+			return
+		}
+	}
+
+	// a rule without filters would turn every new listing into an urgent one
+	if maxPrice == 0 && strings.TrimSpace(regions) == "" && strings.TrimSpace(cities) == "" {
+		c.sendMessage(chatID, userID, urgentRuleUsage, false)
+		return
+	}
+
+	err = c.urgentRulesService.SetRule(ctx, userID, regions, cities, maxPrice, dailyCap)
+	if err != nil {
+		c.log.Error().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to set urgent rule")
+		msgTxt := "💥Failed to set urgent rule"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	msgTxt := "✅Urgent rule was set, matching listings will be sent right away, even within DND windows"
+	c.sendMessage(chatID, userID, msgTxt, false)
+	c.ShowUrgentRule(ctx, userID, chatID)
+}
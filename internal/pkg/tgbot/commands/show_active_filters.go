@@ -2,6 +2,9 @@ package commands
 
 import (
 	"context"
+	"fmt"
+	"fundaNotifier/internal/domain/listings"
+	"fundaNotifier/internal/domain/sessions"
 	"strings"
 )
 
@@ -28,3 +31,24 @@ func (c *TelegramBotCommands) ShowActiveFilters(ctx context.Context, userID stri
 		"\n📍Active cities: " + msgCities
 	c.sendMessage(chatID, userID, msgTxt, false)
 }
+
+// filterListings applies per-query filters followed by bot-level filters, drops duplicates across queries and
+// excludes hidden and blacklisted listings. It fails when the blacklist is not available rather than showing hidden
+// listings again.
+func (c *TelegramBotCommands) filterListings(ctx context.Context, session *sessions.Session, l listings.Listings) (listings.Listings, error) {
+	searchQueries, err := c.searchQueriesService.MGetSearchQueryByUserID(ctx, session.UserID)
+	if err != nil {
+		c.log.Error().Err(err).Str("userID", session.UserID).Msg("failed to get search queries for filtering")
+		l = l.UniqueByObjectID()
+	} else {
+		l = searchQueries.FilterListings(l)
+	}
+	l = l.FilterByRegionsAndCities(session.Regions, session.Cities)
+
+	filter, err := c.blacklistService.GetFilter(ctx, session.UserID)
+	if err != nil {
+		c.log.Error().Err(err).Str("userID", session.UserID).Msg("failed to get blacklist for filtering")
+		return nil, fmt.Errorf("failed to get blacklist for filtering: %w", err)
+	}
+	return filter.Apply(l), nil
+}
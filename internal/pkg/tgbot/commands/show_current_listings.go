@@ -24,12 +24,18 @@ func (c *TelegramBotCommands) ShowCurrentListings(ctx context.Context, userID st
 		c.sendMessage(chatID, userID, msgTxt, false)
 		return
 	}
-	allListings = allListings.FilterByRegionsAndCities(session.Regions, session.Cities)
-	allListings.Sort()
+	allListings, err = c.filterListings(ctx, session, allListings)
+	if err != nil {
+		msgTxt := "💥Failed to get your blacklist"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+	rating := c.rating(ctx, session)
+	rating.sort(allListings)
 
 	var msgTxt string
 	for idx := range allListings {
-		addMsgTxt := fmt.Sprintf(fmt.Sprintf("🏠[%.0f %s %s](%s)\n%s, %s, %s\n%s\n", allListings[idx].Offers.Price, allListings[idx].Offers.PriceCurrency, escapeMarkdownV2(allListings[idx].Name), escapeMarkdownV2(allListings[idx].URL), escapeMarkdownV2(allListings[idx].Address.AddressRegion), escapeMarkdownV2(allListings[idx].Address.AddressLocality), escapeMarkdownV2(allListings[idx].Address.StreetAddress), escapeMarkdownV2(allListings[idx].CreatedAt.Format(time.RFC850))))
+		addMsgTxt := fmt.Sprintf("%s🏠[%.0f %s %s](%s)\n%s, %s, %s\n%s\n", rating.label(&allListings[idx]), allListings[idx].Offers.Price, allListings[idx].Offers.PriceCurrency, escapeMarkdownV2(allListings[idx].Name), escapeMarkdownV2(allListings[idx].URL), escapeMarkdownV2(allListings[idx].Address.AddressRegion), escapeMarkdownV2(allListings[idx].Address.AddressLocality), escapeMarkdownV2(allListings[idx].Address.StreetAddress), escapeMarkdownV2(allListings[idx].CreatedAt.In(session.Location()).Format(time.RFC850)))
 		if utf8.RuneCountInString(msgTxt+addMsgTxt) > messageMaxCharLen {
 			c.sendMessage(chatID, userID, msgTxt, true)
 			msgTxt = ""
@@ -43,6 +49,7 @@ func (c *TelegramBotCommands) ShowCurrentListings(ctx context.Context, userID st
 	}
 
 	c.sendMessage(chatID, userID, msgTxt, true)
+	c.markSeen(ctx, userID, allListings.ObjectIDs())
 }
 
 func escapeMarkdownV2(text string) string {
@@ -2,10 +2,32 @@ package commands
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
+	"fundaNotifier/internal/domain/listings"
+	"fundaNotifier/internal/pkg/funda"
+	"fundaNotifier/internal/pkg/tgbot/callback"
+	"strconv"
+	"strings"
+	"unicode/utf16"
 	"unicode/utf8"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	favoritesPageSize  = 5
+	favoriteNoteMaxLen = 500
+	favoriteNoteClear  = "-"
 )
 
+// changeLabels are the emoji and the name of each kind of tracked listings in change notifications
+var changeLabels = map[string][2]string{
+	listings.KindFavorite: {"⭐", "Favorite listing"},
+	listings.KindWatched:  {"👀", "Watched listing"},
+}
+
 func (c *TelegramBotCommands) ShowFavorites(ctx context.Context, userID string, chatID int64) {
 	favorites, err := c.listingsService.MGetFavoriteListingByUserID(ctx, userID)
 	if err != nil {
@@ -14,22 +36,278 @@ func (c *TelegramBotCommands) ShowFavorites(ctx context.Context, userID string,
 		c.sendMessage(chatID, userID, msgTxt, false)
 		return
 	}
-	favorites.Sort()
+	favorites.SortByRank()
 
-	var msgTxt string
-	for idx := range favorites {
-		addMsgTxt := fmt.Sprintf(fmt.Sprintf("🏠[%.0f %s %s](%s)\n%s, %s, %s\n", favorites[idx].Offers.Price, favorites[idx].Offers.PriceCurrency, escapeMarkdownV2(favorites[idx].Name), escapeMarkdownV2(favorites[idx].URL), escapeMarkdownV2(favorites[idx].Address.AddressRegion), escapeMarkdownV2(favorites[idx].Address.AddressLocality), escapeMarkdownV2(favorites[idx].Address.StreetAddress)))
+	watchedListings, err := c.watchedListingsService.MGetWatchedListingByUserID(ctx, userID)
+	if err != nil {
+		c.log.Error().Err(err).Msg("failed to get watched listings")
+		msgTxt := "💥Failed to get watched listings"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	if len(favorites) == 0 && len(watchedListings) == 0 {
+		msgTxt := "🤷Nothing to show, you need to add a favorite or to /watch a listing first"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+	if len(favorites) != 0 {
+		msgTxt, keyboard := renderFavoritesPage(favorites, 0)
+		c.sendMessageWithKeyboard(chatID, userID, msgTxt, keyboard, false)
+	}
+
+	if len(watchedListings) == 0 {
+		return
+	}
+	msgTxt := "👀Watched listings, use /unwatch followed by an ID to stop watching:"
+	for idx := range watchedListings {
+		addMsgTxt := "\n\n" + formatWatchedListing(&watchedListings[idx])
 		if utf8.RuneCountInString(msgTxt+addMsgTxt) > messageMaxCharLen {
-			c.sendMessage(chatID, userID, msgTxt, true)
+			c.sendMessage(chatID, userID, msgTxt, false)
 			msgTxt = ""
 		}
 		msgTxt += addMsgTxt
 	}
-	if msgTxt == "" {
-		msgTxt = "🤷Nothing to show, you need to add a favorite first"
+	c.sendMessage(chatID, userID, msgTxt, false)
+}
+
+// HandleFavoritesCallback processes callbacks of the favorites list, args are the operation and the page to show.
+func (c *TelegramBotCommands) HandleFavoritesCallback(ctx context.Context, userID string, chatID int64, msgID int, data *callback.Data) {
+	action, objectID := data.Arg(0), data.ID
+	page, _ := strconv.Atoi(data.Arg(1))
+
+	var err error
+	switch action {
+	case "card":
+		c.sendFavoriteCard(ctx, userID, chatID, objectID)
+		return
+	case "up", "down":
+		var favorite *listings.Listing
+		favorite, err = c.listingsService.GetFavoriteListing(ctx, userID, objectID)
+		if err != nil {
+			break
+		}
+		rank := favorite.Rank - 1
+		if action == "down" {
+			rank = favorite.Rank + 1
+		}
+		err = c.listingsService.SetFavoriteListingRank(ctx, userID, objectID, rank)
+	case "rm":
+		err = c.listingsService.RemoveFavoriteListing(ctx, userID, objectID)
+	}
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		c.log.Error().Err(err).Str("userID", userID).Str("objectID", objectID).Msg("failed to update favorite listing")
+		msgTxt := "💥Failed to update favorite listing"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	favorites, err := c.listingsService.MGetFavoriteListingByUserID(ctx, userID)
+	if err != nil {
+		c.log.Error().Err(err).Msg("failed to get favorite listings")
+		msgTxt := "💥Failed to get favorite listings"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+	if len(favorites) == 0 {
+		c.editMessage(chatID, userID, msgID, "🤷No favorites left", nil)
+		return
+	}
+	favorites.SortByRank()
+
+	msgTxt, keyboard := renderFavoritesPage(favorites, page)
+	c.editMessage(chatID, userID, msgID, msgTxt, keyboard)
+}
+
+// HandleFavoriteNoteReply attaches a note to a favorite when replying to a message containing its link,
+// it reports whether the message was consumed.
+func (c *TelegramBotCommands) HandleFavoriteNoteReply(ctx context.Context, userID string, chatID int64, replyTo *tgbotapi.Message, text string) bool {
+	if replyTo.From == nil || replyTo.From.ID != c.bot.Self.ID {
+		return false
+	}
+	objectID, ok := listingObjectIDFromMessage(replyTo)
+	if !ok {
+		return false
+	}
+
+	note := strings.TrimSpace(text)
+	if note == favoriteNoteClear {
+		note = ""
+	}
+	if utf8.RuneCountInString(note) > favoriteNoteMaxLen {
+		msgTxt := fmt.Sprintf("⚠️The note is too long, it must be at most %d characters", favoriteNoteMaxLen)
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return true
+	}
+
+	err := c.listingsService.SetFavoriteListingNote(ctx, userID, objectID, note)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			msgTxt := "⚠️This listing is not in your favorites, save it first to attach a note"
+			c.sendMessage(chatID, userID, msgTxt, false)
+			return true
+		}
+		c.log.Error().Err(err).Str("userID", userID).Str("objectID", objectID).Msg("failed to set favorite listing note")
+		msgTxt := "💥Failed to set favorite listing note"
 		c.sendMessage(chatID, userID, msgTxt, false)
+		return true
+	}
+
+	msgTxt := "✅Note attached"
+	if note == "" {
+		msgTxt = "✅Note cleared"
+	}
+	c.sendMessage(chatID, userID, msgTxt, false)
+	return true
+}
+
+func (c *TelegramBotCommands) sendFavoriteCard(ctx context.Context, userID string, chatID int64, objectID string) {
+	msgTxt, keyboard, ok := c.renderFavoriteCard(ctx, userID, chatID, objectID)
+	if !ok {
 		return
 	}
+	c.sendMessageWithKeyboard(chatID, userID, msgTxt, keyboard, false)
+}
 
-	c.sendMessage(chatID, userID, msgTxt, true)
+// renderFavoriteCard renders a favorite with its pipeline history and stage buttons, errors are reported to the chat.
+func (c *TelegramBotCommands) renderFavoriteCard(ctx context.Context, userID string, chatID int64, objectID string) (string, *tgbotapi.InlineKeyboardMarkup, bool) {
+	favorite, err := c.listingsService.GetFavoriteListing(ctx, userID, objectID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			msgTxt := "🤷This listing is not in your favorites anymore"
+			c.sendMessage(chatID, userID, msgTxt, false)
+			return "", nil, false
+		}
+		c.log.Error().Err(err).Str("userID", userID).Str("objectID", objectID).Msg("failed to get favorite listing")
+		msgTxt := "💥Failed to get favorite listing"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return "", nil, false
+	}
+
+	session, err := c.sessionsService.GetSessionByUserID(ctx, userID)
+	if err != nil {
+		c.log.Error().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to get session details")
+		msgTxt := "💥Failed to get your session details"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return "", nil, false
+	}
+
+	events, err := c.listingsService.MGetStageEvent(ctx, userID, objectID)
+	if err != nil {
+		c.log.Error().Err(err).Str("userID", userID).Str("objectID", objectID).Msg("failed to get pipeline events")
+		msgTxt := "💥Failed to get pipeline history"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return "", nil, false
+	}
+
+	msgTxt := fmt.Sprintf("🏅#%d %s\n💶%.0f %s%s\n📍%s, %s, %s\n🆔%s", favorite.Rank, favorite.Name, favorite.Offers.Price, favorite.Offers.PriceCurrency, formatFavoriteStatus(favorite), favorite.Address.AddressRegion, favorite.Address.AddressLocality, favorite.Address.StreetAddress, favorite.ObjectID)
+	if favorite.Note != "" {
+		msgTxt += "\n📝" + favorite.Note
+	}
+	msgTxt += "\n" + formatStageHistory(events, session.Location())
+	msgTxt += fmt.Sprintf("\n%s\n\n↩️Reply to this message to attach a note, reply with %s to clear it", favorite.URL, favoriteNoteClear)
+	return msgTxt, pipelineKeyboard(favorite), true
+}
+
+// FormatListingChange renders a notification on what has happened to a favorite or a watched listing.
+func FormatListingChange(change *listings.Change) string {
+	label := changeLabels[change.Kind]
+	if change.Listing.IsGone {
+		return fmt.Sprintf("👻%s is not on Funda anymore\n🏠%s, %s\n%s", label[1], change.Listing.Name, change.Listing.Address.AddressLocality, change.Listing.URL)
+	}
+
+	msgTxt := fmt.Sprintf("%s%s update\n🏠%s, %s", label[0], label[1], change.Listing.Name, change.Listing.Address.AddressLocality)
+	if change.IsPriceChanged() {
+		msgTxt += fmt.Sprintf("\n💶Price: %.0f → %.0f %s", change.OldPrice, change.Listing.Offers.Price, change.Listing.Offers.PriceCurrency)
+	}
+	if change.IsStatusChanged() {
+		msgTxt += fmt.Sprintf("\n🏷️Status: %s → %s", change.OldStatus, change.Listing.Status)
+	}
+	return msgTxt + "\n" + change.Listing.URL
+}
+
+// formatFavoriteStatus renders the last known status of a favorite, favorites which were not checked yet have none.
+func formatFavoriteStatus(favorite *listings.Listing) string {
+	switch {
+	case favorite.IsGone:
+		return ", 👻not on Funda anymore"
+	case favorite.Status != "":
+		return ", " + favorite.Status
+	default:
+		return ""
+	}
+}
+
+func renderFavoritesPage(favorites listings.Listings, page int) (string, *tgbotapi.InlineKeyboardMarkup) {
+	pagesCount := (len(favorites) + favoritesPageSize - 1) / favoritesPageSize
+	page = min(max(page, 0), pagesCount-1)
+	pageFavorites := favorites[page*favoritesPageSize : min((page+1)*favoritesPageSize, len(favorites))]
+
+	msgTxt := fmt.Sprintf("⭐Favorites, page %d/%d", page+1, pagesCount)
+	rows := make([][]tgbotapi.InlineKeyboardButton, 0, len(pageFavorites)+1)
+	for idx := range pageFavorites {
+		favorite := &pageFavorites[idx]
+		msgTxt += fmt.Sprintf("\n\n#%d 🏠%s, %s\n💶%.0f %s%s\n%s\n🆔%s", favorite.Rank, favorite.Name, favorite.Address.AddressLocality, favorite.Offers.Price, favorite.Offers.PriceCurrency, formatFavoriteStatus(favorite), stageLabel(favorite.Stage), favorite.ObjectID)
+		if favorite.Note != "" {
+			msgTxt += "\n📝" + favorite.Note
+		}
+		msgTxt += "\n" + favorite.URL
+
+		// object IDs falling back to URLs may not fit into callback data
+		cardData := callback.New(callback.ActionFavorites, favorite.ObjectID, "card", strconv.Itoa(page))
+		if _, err := cardData.Encode(); err != nil {
+			continue
+		}
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			favoritesButton(fmt.Sprintf("#%d 🃏", favorite.Rank), "card", page, favorite.ObjectID),
+			favoritesButton("⬆️", "up", page, favorite.ObjectID),
+			favoritesButton("⬇️", "down", page, favorite.ObjectID),
+			favoritesButton("🗑", "rm", page, favorite.ObjectID),
+		))
+	}
+
+	var navigation []tgbotapi.InlineKeyboardButton
+	if page > 0 {
+		navigation = append(navigation, favoritesButton("◀️", "page", page-1, ""))
+	}
+	if page < pagesCount-1 {
+		navigation = append(navigation, favoritesButton("▶️", "page", page+1, ""))
+	}
+	if len(navigation) != 0 {
+		rows = append(rows, navigation)
+	}
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(rows...)
+	return msgTxt, &keyboard
+}
+
+func favoritesButton(label, action string, page int, objectID string) tgbotapi.InlineKeyboardButton {
+	return callbackButton(label, callback.New(callback.ActionFavorites, objectID, action, strconv.Itoa(page)))
+}
+
+// listingObjectIDFromMessage looks for a Funda listing link in a message, either as a plain URL or behind a text link.
+func listingObjectIDFromMessage(msg *tgbotapi.Message) (string, bool) {
+	text := utf16.Encode([]rune(msg.Text))
+	for _, entity := range msg.Entities {
+		var link string
+		switch {
+		case entity.Type == "text_link":
+			link = entity.URL
+		case entity.IsURL():
+			// entity offsets are in UTF-16 code units
+			if entity.Offset+entity.Length > len(text) {
+				continue
+			}
+			link = string(utf16.Decode(text[entity.Offset : entity.Offset+entity.Length]))
+		default:
+			continue
+		}
+		if !validateListingURL(link) {
+			continue
+		}
+		if objectID, err := funda.ObjectID(link); err == nil {
+			return objectID, true
+		}
+	}
+	return "", false
 }
@@ -0,0 +1,133 @@
+package commands
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"fundaNotifier/internal/pkg/tgbot/callback"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	hiddenListingsToShow    = 20
+	hiddenListingsPerRowBtn = 4
+)
+
+func (c *TelegramBotCommands) ShowHidden(ctx context.Context, userID string, chatID int64) {
+	msgTxt, keyboard, err := c.renderHidden(ctx, userID)
+	if err != nil {
+		msgTxt := "💥Failed to get hidden listings"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+	c.sendMessageWithKeyboard(chatID, userID, msgTxt, keyboard, false)
+}
+
+// HideListing hides a listing given by its card UUID and turns the card buttons into an undo button, inside the listing
+// browser or the /next stepper the next listing is shown instead.
+func (c *TelegramBotCommands) HideListing(ctx context.Context, userID string, chatID int64, msgID int, data *callback.Data) {
+	listing, ok := c.getListingByUUID(ctx, userID, chatID, data.ID)
+	if !ok {
+		return
+	}
+
+	err := c.blacklistService.Hide(ctx, userID, listing)
+	if err != nil {
+		c.log.Error().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to hide listing")
+		msgTxt := "💥Failed to hide listing"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	if len(data.Args) != 0 {
+		c.RefreshCard(ctx, userID, chatID, msgID, data.Args)
+		return
+	}
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+		callbackButton("↩️Undo hide", callback.New(callback.ActionUnhide, listing.ObjectID, listing.UUID)),
+	))
+	c.editReplyMarkup(chatID, userID, msgID, &keyboard)
+}
+
+// UnhideListing restores a hidden listing, the card it was hidden from gets its buttons back while the /show_hidden
+// message is rendered anew.
+func (c *TelegramBotCommands) UnhideListing(ctx context.Context, userID string, chatID int64, msgID int, data *callback.Data) {
+	err := c.blacklistService.Unhide(ctx, userID, data.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		c.log.Error().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to unhide listing")
+		msgTxt := "💥Failed to unhide listing"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	if UUID := data.Arg(0); UUID != "" {
+		listing, ok := c.getListingByUUID(ctx, userID, chatID, UUID)
+		if !ok {
+			return
+		}
+		_, errFavorite := c.listingsService.GetFavoriteListing(ctx, userID, listing.ObjectID)
+		c.editReplyMarkup(chatID, userID, msgID, ListingCardKeyboard(listing, errFavorite == nil))
+		return
+	}
+
+	msgTxt, keyboard, err := c.renderHidden(ctx, userID)
+	if err != nil {
+		msgTxt := "💥Failed to get hidden listings"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+	c.editMessage(chatID, userID, msgID, msgTxt, keyboard)
+}
+
+// renderHidden lists blacklist rules followed by the latest hidden listings with a restore button for each of them.
+func (c *TelegramBotCommands) renderHidden(ctx context.Context, userID string) (string, *tgbotapi.InlineKeyboardMarkup, error) {
+	rules, err := c.blacklistService.MGetRuleByUserID(ctx, userID)
+	if err != nil {
+		c.log.Error().Err(err).Str("userID", userID).Msg("failed to get blacklist rules")
+		return "", nil, err
+	}
+
+	hiddenListings, err := c.blacklistService.MGetHiddenListingByUserID(ctx, userID)
+	if err != nil {
+		c.log.Error().Err(err).Str("userID", userID).Msg("failed to get hidden listings")
+		return "", nil, err
+	}
+
+	msgTxt := "🚫Blacklist rules, use /unblacklist followed by a kind and a value to remove any of them:"
+	if len(rules) == 0 {
+		msgTxt = "🚫No blacklist rules, add one with /blacklist"
+	}
+	for idx := range rules {
+		msgTxt += fmt.Sprintf("\n%s: %s", rules[idx].Kind, rules[idx].Value)
+	}
+
+	if len(hiddenListings) == 0 {
+		msgTxt += "\n\n🙈No hidden listings, hide one with the button under its card"
+		return msgTxt, nil, nil
+	}
+
+	msgTxt += "\n\n🙈Hidden listings, tap a number to show it again:"
+	rows := make([][]tgbotapi.InlineKeyboardButton, 0)
+	for idx := range hiddenListings {
+		if idx == hiddenListingsToShow {
+			msgTxt += fmt.Sprintf("\n…and %d more", len(hiddenListings)-hiddenListingsToShow)
+			break
+		}
+		msgTxt += fmt.Sprintf("\n%d. %s\n%s", idx+1, hiddenListings[idx].Name, hiddenListings[idx].URL)
+		if idx%hiddenListingsPerRowBtn == 0 {
+			rows = append(rows, tgbotapi.NewInlineKeyboardRow())
+		}
+		rows[len(rows)-1] = append(rows[len(rows)-1], callbackButton(fmt.Sprintf("↩️#%d", idx+1), callback.New(callback.ActionUnhide, hiddenListings[idx].ObjectID)))
+	}
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(rows...)
+	return msgTxt, &keyboard, nil
+}
+
+func (c *TelegramBotCommands) editReplyMarkup(chatID int64, userID string, msgID int, keyboard *tgbotapi.InlineKeyboardMarkup) {
+	edit := tgbotapi.NewEditMessageReplyMarkup(chatID, msgID, *keyboard)
+	if _, err := c.bot.Request(edit); err != nil {
+		c.log.Error().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to update buttons")
+	}
+}
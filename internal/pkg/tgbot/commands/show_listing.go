@@ -0,0 +1,180 @@
+package commands
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"fundaNotifier/internal/domain/listings"
+	"fundaNotifier/internal/pkg/funda"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// mediaGroupMaxLen is the limit Telegram puts on photos in a single album
+const mediaGroupMaxLen = 10
+
+func (c *TelegramBotCommands) ShowListing(ctx context.Context, userID string, chatID int64, args string) {
+	objectID := strings.TrimSpace(args)
+	if objectID == "" || strings.ContainsAny(objectID, " \n") {
+		msgTxt := "⚠️Usage: /listing <ID or URL> (e.g. `/listing 43123456`), listing IDs are shown in details and favorites"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+	if parsedObjectID, err := funda.ObjectID(objectID); err == nil {
+		objectID = parsedObjectID
+	}
+
+	listing, err := c.listingsService.GetListingByObjectID(ctx, userID, objectID)
+	if errors.Is(err, sql.ErrNoRows) {
+		// a favorite outlives the listing once no query matches it anymore
+		listing, err = c.listingsService.GetFavoriteListing(ctx, userID, objectID)
+	}
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			msgTxt := fmt.Sprintf("🤷Listing %s is neither found by your queries nor saved to favorites", objectID)
+			c.sendMessage(chatID, userID, msgTxt, false)
+			return
+		}
+		c.log.Error().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to get listing")
+		msgTxt := "💥Failed to get listing"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	c.sendListingDetails(ctx, userID, chatID, listing)
+}
+
+// sendListingDetails sends the photo gallery of a listing followed by everything stored about it.
+func (c *TelegramBotCommands) sendListingDetails(ctx context.Context, userID string, chatID int64, listing *listings.Listing) {
+	session, err := c.sessionsService.GetSessionByUserID(ctx, userID)
+	if err != nil {
+		c.log.Error().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to get session details")
+		msgTxt := "💥Failed to get your session details"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	favorite, err := c.listingsService.GetFavoriteListing(ctx, userID, listing.ObjectID)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			c.log.Error().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to get favorite listing")
+			msgTxt := "💥Failed to get favorite listing"
+			c.sendMessage(chatID, userID, msgTxt, false)
+			return
+		}
+		favorite = nil
+	}
+
+	points, err := c.listingsService.MGetPricePoint(ctx, userID, listing.ObjectID)
+	if err != nil {
+		c.log.Error().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to get price history")
+		msgTxt := "💥Failed to get price history"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	c.sendPhotos(userID, chatID, listing.PhotoURLs())
+
+	msgTxt := formatListingDetails(listing, favorite, points, session.Location(), c.rating(ctx, session).label(listing))
+	if listing.Description != "" {
+		msgTxt += "\n\n" + truncateMarkdownV2(listing.Description, messageMaxCharLen-utf8.RuneCountInString(msgTxt)-2)
+	}
+	c.markSeen(ctx, userID, []string{listing.ObjectID})
+	if listing.UUID == "" {
+		c.sendMessage(chatID, userID, msgTxt, true)
+		return
+	}
+	c.sendMessageWithKeyboard(chatID, userID, msgTxt, ListingCardKeyboard(listing, favorite != nil), true)
+}
+
+// sendPhotos sends up to an album worth of photos, a photo Telegram fails to fetch does not prevent the details from
+// being shown.
+func (c *TelegramBotCommands) sendPhotos(userID string, chatID int64, urls []string) {
+	if len(urls) == 0 {
+		return
+	}
+	if len(urls) == 1 {
+		if _, err := c.bot.Send(tgbotapi.NewPhoto(chatID, tgbotapi.FileURL(urls[0]))); err != nil {
+			c.log.Error().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to send photo to")
+		}
+		return
+	}
+
+	media := make([]interface{}, 0, mediaGroupMaxLen)
+	for idx := 0; idx < len(urls) && idx < mediaGroupMaxLen; idx++ {
+		media = append(media, tgbotapi.NewInputMediaPhoto(tgbotapi.FileURL(urls[idx])))
+	}
+	if _, err := c.bot.SendMediaGroup(tgbotapi.NewMediaGroup(chatID, media)); err != nil {
+		c.log.Error().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to send media group to")
+	}
+}
+
+// formatListingDetails renders every stored attribute of a listing as MarkdownV2, favorite is nil for unsaved listings
+// and label is empty for unrated ones.
+func formatListingDetails(listing, favorite *listings.Listing, points listings.PricePoints, loc *time.Location, label string) string {
+	msgTxt := fmt.Sprintf("%s🏠*%s*\n💶%s", label, escapeMarkdownV2(listing.Name), escapeMarkdownV2(fmt.Sprintf("%.0f %s", listing.Offers.Price, listing.Offers.PriceCurrency)))
+	if listing.LivingArea != 0 {
+		msgTxt += fmt.Sprintf("\n📐%d m²", listing.LivingArea)
+	}
+	if listing.Rooms != 0 {
+		msgTxt += fmt.Sprintf("\n🚪%d rooms", listing.Rooms)
+	}
+	if listing.EnergyLabel != "" {
+		msgTxt += "\n🔋Energy label " + escapeMarkdownV2(listing.EnergyLabel)
+	}
+	address := []string{listing.Address.StreetAddress, strings.TrimSpace(listing.Address.PostalCode + " " + listing.Address.AddressLocality), listing.Address.AddressRegion}
+	msgTxt += "\n📍" + escapeMarkdownV2(strings.Join(address, ", "))
+	if listing.Agent != "" {
+		msgTxt += "\n🏢" + escapeMarkdownV2(listing.Agent)
+	}
+	if listing.QueryName != "" {
+		msgTxt += "\n🔎Found by " + escapeMarkdownV2(listing.QueryName)
+	}
+	msgTxt += "\n🕒First seen " + escapeMarkdownV2(listing.CreatedAt.In(loc).Format(time.RFC850))
+	if listing.IsNew {
+		msgTxt += "\n🆕New since the last sync"
+	}
+	msgTxt += "\n🆔" + escapeMarkdownV2(listing.ObjectID)
+
+	if favorite != nil {
+		msgTxt += escapeMarkdownV2(fmt.Sprintf("\n💚Saved as #%d, %s%s", favorite.Rank, stageLabel(favorite.Stage), formatFavoriteStatus(favorite)))
+		if favorite.Note != "" {
+			msgTxt += "\n📝" + escapeMarkdownV2(favorite.Note)
+		}
+	} else {
+		msgTxt += "\n🤍Not saved"
+	}
+
+	if len(points) != 0 {
+		msgTxt += "\n📈Price history" + escapeMarkdownV2(formatPricePoints(points, loc))
+	}
+	return msgTxt + fmt.Sprintf("\n[Open on Funda](%s)", escapeMarkdownV2(listing.URL))
+}
+
+// truncateMarkdownV2 escapes text to fit into maxLen characters, cutting it at a word boundary when it is too long.
+func truncateMarkdownV2(text string, maxLen int) string {
+	escaped := escapeMarkdownV2(text)
+	if utf8.RuneCountInString(escaped) <= maxLen {
+		return escaped
+	}
+
+	runes := []rune(text)
+	for cut := min(len(runes), maxLen-1); cut > 0; {
+		truncated := strings.TrimRightFunc(string(runes[:cut]), unicode.IsSpace)
+		if idx := strings.LastIndexFunc(truncated, unicode.IsSpace); idx > len(truncated)/2 {
+			truncated = truncated[:idx]
+		}
+		escaped = escapeMarkdownV2(truncated) + "…"
+		excess := utf8.RuneCountInString(escaped) - maxLen
+		if excess <= 0 {
+			return escaped
+		}
+		cut -= excess
+	}
+	return ""
+}
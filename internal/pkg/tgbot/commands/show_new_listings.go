@@ -16,19 +16,19 @@ func (c *TelegramBotCommands) ShowNewListings(ctx context.Context, userID string
 		return
 	}
 
-	newListings, err := c.listingsService.MGetListingByUserID(ctx, userID, true)
+	newListings, err := c.UnseenListings(ctx, session)
 	if err != nil {
-		c.log.Error().Err(err).Msg("failed to get new listings")
-		msgTxt := "💥Failed to get new listings"
+		c.log.Error().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to get unseen listings")
+		msgTxt := "💥Failed to get unseen listings"
 		c.sendMessage(chatID, userID, msgTxt, false)
 		return
 	}
-	newListings = newListings.FilterByRegionsAndCities(session.Regions, session.Cities)
-	newListings.Sort()
+	rating := c.rating(ctx, session)
+	rating.sort(newListings)
 
 	var msgTxt string
 	for idx := range newListings {
-		addMsgTxt := fmt.Sprintf(fmt.Sprintf("🏠[%.0f %s %s](%s)\n%s, %s, %s\n%s\n", newListings[idx].Offers.Price, newListings[idx].Offers.PriceCurrency, escapeMarkdownV2(newListings[idx].Name), escapeMarkdownV2(newListings[idx].URL), escapeMarkdownV2(newListings[idx].Address.AddressRegion), escapeMarkdownV2(newListings[idx].Address.AddressLocality), escapeMarkdownV2(newListings[idx].Address.StreetAddress), escapeMarkdownV2(newListings[idx].CreatedAt.Format(time.RFC850))))
+		addMsgTxt := fmt.Sprintf("%s🏠[%.0f %s %s](%s)\n%s, %s, %s\n%s\n", rating.label(&newListings[idx]), newListings[idx].Offers.Price, newListings[idx].Offers.PriceCurrency, escapeMarkdownV2(newListings[idx].Name), escapeMarkdownV2(newListings[idx].URL), escapeMarkdownV2(newListings[idx].Address.AddressRegion), escapeMarkdownV2(newListings[idx].Address.AddressLocality), escapeMarkdownV2(newListings[idx].Address.StreetAddress), escapeMarkdownV2(newListings[idx].CreatedAt.In(session.Location()).Format(time.RFC850)))
 		if utf8.RuneCountInString(msgTxt+addMsgTxt) > messageMaxCharLen {
 			c.sendMessage(chatID, userID, msgTxt, true)
 			msgTxt = ""
@@ -36,10 +36,11 @@ func (c *TelegramBotCommands) ShowNewListings(ctx context.Context, userID string
 		msgTxt += addMsgTxt
 	}
 	if msgTxt == "" {
-		msgTxt = "🤷Nothing to show, call /update_now or /run to start collecting data; if you already did - this means that last sync retrieved zero new listings"
+		msgTxt = "🤷Nothing to show, call /update_now or /run to start collecting data; if you already did - this means that you have seen all listings"
 		c.sendMessage(chatID, userID, msgTxt, false)
 		return
 	}
 
 	c.sendMessage(chatID, userID, msgTxt, true)
+	c.markSeen(ctx, userID, newListings.ObjectIDs())
 }
@@ -14,8 +14,12 @@ func (c *TelegramBotCommands) ShowPollingInterval(ctx context.Context, userID st
 		return
 	}
 
-	pollingInterval := time.Duration(session.UpdateIntervalSeconds) * time.Second
-	msgTxt := "⏳Active polling interval: " + pollingInterval.String()
+	var msgTxt string
+	if session.Schedule != "" {
+		msgTxt = "🗓️Active polling schedule: " + session.Schedule + " (" + session.Location().String() + ")"
+	} else {
+		pollingInterval := time.Duration(session.UpdateIntervalSeconds) * time.Second
+		msgTxt = "⏳Active polling interval: " + pollingInterval.String()
+	}
 	c.sendMessage(chatID, userID, msgTxt, false)
-
 }
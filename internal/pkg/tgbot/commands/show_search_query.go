@@ -0,0 +1,66 @@
+package commands
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"fundaNotifier/internal/domain/search_queries"
+	"fundaNotifier/internal/pkg/funda"
+	"strings"
+)
+
+func (c *TelegramBotCommands) ShowSearchQuery(ctx context.Context, userID string, chatID int64, queryName string) {
+	var searchQueries search_queries.SearchQueries
+	if queryName = search_queries.NormalizeName(queryName); queryName != "" {
+		searchQuery, err := c.searchQueriesService.GetSearchQueryByName(ctx, userID, queryName)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				msgTxt := fmt.Sprintf("🤷Search query %s does not exist", queryName)
+				c.sendMessage(chatID, userID, msgTxt, false)
+				return
+			}
+			c.log.Error().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to get search query")
+			msgTxt := "💥Failed to get search query"
+			c.sendMessage(chatID, userID, msgTxt, false)
+			return
+		}
+		searchQueries = search_queries.SearchQueries{*searchQuery}
+	} else {
+		var err error
+		searchQueries, err = c.searchQueriesService.MGetSearchQueryByUserID(ctx, userID)
+		if err != nil {
+			c.log.Error().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to get search queries")
+			msgTxt := "💥Failed to get search queries"
+			c.sendMessage(chatID, userID, msgTxt, false)
+			return
+		}
+	}
+
+	if len(searchQueries) == 0 {
+		msgTxt := "🤷No search queries are set, use /set_search_query, /add_query or /build_query to add one"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	for idx := range searchQueries {
+		c.sendMessage(chatID, userID, inspectSearchQuery(&searchQueries[idx]), false)
+	}
+}
+
+func inspectSearchQuery(searchQuery *search_queries.SearchQuery) string {
+	msgTxt := fmt.Sprintf("🔎Search query %s\n", searchQuery.Name)
+	query, warnings, err := funda.Inspect(searchQuery.URL)
+	if err != nil {
+		return msgTxt + fmt.Sprintf("⚠️Failed to parse the URL: %s\n%s", err.Error(), searchQuery.URL)
+	}
+
+	msgTxt += describeSearchQuery(query)
+	if extraParams := query.ExtraParams(); len(extraParams) > 0 {
+		msgTxt += "\n➕Other parameters: " + strings.Join(extraParams, ", ")
+	}
+	for idx := range warnings {
+		msgTxt += "\n⚠️" + warnings[idx]
+	}
+	return msgTxt + "\n" + searchQuery.URL
+}
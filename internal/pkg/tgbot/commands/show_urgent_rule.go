@@ -0,0 +1,45 @@
+package commands
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+func (c *TelegramBotCommands) ShowUrgentRule(ctx context.Context, userID string, chatID int64) {
+	rule, err := c.urgentRulesService.GetRuleByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			msgTxt := "🤷No urgent rule is set, use /set_urgent_rule to define one"
+			c.sendMessage(chatID, userID, msgTxt, false)
+			return
+		}
+		c.log.Error().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to get urgent rule")
+		msgTxt := "💥Failed to get urgent rule"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	var (
+		msgRegions  = "all"
+		msgCities   = "all"
+		msgMaxPrice = "any"
+	)
+	if len(rule.Regions) > 0 {
+		msgRegions = strings.Join(rule.Regions, ", ")
+	}
+	if len(rule.Cities) > 0 {
+		msgCities = strings.Join(rule.Cities, ", ")
+	}
+	if rule.MaxPrice > 0 {
+		msgMaxPrice = fmt.Sprintf("%.0f", rule.MaxPrice)
+	}
+	msgTxt := "🚨Urgent rule" +
+		"\n💶Max price: " + msgMaxPrice +
+		"\n🌍Regions: " + msgRegions +
+		"\n📍Cities: " + msgCities +
+		fmt.Sprintf("\n🔢Daily cap: %d", rule.DailyCap)
+	c.sendMessage(chatID, userID, msgTxt, false)
+}
@@ -10,6 +10,6 @@ func (c *TelegramBotCommands) Start(ctx context.Context, userID string, chatID i
 		return
 	}
 
-	msgTxt := "👋Hi\n✨Please run /help to see all available commands.\n❗You must define search query with /set_search_query\n❗You must define polling interval with /set_polling_interval\n❓You may optionally define active regions with /set_regions\n❓You may optionally define active cities with /set_cities"
+	msgTxt := "👋Hi\n✨Please run /help to see all available commands.\n❗You must define search query with /set_search_query\n❗You must define polling interval with /set_polling_interval\n❓You may optionally define active regions with /set_regions\n❓You may optionally define active cities with /set_cities\n❓You may optionally define your timezone with /set_timezone"
 	c.sendMessage(chatID, userID, msgTxt, false)
 }
@@ -2,8 +2,6 @@ package commands
 
 import (
 	"context"
-	"database/sql"
-	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -18,16 +16,20 @@ func (c *TelegramBotCommands) Stop(ctx context.Context, userID string, chatID in
 		return
 	}
 
-	URL, err := c.searchQueriesService.GetSearchQuery(ctx, userID)
+	searchQueries, err := c.searchQueriesService.MGetSearchQueryByUserID(ctx, userID)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			URL = "not set"
-		} else {
-			c.log.Error().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to get search query upon /stop command")
-			msgTxt := "💥Failed to get search query upon /stop command"
-			c.sendMessage(chatID, userID, msgTxt, false)
-			return
-		}
+		c.log.Error().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to get search queries upon /stop command")
+		msgTxt := "💥Failed to get search queries upon /stop command"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	windows, err := c.dndWindowsService.MGetWindowByUserID(ctx, userID)
+	if err != nil {
+		c.log.Error().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to get DND windows upon /stop command")
+		msgTxt := "💥Failed to get DND windows upon /stop command"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
 	}
 
 	if err = c.sessionsService.RemoveEverythingByUserID(ctx, userID); err != nil {
@@ -49,7 +51,7 @@ func (c *TelegramBotCommands) Stop(ctx context.Context, userID string, chatID in
 		msgCities = strings.Join(session.Cities, ", ")
 	}
 
-	msgTxt := fmt.Sprintf("⏳Polling interval: %s\n🌍Regions: %s\n📍Cities: %s\n🌝DND start: %s UTC\n🌚DND end: %s UTC\n Search query: %s\n", pollingInterval.String(), msgRegions, msgCities, minutesAfterMidnightToDayTime(session.DNDStart), minutesAfterMidnightToDayTime(session.DNDEnd), URL)
+	msgTxt := fmt.Sprintf("⏳Polling interval: %s\n🌍Regions: %s\n📍Cities: %s\n🌐Timezone: %s\n🌝DND windows:%s\n🔎Search queries:%s\n", pollingInterval.String(), msgRegions, msgCities, session.Location().String(), formatDNDWindows(windows), formatSearchQueries(searchQueries))
 	msgTxt += "⏹️You have stopped the bot, all your data and settings were removed"
 	c.sendMessage(chatID, userID, msgTxt, false)
 }
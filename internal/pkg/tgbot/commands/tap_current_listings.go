@@ -4,8 +4,6 @@ import (
 	"context"
 	"fmt"
 	"time"
-
-	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
 
 func (c *TelegramBotCommands) TapCurrentListings(ctx context.Context, userID string, chatID int64) {
@@ -24,8 +22,14 @@ func (c *TelegramBotCommands) TapCurrentListings(ctx context.Context, userID str
 		c.sendMessage(chatID, userID, msgTxt, false)
 		return
 	}
-	allListings = allListings.FilterByRegionsAndCities(session.Regions, session.Cities)
-	allListings.Sort()
+	allListings, err = c.filterListings(ctx, session, allListings)
+	if err != nil {
+		msgTxt := "💥Failed to get your blacklist"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+	rating := c.rating(ctx, session)
+	rating.sort(allListings)
 
 	if len(allListings) == 0 {
 		msgTxt := "🤷Nothing to show, list of favorites is empty"
@@ -34,9 +38,8 @@ func (c *TelegramBotCommands) TapCurrentListings(ctx context.Context, userID str
 	}
 
 	for idx := range allListings {
-		msgTxt := fmt.Sprintf(fmt.Sprintf("🏠[%.0f %s %s](%s)\n%s, %s, %s\n%s\n", allListings[idx].Offers.Price, allListings[idx].Offers.PriceCurrency, escapeMarkdownV2(allListings[idx].Name), escapeMarkdownV2(allListings[idx].URL), escapeMarkdownV2(allListings[idx].Address.AddressRegion), escapeMarkdownV2(allListings[idx].Address.AddressLocality), escapeMarkdownV2(allListings[idx].Address.StreetAddress), escapeMarkdownV2(allListings[idx].CreatedAt.Format(time.RFC850))))
-		rows := [][]tgbotapi.InlineKeyboardButton{tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData("️➕Save", allListings[idx].UUID))}
-		keyboard := tgbotapi.NewInlineKeyboardMarkup(rows...)
-		c.sendMessageWithKeyboard(chatID, userID, msgTxt, &keyboard, true)
+		msgTxt := fmt.Sprintf("%s🏠[%.0f %s %s](%s)\n%s, %s, %s\n%s\n", rating.label(&allListings[idx]), allListings[idx].Offers.Price, allListings[idx].Offers.PriceCurrency, escapeMarkdownV2(allListings[idx].Name), escapeMarkdownV2(allListings[idx].URL), escapeMarkdownV2(allListings[idx].Address.AddressRegion), escapeMarkdownV2(allListings[idx].Address.AddressLocality), escapeMarkdownV2(allListings[idx].Address.StreetAddress), escapeMarkdownV2(allListings[idx].CreatedAt.In(session.Location()).Format(time.RFC850)))
+		c.sendMessageWithKeyboard(chatID, userID, msgTxt, ListingCardKeyboard(&allListings[idx], false), true)
 	}
+	c.markSeen(ctx, userID, allListings.ObjectIDs())
 }
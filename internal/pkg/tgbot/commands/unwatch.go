@@ -0,0 +1,39 @@
+package commands
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+)
+
+func (c *TelegramBotCommands) Unwatch(ctx context.Context, userID string, chatID int64, listing string) {
+	listing = strings.TrimSpace(listing)
+	if listing == "" {
+		msgTxt := "⚠️Usage: /unwatch <URL or ID>, run /show_favorites to see watched listings"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	if strings.Contains(listing, "/") && !validateListingURL(listing) {
+		msgTxt := "⚠️The provided URL is not a funda.nl listing page"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	err := c.watchedListingsService.Unwatch(ctx, userID, listing)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			msgTxt := "🤷This listing is not watched, run /show_favorites to see watched listings"
+			c.sendMessage(chatID, userID, msgTxt, false)
+			return
+		}
+		c.log.Error().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to unwatch listing")
+		msgTxt := "💥Failed to unwatch listing"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	msgTxt := "✅Listing is not watched anymore"
+	c.sendMessage(chatID, userID, msgTxt, false)
+}
@@ -2,6 +2,8 @@ package commands
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 )
@@ -22,32 +24,90 @@ func (c *TelegramBotCommands) UpdateNow(ctx context.Context, userID string, chat
 		return
 	}
 
-	searchQuery, err := c.searchQueriesService.GetSearchQuery(ctx, session.UserID)
+	searchQueries, err := c.searchQueriesService.MGetSearchQueryByUserID(ctx, session.UserID)
 	if err != nil {
-		c.log.Error().Err(err).Str("userID", session.UserID).Msg("failed to get search query for sync")
-		msgTxt := fmt.Sprintf("📅Updated at %s\n💥failed to get listings updates", time.Now().Format(time.RFC3339))
+		c.log.Error().Err(err).Str("userID", session.UserID).Msg("failed to get search queries for sync")
+		msgTxt := fmt.Sprintf("📅Updated at %s\n💥failed to get listings updates", time.Now().In(session.Location()).Format(time.RFC3339))
 		c.sendMessage(session.ChatID, session.UserID, msgTxt, false)
 		return
 	}
 
+	activeSearchQueries := searchQueries.Active()
+	if len(activeSearchQueries) == 0 {
+		msgTxt := "🤷All search queries are paused, use /resume_query to resume any of them"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
 	err = c.sessionsService.UpdateLastSyncedAt(ctx, session.UserID, time.Now())
 	if err != nil {
 		c.log.Error().Err(err).Str("userID", session.UserID).Msg("failed to update last sync timestamp")
-		msgTxt := fmt.Sprintf("📅Updated at %s\n💥failed to update last sync timestamp", time.Now().Format(time.RFC3339))
+		msgTxt := fmt.Sprintf("📅Updated at %s\n💥failed to update last sync timestamp", time.Now().In(session.Location()).Format(time.RFC3339))
 		c.sendMessage(session.ChatID, session.UserID, msgTxt, false)
 		return
 	}
 
-	addedListings, removedListings, _, err := c.listingsService.UpdateAndCompareListings(ctx, session.UserID, searchQuery)
+	filter, err := c.blacklistService.GetFilter(ctx, session.UserID)
+	if err != nil {
+		c.log.Error().Err(err).Str("userID", session.UserID).Msg("failed to get blacklist for sync")
+	}
+
+	// urgent matches are exempt from suppression of unlikely matches, a broken rule only takes the exemption away
+	urgentRule, err := c.urgentRulesService.GetRuleByUserID(ctx, session.UserID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		c.log.Error().Err(err).Str("userID", session.UserID).Msg("failed to get urgent rule for sync")
+	}
+
+	messages := make([]string, 0, len(activeSearchQueries))
+	for idx := range activeSearchQueries {
+		update, errUpdate := c.listingsService.UpdateAndCompareListings(ctx, session.UserID, activeSearchQueries[idx].Name, activeSearchQueries[idx].Version, activeSearchQueries[idx].URL)
+		if errUpdate != nil {
+			c.log.Error().Err(errUpdate).Str("userID", session.UserID).Str("queryName", activeSearchQueries[idx].Name).Msg("failed to compare and update listings within sync iteration")
+			msgTxt := fmt.Sprintf("📅Updated at %s\n🔎Query: %s\n💥failed to get listings updates", time.Now().In(session.Location()).Format(time.RFC3339), activeSearchQueries[idx].Name)
+			c.sendMessage(session.ChatID, session.UserID, msgTxt, false)
+			continue
+		}
+
+		filteredAddedListings := update.Added.FilterByRegionsAndCities(activeSearchQueries[idx].Regions, activeSearchQueries[idx].Cities)
+		filteredAddedListings = filteredAddedListings.FilterByRegionsAndCities(session.Regions, session.Cities)
+		filteredRemovedListings := update.Removed.FilterByRegionsAndCities(activeSearchQueries[idx].Regions, activeSearchQueries[idx].Cities)
+		filteredRemovedListings = filteredRemovedListings.FilterByRegionsAndCities(session.Regions, session.Cities)
+		filteredAddedListings = filter.Apply(filteredAddedListings)
+		filteredRemovedListings = filter.Apply(filteredRemovedListings)
+		filteredAddedListings, suppressed := c.SuppressUnlikely(ctx, session, filteredAddedListings, urgentRule)
+		msgTxt := fmt.Sprintf("📅Updated at %s\n🔎Query: %s\n➕Added listings count: %d\n➖Removed listings count: %d", time.Now().In(session.Location()).Format(time.RFC3339), activeSearchQueries[idx].Name, len(filteredAddedListings), len(filteredRemovedListings))
+		if update.IsTruncated {
+			msgTxt += "\n" + TooBroadWarning(update.TotalCount, update.TrackedCount, activeSearchQueries[idx].URL)
+		}
+		msgTxt += SuppressedCounter(suppressed)
+		msgTxt += c.ScoreSummary(ctx, session, filteredAddedListings)
+		messages = append(messages, msgTxt)
+	}
+	// unseen listings are counted once all queries are synced and the count goes to the last sync message
+	if len(messages) != 0 {
+		messages[len(messages)-1] += c.UnseenCounter(ctx, session)
+	}
+	for idx := range messages {
+		c.sendMessage(session.ChatID, session.UserID, messages[idx], false)
+	}
+	changes, err := c.watchedListingsService.CheckWatchedListings(ctx, session.UserID)
 	if err != nil {
-		c.log.Error().Err(err).Str("userID", session.UserID).Msg("failed to compare and update listings within sync iteration")
-		msgTxt := fmt.Sprintf("📅Updated at %s\n💥failed to get listings updates", time.Now().Format(time.RFC3339))
+		c.log.Error().Err(err).Str("userID", session.UserID).Msg("failed to check watched listings")
+		msgTxt := "💥Failed to check watched listings"
 		c.sendMessage(session.ChatID, session.UserID, msgTxt, false)
 		return
 	}
-
-	filteredAddedListings := addedListings.FilterByRegionsAndCities(session.Regions, session.Cities)
-	filteredRemovedListings := removedListings.FilterByRegionsAndCities(session.Regions, session.Cities)
-	msgTxt := fmt.Sprintf("📅Updated at %s\n➕Added listings count: %d\n➖Removed listings count: %d", time.Now().Format(time.RFC3339), len(filteredAddedListings), len(filteredRemovedListings))
-	c.sendMessage(session.ChatID, session.UserID, msgTxt, false)
+	for idx := range changes {
+		c.sendMessage(session.ChatID, session.UserID, FormatListingChange(&changes[idx]), false)
+	}
+	favoriteChanges, err := c.listingsService.CheckFavoriteListings(ctx, session.UserID)
+	if err != nil {
+		c.log.Error().Err(err).Str("userID", session.UserID).Msg("failed to check favorite listings")
+		msgTxt := "💥Failed to check favorite listings"
+		c.sendMessage(session.ChatID, session.UserID, msgTxt, false)
+		return
+	}
+	for idx := range favoriteChanges {
+		c.sendMessage(session.ChatID, session.UserID, FormatListingChange(&favoriteChanges[idx]), false)
+	}
 }
@@ -0,0 +1,183 @@
+package commands
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"fundaNotifier/internal/domain/listings"
+	"fundaNotifier/internal/domain/viewings"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	viewingInputLayout = "2006-01-02 15:04"
+	viewingLayout      = "Mon 02 Jan 2006 15:04"
+	viewingRemindArg   = "remind="
+	viewingsFileName   = "viewings.ics"
+)
+
+func (c *TelegramBotCommands) ScheduleViewing(ctx context.Context, userID string, chatID int64, args string) {
+	fields := strings.Fields(args)
+	if len(fields) != 3 && len(fields) != 4 {
+		msgTxt := "⚠️Usage: /schedule_viewing <ID> <YYYY-MM-DD> <HH:MM> [remind=<duration>] (e.g. `/schedule_viewing 43123456 2024-10-21 18:30 remind=1h`), run /show_favorites to see listing IDs"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	var remindBefore time.Duration
+	if len(fields) == 4 {
+		duration, err := time.ParseDuration(strings.TrimPrefix(fields[3], viewingRemindArg))
+		if !strings.HasPrefix(fields[3], viewingRemindArg) || err != nil || duration <= 0 {
+			msgTxt := fmt.Sprintf("⚠️Invalid reminder %s, expected e.g. `remind=30m` or `remind=2h`", fields[3])
+			c.sendMessage(chatID, userID, msgTxt, false)
+			return
+		}
+		remindBefore = duration
+	}
+
+	session, err := c.sessionsService.GetSessionByUserID(ctx, userID)
+	if err != nil {
+		c.log.Error().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to get session details")
+		msgTxt := "💥Failed to get your session details"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	startsAt, err := time.ParseInLocation(viewingInputLayout, fields[1]+" "+fields[2], session.Location())
+	if err != nil {
+		msgTxt := "⚠️Invalid date and time, expected YYYY-MM-DD HH:MM in your timezone (see /set_timezone)"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+	if !startsAt.After(time.Now()) {
+		msgTxt := "⚠️The viewing must be in the future"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	favorite, err := c.listingsService.GetFavoriteListing(ctx, userID, fields[0])
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			msgTxt := fmt.Sprintf("🤷Listing %s is not in your favorites, save it first to schedule a viewing", fields[0])
+			c.sendMessage(chatID, userID, msgTxt, false)
+			return
+		}
+		c.log.Error().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to get favorite listing")
+		msgTxt := "💥Failed to get favorite listing"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	viewing, err := c.viewingsService.Schedule(ctx, favorite, startsAt, remindBefore)
+	if err != nil {
+		c.log.Error().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to schedule viewing")
+		msgTxt := "💥Failed to schedule viewing"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	// a scheduled viewing moves the favorite forward in the pipeline but never back
+	if favorite.Stage == listings.StageInterested || favorite.Stage == listings.StageContacted {
+		if _, err = c.listingsService.SetFavoriteListingStage(ctx, userID, favorite.ObjectID, listings.StageViewing); err != nil {
+			c.log.Error().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to set favorite listing stage")
+		}
+	}
+
+	msgTxt := fmt.Sprintf("✅Viewing of %s is scheduled at %s, a reminder is sent at %s", viewing.Name, viewing.StartsAt.In(session.Location()).Format(viewingLayout), viewing.RemindAt.In(session.Location()).Format(viewingLayout))
+	c.sendMessage(chatID, userID, msgTxt, false)
+}
+
+func (c *TelegramBotCommands) CancelViewing(ctx context.Context, userID string, chatID int64, objectID string) {
+	objectID = strings.TrimSpace(objectID)
+	if objectID == "" {
+		msgTxt := "⚠️Usage: /cancel_viewing <ID>, run /show_viewings to see scheduled viewings"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	err := c.viewingsService.Cancel(ctx, userID, objectID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			msgTxt := "🤷There is no viewing of this listing, run /show_viewings to see scheduled viewings"
+			c.sendMessage(chatID, userID, msgTxt, false)
+			return
+		}
+		c.log.Error().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to cancel viewing")
+		msgTxt := "💥Failed to cancel viewing"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	msgTxt := "✅Viewing cancelled"
+	c.sendMessage(chatID, userID, msgTxt, false)
+}
+
+func (c *TelegramBotCommands) ShowViewings(ctx context.Context, userID string, chatID int64) {
+	upcomingViewings, loc, ok := c.getUpcomingViewings(ctx, userID, chatID)
+	if !ok {
+		return
+	}
+
+	msgTxt := "🗓Upcoming viewings, use /export_viewings to get them as a calendar file:"
+	for idx := range upcomingViewings {
+		addMsgTxt := "\n\n" + FormatViewing(&upcomingViewings[idx], loc) + "\n🆔" + upcomingViewings[idx].ObjectID
+		if utf8.RuneCountInString(msgTxt+addMsgTxt) > messageMaxCharLen {
+			c.sendMessage(chatID, userID, msgTxt, false)
+			msgTxt = ""
+		}
+		msgTxt += addMsgTxt
+	}
+	c.sendMessage(chatID, userID, msgTxt, false)
+}
+
+func (c *TelegramBotCommands) ExportViewings(ctx context.Context, userID string, chatID int64) {
+	upcomingViewings, _, ok := c.getUpcomingViewings(ctx, userID, chatID)
+	if !ok {
+		return
+	}
+
+	doc := tgbotapi.NewDocument(chatID, tgbotapi.FileBytes{Name: viewingsFileName, Bytes: upcomingViewings.ICS(time.Now())})
+	doc.Caption = fmt.Sprintf("🗓%d upcoming viewing(s), open the file to import them into your calendar", len(upcomingViewings))
+	if _, err := c.bot.Send(doc); err != nil {
+		c.log.Error().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to send document to")
+	}
+}
+
+// FormatViewing renders a viewing with its time in the given location.
+func FormatViewing(viewing *viewings.Viewing, loc *time.Location) string {
+	msgTxt := fmt.Sprintf("🏠%s\n🕒%s", viewing.Name, viewing.StartsAt.In(loc).Format(viewingLayout))
+	if viewing.Address != "" {
+		msgTxt += "\n📍" + viewing.Address
+	}
+	return msgTxt + "\n" + viewing.URL
+}
+
+func (c *TelegramBotCommands) getUpcomingViewings(ctx context.Context, userID string, chatID int64) (viewings.Viewings, *time.Location, bool) {
+	session, err := c.sessionsService.GetSessionByUserID(ctx, userID)
+	if err != nil {
+		c.log.Error().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to get session details")
+		msgTxt := "💥Failed to get your session details"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return nil, nil, false
+	}
+
+	upcomingViewings, err := c.viewingsService.MGetUpcomingViewing(ctx, userID)
+	if err != nil {
+		c.log.Error().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to get viewings")
+		msgTxt := "💥Failed to get viewings"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return nil, nil, false
+	}
+	if len(upcomingViewings) == 0 {
+		msgTxt := "🤷No upcoming viewings, schedule one with /schedule_viewing"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return nil, nil, false
+	}
+
+	return upcomingViewings, session.Location(), true
+}
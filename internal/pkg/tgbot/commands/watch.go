@@ -0,0 +1,54 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"fundaNotifier/internal/domain/listings"
+	"fundaNotifier/internal/domain/watched_listings"
+	"fundaNotifier/internal/pkg/funda"
+	"net/url"
+	"strings"
+)
+
+func (c *TelegramBotCommands) Watch(ctx context.Context, userID string, chatID int64, listingURL string) {
+	listingURL = strings.TrimSpace(listingURL)
+	if !validateListingURL(listingURL) {
+		msgTxt := "⚠️Usage: /watch <URL>, the URL must be a funda.nl listing page copied directly from browser"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	watchedListing, err := c.watchedListingsService.Watch(ctx, userID, listingURL)
+	if err != nil {
+		if listings.IsGone(err) {
+			msgTxt := "🤷This listing is not available on Funda anymore"
+			c.sendMessage(chatID, userID, msgTxt, false)
+			return
+		}
+		c.log.Error().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to watch listing")
+		msgTxt := "💥Failed to watch listing"
+		c.sendMessage(chatID, userID, msgTxt, false)
+		return
+	}
+
+	msgTxt := "✅Watching listing, you will be notified upon price or status changes and its disappearance\n\n" + formatWatchedListing(watchedListing)
+	c.sendMessage(chatID, userID, msgTxt, false)
+}
+
+// FormatWatchedListingChange renders a notification on what has happened to a watched listing.
+func formatWatchedListing(watchedListing *watched_listings.WatchedListing) string {
+	status := watchedListing.Status
+	if watchedListing.IsGone {
+		status = "👻not on Funda anymore"
+	}
+	return fmt.Sprintf("🏠%s, %s\n💶%.0f %s, %s\n🆔%s\n%s", watchedListing.Name, watchedListing.City, watchedListing.Price, watchedListing.Currency, status, watchedListing.ObjectID, watchedListing.URL)
+}
+
+func validateListingURL(str string) bool {
+	parsedURL, err := url.Parse(str)
+	if err != nil || (parsedURL.Hostname() != "funda.nl" && !strings.HasSuffix(parsedURL.Hostname(), ".funda.nl")) {
+		return false
+	}
+	_, err = funda.ObjectID(str)
+	return err == nil
+}
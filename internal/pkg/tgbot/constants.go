@@ -5,6 +5,7 @@ import "time"
 const (
 	workerTickerInterval              = 10 * time.Second
 	nSessionsWithForcedMessageSending = 3
+	messageMaxCharLen                 = 4096
 	disabledButtonCallbackData        = "sheDoneAlreadyDoneHadHerses"
 	lisenceWorldMap                   = "Distributed by https://simplemaps.com/data/world-cities under CC BY 4.0 https://creativecommons.org/licenses/by/4.0/"
 )
@@ -0,0 +1,157 @@
+package tgbot
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"fundaNotifier/internal/domain/listings"
+	"fundaNotifier/internal/domain/sessions"
+	"fundaNotifier/internal/domain/urgent_rules"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+func (b *TelegramBot) isWithinDND(ctx context.Context, session *sessions.Session) (bool, error) {
+	if !session.DNDActive {
+		return false, nil
+	}
+
+	isWithinDND, err := b.dndWindowsService.IsWithinDND(ctx, session.UserID, time.Now().In(session.Location()))
+	if err != nil {
+		b.log.Error().Err(err).Str("userID", session.UserID).Msg("failed to evaluate DND windows")
+		return false, err
+	}
+
+	return isWithinDND, nil
+}
+
+// delivery tells how notifications of a sync reach the user.
+type delivery int
+
+const (
+	deliverNow delivery = iota
+	// deliverHeld keeps notifications in the outbox until the DND window ends
+	deliverHeld
+	// deliverUrgent sends only urgent alerts right away, within DND in skip mode, sync messages are held like with
+	// deliverHeld since the listings they report are stored as known and would not be reported again once DND ends
+	deliverUrgent
+)
+
+// notify sends a plain-text message right away or holds it in the outbox until the DND window ends.
+func (b *TelegramBot) notify(ctx context.Context, session *sessions.Session, msgTxt string, delivery delivery) {
+	if delivery == deliverNow {
+		_ = b.sendMessage(session.ChatID, session.UserID, msgTxt, false)
+		return
+	}
+
+	if err := b.outboxService.HoldMessage(ctx, session.UserID, msgTxt); err != nil {
+		b.log.Error().Err(err).Str("userID", session.UserID).Msg("failed to hold message, sending it right away")
+		_ = b.sendMessage(session.ChatID, session.UserID, msgTxt, false)
+	}
+}
+
+func (b *TelegramBot) deliverHeldMessages(ctx context.Context, session *sessions.Session) {
+	heldMessages, err := b.outboxService.MGetMessageByUserID(ctx, session.UserID)
+	if err != nil || len(heldMessages) == 0 {
+		return
+	}
+
+	// held messages are delivered in chunks, each chunk is removed from the outbox as soon as it is sent so that a
+	// failure halfway through does not lose messages, a chunk which is sent but fails to be removed is sent again upon
+	// the next delivery
+	msgTxt := fmt.Sprintf("🌅DND is over, %d notification(s) were held:", len(heldMessages))
+	// a message too long to fit into a chunk on its own would fail to be sent upon every delivery, so it is truncated
+	maxLen := messageMaxCharLen - utf8.RuneCountInString(msgTxt) - 2
+	chunkIDs := make([]int64, 0, len(heldMessages))
+	for idx := range heldMessages {
+		addMsgTxt := "\n\n" + truncateText(strings.TrimSpace(heldMessages[idx].Text), maxLen)
+		if len(chunkIDs) != 0 && utf8.RuneCountInString(msgTxt+addMsgTxt) > messageMaxCharLen {
+			if !b.deliverHeldChunk(ctx, session, msgTxt, chunkIDs) {
+				return
+			}
+			msgTxt = ""
+			chunkIDs = chunkIDs[:0]
+		}
+		msgTxt += addMsgTxt
+		chunkIDs = append(chunkIDs, heldMessages[idx].ID)
+	}
+	b.deliverHeldChunk(ctx, session, msgTxt, chunkIDs)
+}
+
+// deliverHeldChunk sends a chunk of held messages and removes them from the outbox, it reports whether both succeeded.
+func (b *TelegramBot) deliverHeldChunk(ctx context.Context, session *sessions.Session, msgTxt string, IDs []int64) bool {
+	if err := b.sendMessage(session.ChatID, session.UserID, msgTxt, false); err != nil {
+		return false
+	}
+
+	if err := b.outboxService.MDeleteMessageByUserIDAndIDs(ctx, session.UserID, IDs); err != nil {
+		b.log.Error().Err(err).Str("userID", session.UserID).Msg("failed to delete delivered messages")
+		return false
+	}
+	return true
+}
+
+// truncateText cuts text down to maxLen runes, marking the cut with an ellipsis.
+func truncateText(text string, maxLen int) string {
+	if utf8.RuneCountInString(text) <= maxLen {
+		return text
+	}
+	return string([]rune(text)[:maxLen-1]) + "…"
+}
+
+func (b *TelegramBot) getUrgentRule(ctx context.Context, session *sessions.Session) *urgent_rules.Rule {
+	rule, err := b.urgentRulesService.GetRuleByUserID(ctx, session.UserID)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			b.log.Error().Err(err).Str("userID", session.UserID).Msg("failed to get urgent rule for sync")
+		}
+		return nil
+	}
+
+	return rule
+}
+
+// sendUrgentAlerts sends listings matching the urgent rule right away, regardless of DND, within the daily cap.
+func (b *TelegramBot) sendUrgentAlerts(ctx context.Context, session *sessions.Session, rule *urgent_rules.Rule, addedListings listings.Listings) {
+	urgentListings := rule.Filter(addedListings)
+	if len(urgentListings) == 0 {
+		return
+	}
+	urgentListings.Sort()
+
+	day := urgent_rules.Day(time.Now().In(session.Location()))
+	remaining := rule.RemainingOn(day)
+	if remaining == 0 {
+		b.log.Info().Str("userID", session.UserID).Int("number_of_listings", len(urgentListings)).Msg("urgent alerts daily cap is reached")
+		return
+	}
+
+	var skipped int
+	if len(urgentListings) > remaining {
+		skipped = len(urgentListings) - remaining
+		urgentListings = urgentListings[:remaining]
+	}
+
+	var sent int
+	for idx := range urgentListings {
+		msgTxt := fmt.Sprintf("🚨*URGENT MATCH*🚨\n🏠[%.0f %s %s](%s)\n%s, %s, %s", urgentListings[idx].Offers.Price, urgentListings[idx].Offers.PriceCurrency, escapeMarkdownV2(urgentListings[idx].Name), escapeMarkdownV2(urgentListings[idx].URL), escapeMarkdownV2(urgentListings[idx].Address.AddressRegion), escapeMarkdownV2(urgentListings[idx].Address.AddressLocality), escapeMarkdownV2(urgentListings[idx].Address.StreetAddress))
+		if err := b.sendMessage(session.ChatID, session.UserID, msgTxt, true); err != nil {
+			continue
+		}
+		sent++
+	}
+	if sent == 0 {
+		return
+	}
+
+	if err := b.urgentRulesService.RegisterSentAlerts(ctx, rule, day, sent); err != nil {
+		return
+	}
+
+	if skipped > 0 {
+		msgTxt := fmt.Sprintf("🚨Daily cap of %d urgent alerts is reached, %d more urgent match(es) will only show up in regular notifications", rule.DailyCap, skipped)
+		_ = b.sendMessage(session.ChatID, session.UserID, msgTxt, false)
+	}
+}
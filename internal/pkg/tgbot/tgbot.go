@@ -3,9 +3,17 @@ package tgbot
 import (
 	"context"
 	"fmt"
+	"fundaNotifier/internal/domain/blacklist"
+	"fundaNotifier/internal/domain/dnd_windows"
 	"fundaNotifier/internal/domain/listings"
+	"fundaNotifier/internal/domain/outbox"
+	"fundaNotifier/internal/domain/preferences"
+	"fundaNotifier/internal/domain/scoring"
 	"fundaNotifier/internal/domain/search_queries"
 	"fundaNotifier/internal/domain/sessions"
+	"fundaNotifier/internal/domain/urgent_rules"
+	"fundaNotifier/internal/domain/viewings"
+	"fundaNotifier/internal/domain/watched_listings"
 	"fundaNotifier/internal/pkg/geo"
 	"fundaNotifier/internal/pkg/tgbot/commands"
 	"strings"
@@ -22,24 +30,67 @@ func defineCommands() tgbotapi.SetMyCommandsConfig {
 		{Command: "run", Description: "Start scheduled API polling"},
 		{Command: "pause", Description: "Pause scheduled API polling"},
 		{Command: "stop", Description: "Stop the bot, remove your data and everything"},
-		{Command: "set_search_query", Description: "Set search query (resets the database and current settings)"},
+		{Command: "set_search_query", Description: "Set the default search query"},
+		{Command: "show_search_query", Description: "Show a readable summary of search queries with warnings about unknown or conflicting parameters, optionally followed by a query name"},
+		{Command: "preview_query", Description: "Dry-run a search URL or a stored query by name: shows the number of matches, first listings and estimated sync duration without saving anything"},
+		{Command: "build_query", Description: "Build a search query step by step instead of pasting a URL, optionally followed by a query name (`default` if omitted)"},
+		{Command: "add_query", Description: "Add or replace a named search query as name and URL (e.g. `/add_query utrecht_rent <URL>`)"},
+		{Command: "list_queries", Description: "Show all search queries with their state and filters"},
+		{Command: "remove_query", Description: "Remove a search query and its listings by name"},
+		{Command: "query_history", Description: "Show previous versions of a search query with the number of listings each has found, optionally followed by a query name"},
+		{Command: "restore_query", Description: "Go back to an earlier version of a search query as an optional query name and a version number (e.g. `/restore_query 2`)"},
+		{Command: "pause_query", Description: "Pause API polling of a search query by name"},
+		{Command: "resume_query", Description: "Resume API polling of a search query by name"},
+		{Command: "set_query_filters", Description: "Set regions and cities of a search query (e.g. `/set_query_filters utrecht_rent cities=utrecht`), reset if invoked with a name only"},
 		{Command: "set_polling_interval", Description: "Set polling interval (e.g. `1000s`, `3m` `1h`, `1.5h`, `2h30m15s`, minimal value is 900s)"},
+		{Command: "set_schedule", Description: "Set cron-style polling schedule in your timezone (e.g. `*/20 7-23 * * 1-5` or a preset like `aggressive mornings`), overrides polling interval, reset if invoked without message"},
 		{Command: "set_regions", Description: "Set regions (comma-separated, case-insensitive) or reset (if invoked without message)"},
 		{Command: "set_cities", Description: "Set cities (comma-separated, case-insensitive) or reset (if invoked without message)"},
 		{Command: "add_region", Description: "Add one region (case-insensitive)"},
 		{Command: "add_city", Description: "Add one city (case-insensitive)"},
 		{Command: "show_active_filters", Description: "Show currently set regions and cities"},
-		{Command: "show_polling_interval", Description: "Show currently set polling interval"},
+		{Command: "show_polling_interval", Description: "Show currently set polling interval or schedule"},
 		{Command: "update_now", Description: "Trigger manual update"},
 		{Command: "show_current_listings", Description: "Show all currently stored listings"},
 		{Command: "tap_current_listings", Description: "Show all currently stored listings with an option to save any of them as favorites"},
-		{Command: "show_new_listings", Description: "Show all newly added listings"},
-		{Command: "tap_new_listings", Description: "Show all newly added listings with an option to save any of them as favorites"},
-		{Command: "show_favorites", Description: "Show all favorite listings"},
-		{Command: "dnd_set_schedule", Description: "Set DND interval in UTC as start and end HH:MM (e.g. `/set_dnd_period 23:00,08:00`), DND means that API polling will be paused during the set interval if DND is turned on"},
-		{Command: "dnd_show_schedule", Description: "Show DND schedule"},
+		{Command: "next", Description: "Show the oldest listing you have not seen yet with buttons to save, hide or mark it as seen"},
+		{Command: "mark_all_seen", Description: "Mark all currently unseen listings as seen"},
+		{Command: "browse", Description: "Browse stored listings one by one in a single message with sorting by date, price, area, score or match probability and filters"},
+		{Command: "listing", Description: "Show everything stored about a listing by its ID or URL, including photos, price history and favorite status"},
+		{Command: "search", Description: "Search stored listings by name, description and address (e.g. `/search balkon centrum`)"},
+		{Command: "show_scoring", Description: "Show your scoring profile which ranks and labels listings"},
+		{Command: "set_weight", Description: "Weight a scoring criterion from 0 to 10 (e.g. `/set_weight price 5`)"},
+		{Command: "set_keywords", Description: "Set keywords scored in listing descriptions (e.g. `/set_keywords balkon, tuin`)"},
+		{Command: "add_poi", Description: "Add a point of interest to score the distance to (e.g. `/add_poi work 52.0907,5.1214`)"},
+		{Command: "remove_poi", Description: "Remove a point of interest by its name"},
+		{Command: "reset_scoring", Description: "Remove your scoring profile, listings are not scored anymore"},
+		{Command: "preferences", Description: "Show what the bot learned from your 👍/👎 feedback on listing cards"},
+		{Command: "set_match_threshold", Description: "Do not notify about new listings with a lower match probability in percent, 0 turns it off"},
+		{Command: "reset_preferences", Description: "Forget all 👍/👎 feedback and learn your preferences from scratch"},
+		{Command: "show_new_listings", Description: "Show all listings you have not seen yet"},
+		{Command: "tap_new_listings", Description: "Show all listings you have not seen yet with an option to save any of them as favorites"},
+		{Command: "show_favorites", Description: "Show favorite listings to reorder, annotate or remove them, as well as watched listings"},
+		{Command: "pipeline", Description: "Show favorites by pipeline stage (interested, contacted, viewing scheduled, applied/bid, rejected, won)"},
+		{Command: "schedule_viewing", Description: "Schedule a viewing of a favorite in your timezone with an optional reminder offset (e.g. `/schedule_viewing 43123456 2024-10-21 18:30 remind=1h`)"},
+		{Command: "cancel_viewing", Description: "Cancel a viewing by listing ID"},
+		{Command: "show_viewings", Description: "Show upcoming viewings"},
+		{Command: "export_viewings", Description: "Send upcoming viewings as an .ics calendar file"},
+		{Command: "rank_favorite", Description: "Move a favorite listing to the given position of your personal ranking"},
+		{Command: "watch", Description: "Watch a single listing by its URL regardless of search queries, notifies upon price or status changes and its disappearance"},
+		{Command: "unwatch", Description: "Stop watching a listing by its URL or ID"},
+		{Command: "blacklist", Description: "Hide listings by `street`, `postcode`, `keyword` or `agent` (e.g. `/blacklist postcode 1012`)"},
+		{Command: "unblacklist", Description: "Remove a blacklist rule by its kind and value"},
+		{Command: "show_hidden", Description: "Show blacklist rules and hidden listings with an option to show any of them again"},
+		{Command: "set_timezone", Description: "Set your timezone (IANA name, e.g. `Europe/Amsterdam`) used for schedules, DND and timestamps, reset to UTC if invoked without message"},
+		{Command: "dnd_add_window", Description: "Add or replace a named DND window in your timezone as name, optional weekdays and HH:MM-HH:MM (e.g. `/dnd_add_window nights mon-fri 23:00-07:00`), API polling is paused within DND windows if DND is turned on"},
+		{Command: "dnd_show_schedule", Description: "Show DND windows and status"},
+		{Command: "dnd_remove_window", Description: "Remove a DND window by name"},
+		{Command: "dnd_set_mode", Description: "Set DND mode: `skip` pauses API polling within DND windows (default), `queue` keeps polling and holds notifications until the DND window ends"},
 		{Command: "dnd_activate", Description: "Turn on DND"},
 		{Command: "dnd_deactivate", Description: "Turn off DND"},
+		{Command: "set_urgent_rule", Description: "Set urgent rule delivering matches right away even within DND (e.g. `max_price=1400; cities=utrecht; cap=3`)"},
+		{Command: "show_urgent_rule", Description: "Show urgent rule"},
+		{Command: "remove_urgent_rule", Description: "Remove urgent rule"},
 		{Command: "show_locations", Description: "Show a prompt with a list of major cities by their corresponding region"},
 		{Command: "help", Description: "Show help"},
 	}
@@ -47,14 +98,23 @@ func defineCommands() tgbotapi.SetMyCommandsConfig {
 }
 
 type TelegramBot struct {
-	log                  *zerolog.Logger
-	bot                  *tgbotapi.BotAPI
-	cfg                  *Config
-	commands             *commands.TelegramBotCommands
-	listingsService      *listings.Service
-	sessionsService      *sessions.Service
-	searchQueriesService *search_queries.Service
-	cityData             *geo.CityData
+	log                    *zerolog.Logger
+	bot                    *tgbotapi.BotAPI
+	cfg                    *Config
+	commands               *commands.TelegramBotCommands
+	listingsService        *listings.Service
+	sessionsService        *sessions.Service
+	searchQueriesService   *search_queries.Service
+	dndWindowsService      *dnd_windows.Service
+	outboxService          *outbox.Service
+	urgentRulesService     *urgent_rules.Service
+	watchedListingsService *watched_listings.Service
+	blacklistService       *blacklist.Service
+	viewingsService        *viewings.Service
+	scoringService         *scoring.Service
+	preferencesService     *preferences.Service
+	cityData               *geo.CityData
+	callbackHandlers       map[string]callbackHandler
 }
 
 func NewTelegramBot(
@@ -63,6 +123,14 @@ func NewTelegramBot(
 	listingsService *listings.Service,
 	sessionsService *sessions.Service,
 	searchQueriesService *search_queries.Service,
+	dndWindowsService *dnd_windows.Service,
+	outboxService *outbox.Service,
+	urgentRulesService *urgent_rules.Service,
+	watchedListingsService *watched_listings.Service,
+	blacklistService *blacklist.Service,
+	viewingsService *viewings.Service,
+	scoringService *scoring.Service,
+	preferencesService *preferences.Service,
 ) *TelegramBot {
 	log.Info().Msg("initializing telegram bot instance")
 
@@ -78,18 +146,28 @@ func NewTelegramBot(
 	}
 
 	log.Info().Str("account", bot.Self.UserName).Msg("telegram bot was authorized as")
-	return &TelegramBot{
-		cfg:                  cfg,
-		log:                  log,
-		bot:                  bot,
-		commands:             commands.NewTelegramBotCommands(log, bot, listingsService, sessionsService, searchQueriesService, geo.NewCityData()),
-		listingsService:      listingsService,
-		sessionsService:      sessionsService,
-		searchQueriesService: searchQueriesService,
+	b := &TelegramBot{
+		cfg:                    cfg,
+		log:                    log,
+		bot:                    bot,
+		commands:               commands.NewTelegramBotCommands(log, bot, listingsService, sessionsService, searchQueriesService, dndWindowsService, urgentRulesService, watchedListingsService, blacklistService, viewingsService, scoringService, preferencesService, geo.NewCityData()),
+		listingsService:        listingsService,
+		sessionsService:        sessionsService,
+		searchQueriesService:   searchQueriesService,
+		dndWindowsService:      dndWindowsService,
+		outboxService:          outboxService,
+		urgentRulesService:     urgentRulesService,
+		watchedListingsService: watchedListingsService,
+		blacklistService:       blacklistService,
+		viewingsService:        viewingsService,
+		scoringService:         scoringService,
+		preferencesService:     preferencesService,
 	}
+	b.callbackHandlers = b.newCallbackHandlers()
+	return b
 }
 
-func (b *TelegramBot) sendMessage(chatID int64, userID, message string, md2 bool) {
+func (b *TelegramBot) sendMessage(chatID int64, userID, message string, md2 bool) error {
 	msg := tgbotapi.NewMessage(chatID, message)
 	msg.DisableWebPagePreview = true
 	if md2 {
@@ -99,6 +177,7 @@ func (b *TelegramBot) sendMessage(chatID int64, userID, message string, md2 bool
 	if err != nil {
 		b.log.Error().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to send message to")
 	}
+	return err
 }
 
 func (b *TelegramBot) Begin(ctx context.Context, wg *sync.WaitGroup) error {
@@ -124,6 +203,8 @@ func (b *TelegramBot) Begin(ctx context.Context, wg *sync.WaitGroup) error {
 				default:
 					break
 				}
+			} else if update.Message != nil && update.Message.Text != "" {
+				b.updateTextHandler(ctx, update)
 			} else {
 				continue
 			}
@@ -134,68 +215,22 @@ func (b *TelegramBot) Begin(ctx context.Context, wg *sync.WaitGroup) error {
 	}
 }
 
-func (b *TelegramBot) updateCallbackHandler(ctx context.Context, update tgbotapi.Update) {
-	user := update.CallbackQuery.From
-	chatID := update.CallbackQuery.Message.Chat.ID
-	msgID := update.CallbackQuery.Message.MessageID
-	b.log.Debug().Str("userID", user.UserName).Int64("chatID", chatID).Msg("received callback from")
+// updateTextHandler routes plain-text messages to flows awaiting free-text input.
+func (b *TelegramBot) updateTextHandler(ctx context.Context, update tgbotapi.Update) {
+	user := update.Message.From
+	chatID := update.Message.Chat.ID
 
-	// check that user is in whitelist
 	if !b.isAuthorizedUser(user.UserName, chatID) {
-		b.reactToCallbackError(user.UserName, chatID, update)
 		return
 	}
 
-	if update.CallbackQuery.Data == disabledButtonCallbackData {
-		b.reactToCallbackError(user.UserName, chatID, update)
-		return
-	}
-
-	listing, err := b.listingsService.GetListingByUUID(ctx, update.CallbackQuery.Data)
-	if err != nil {
-		b.log.Error().Err(err).Str("userID", user.UserName).Msg("failed to get a listing inside a callback query")
-		msgTxt := "💥Failed to get a listing inside a callback query"
-		b.sendMessage(chatID, user.UserName, msgTxt, false)
-		b.reactToCallbackError(user.UserName, chatID, update)
+	if update.Message.ReplyToMessage != nil && b.commands.HandleFavoriteNoteReply(ctx, user.UserName, chatID, update.Message.ReplyToMessage, update.Message.Text) {
 		return
 	}
-
-	err = b.listingsService.AddFavoriteListing(ctx, listing)
-	if err != nil {
-		b.log.Error().Err(err).Str("userID", user.UserName).Msg("failed to add a favorite listing")
-		msgTxt := "💥Failed to add a favorite listing"
-		b.sendMessage(chatID, user.UserName, msgTxt, false)
-		b.reactToCallbackError(user.UserName, chatID, update)
+	if b.commands.HandleQueryBuilderText(user.UserName, chatID, update.Message.Text) {
 		return
 	}
-
-	b.reactToCallback(user.UserName, chatID, msgID, update)
-}
-
-func (b *TelegramBot) reactToCallbackError(userID string, chatID int64, update tgbotapi.Update) {
-	_, err := b.bot.Request(tgbotapi.NewCallback(update.CallbackQuery.ID, "💥either an error or you have already added it to favorites"))
-	if err != nil {
-		b.log.Error().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to send callback call to")
-	}
-}
-
-func (b *TelegramBot) reactToCallback(userID string, chatID int64, msgID int, update tgbotapi.Update) {
-	_, err := b.bot.Request(tgbotapi.NewCallback(update.CallbackQuery.ID, "✅"))
-	if err != nil {
-		b.log.Error().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to send callback call to")
-	}
-
-	updatedKeyboard := tgbotapi.NewInlineKeyboardMarkup(
-		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("💚", disabledButtonCallbackData),
-		),
-	)
-
-	// Edit the message to update the button
-	edit := tgbotapi.NewEditMessageReplyMarkup(chatID, msgID, updatedKeyboard)
-	if _, err = b.bot.Request(edit); err != nil {
-		b.log.Error().Err(err).Str("userID", userID).Int64("chatID", chatID).Msg("failed to update button")
-	}
+	b.log.Debug().Str("userID", user.UserName).Int64("chatID", chatID).Msg("ignoring plain-text message from")
 }
 
 func (b *TelegramBot) updateCommandHandler(ctx context.Context, update tgbotapi.Update) {
@@ -237,6 +272,12 @@ func (b *TelegramBot) updateCommandHandler(ctx context.Context, update tgbotapi.
 		case "set_polling_interval":
 			b.commands.SetPollingInterval(ctx, user.UserName, chatID, update.Message.CommandArguments())
 
+		case "set_schedule":
+			b.commands.SetSchedule(ctx, user.UserName, chatID, update.Message.CommandArguments())
+
+		case "set_timezone":
+			b.commands.SetTimezone(ctx, user.UserName, chatID, update.Message.CommandArguments())
+
 		case "show_polling_interval":
 			b.commands.ShowPollingInterval(ctx, user.UserName, chatID)
 
@@ -258,6 +299,39 @@ func (b *TelegramBot) updateCommandHandler(ctx context.Context, update tgbotapi.
 		case "set_search_query":
 			b.commands.SetSearchQuery(ctx, user.UserName, chatID, update.Message.CommandArguments())
 
+		case "show_search_query":
+			b.commands.ShowSearchQuery(ctx, user.UserName, chatID, update.Message.CommandArguments())
+
+		case "preview_query":
+			b.commands.PreviewQuery(ctx, user.UserName, chatID, update.Message.CommandArguments())
+
+		case "build_query":
+			b.commands.BuildQuery(ctx, user.UserName, chatID, update.Message.CommandArguments())
+
+		case "add_query":
+			b.commands.AddQuery(ctx, user.UserName, chatID, update.Message.CommandArguments())
+
+		case "list_queries":
+			b.commands.ListQueries(ctx, user.UserName, chatID)
+
+		case "remove_query":
+			b.commands.RemoveQuery(ctx, user.UserName, chatID, update.Message.CommandArguments())
+
+		case "query_history":
+			b.commands.QueryHistory(ctx, user.UserName, chatID, update.Message.CommandArguments())
+
+		case "restore_query":
+			b.commands.RestoreQuery(ctx, user.UserName, chatID, update.Message.CommandArguments())
+
+		case "pause_query":
+			b.commands.PauseQuery(ctx, user.UserName, chatID, update.Message.CommandArguments())
+
+		case "resume_query":
+			b.commands.ResumeQuery(ctx, user.UserName, chatID, update.Message.CommandArguments())
+
+		case "set_query_filters":
+			b.commands.SetQueryFilters(ctx, user.UserName, chatID, update.Message.CommandArguments())
+
 		case "show_current_listings":
 			b.commands.ShowCurrentListings(ctx, user.UserName, chatID)
 
@@ -267,27 +341,117 @@ func (b *TelegramBot) updateCommandHandler(ctx context.Context, update tgbotapi.
 		case "show_new_listings":
 			b.commands.ShowNewListings(ctx, user.UserName, chatID)
 
+		case "next":
+			b.commands.Next(ctx, user.UserName, chatID)
+
+		case "mark_all_seen":
+			b.commands.MarkAllSeen(ctx, user.UserName, chatID)
+
+		case "browse":
+			b.commands.Browse(ctx, user.UserName, chatID)
+
+		case "listing":
+			b.commands.ShowListing(ctx, user.UserName, chatID, update.Message.CommandArguments())
+
+		case "search":
+			b.commands.Search(ctx, user.UserName, chatID, update.Message.CommandArguments())
+
+		case "show_scoring":
+			b.commands.ShowScoring(ctx, user.UserName, chatID)
+
+		case "set_weight":
+			b.commands.SetWeight(ctx, user.UserName, chatID, update.Message.CommandArguments())
+
+		case "set_keywords":
+			b.commands.SetScoringKeywords(ctx, user.UserName, chatID, update.Message.CommandArguments())
+
+		case "add_poi":
+			b.commands.AddPointOfInterest(ctx, user.UserName, chatID, update.Message.CommandArguments())
+
+		case "remove_poi":
+			b.commands.RemovePointOfInterest(ctx, user.UserName, chatID, update.Message.CommandArguments())
+
+		case "reset_scoring":
+			b.commands.ResetScoring(ctx, user.UserName, chatID)
+
+		case "preferences":
+			b.commands.ShowPreferences(ctx, user.UserName, chatID)
+
+		case "set_match_threshold":
+			b.commands.SetMatchThreshold(ctx, user.UserName, chatID, update.Message.CommandArguments())
+
+		case "reset_preferences":
+			b.commands.ResetPreferences(ctx, user.UserName, chatID)
+
 		case "tap_new_listings":
 			b.commands.TapNewListings(ctx, user.UserName, chatID)
 
 		case "show_favorites":
 			b.commands.ShowFavorites(ctx, user.UserName, chatID)
 
+		case "pipeline":
+			b.commands.Pipeline(ctx, user.UserName, chatID)
+
+		case "schedule_viewing":
+			b.commands.ScheduleViewing(ctx, user.UserName, chatID, update.Message.CommandArguments())
+
+		case "cancel_viewing":
+			b.commands.CancelViewing(ctx, user.UserName, chatID, update.Message.CommandArguments())
+
+		case "show_viewings":
+			b.commands.ShowViewings(ctx, user.UserName, chatID)
+
+		case "export_viewings":
+			b.commands.ExportViewings(ctx, user.UserName, chatID)
+
+		case "rank_favorite":
+			b.commands.RankFavorite(ctx, user.UserName, chatID, update.Message.CommandArguments())
+
+		case "watch":
+			b.commands.Watch(ctx, user.UserName, chatID, update.Message.CommandArguments())
+
+		case "unwatch":
+			b.commands.Unwatch(ctx, user.UserName, chatID, update.Message.CommandArguments())
+
+		case "blacklist":
+			b.commands.Blacklist(ctx, user.UserName, chatID, update.Message.CommandArguments())
+
+		case "unblacklist":
+			b.commands.Unblacklist(ctx, user.UserName, chatID, update.Message.CommandArguments())
+
+		case "show_hidden":
+			b.commands.ShowHidden(ctx, user.UserName, chatID)
+
 		case "update_now":
 			b.commands.UpdateNow(ctx, user.UserName, chatID)
 
-		case "dnd_set_schedule":
-			b.commands.SetDNDSchedule(ctx, user.UserName, chatID, update.Message.CommandArguments())
+		case "dnd_add_window":
+			b.commands.AddDNDWindow(ctx, user.UserName, chatID, update.Message.CommandArguments())
 
 		case "dnd_show_schedule":
 			b.commands.ShowDNDSchedule(ctx, user.UserName, chatID)
 
+		case "dnd_remove_window":
+			b.commands.RemoveDNDWindow(ctx, user.UserName, chatID, update.Message.CommandArguments())
+
+		case "dnd_set_mode":
+			b.commands.SetDNDMode(ctx, user.UserName, chatID, update.Message.CommandArguments())
+
 		case "dnd_activate":
 			b.commands.ActivateDND(ctx, user.UserName, chatID)
 
 		case "dnd_deactivate":
 			b.commands.DeactivateDND(ctx, user.UserName, chatID)
 
+		case "set_urgent_rule":
+			b.commands.SetUrgentRule(ctx, user.UserName, chatID, update.Message.CommandArguments())
+
+		case "show_urgent_rule":
+			b.commands.ShowUrgentRule(ctx, user.UserName, chatID)
+
+		case "remove_urgent_rule":
+			b.commands.RemoveUrgentRule(ctx, user.UserName, chatID)
+
 		case "show_locations":
 			b.commands.ShowLocations(ctx, user.UserName, chatID)
 
@@ -321,52 +485,176 @@ func (b *TelegramBot) syncer(ctx context.Context) {
 		b.log.Error().Err(err).Msg("failed to fetch sessions for sync")
 		return
 	}
+
+	for idx := range activeSessions {
+		isWithinDND, errDND := b.isWithinDND(ctx, &activeSessions[idx])
+		if errDND != nil || isWithinDND {
+			continue
+		}
+		b.deliverHeldMessages(ctx, &activeSessions[idx])
+	}
+
+	// viewing reminders are time-critical, they are sent regardless of DND
+	for idx := range activeSessions {
+		b.remindViewings(ctx, &activeSessions[idx])
+	}
+
 	sessionsForSync := activeSessions.SelectForSync()
 	b.log.Info().Int("number_of_sessions", len(sessionsForSync)).Msg("attempting to sync sessions")
 
 	for idx := range sessionsForSync {
-		if sessionsForSync[idx].IsWithinDND() {
+		isWithinDND, errDND := b.isWithinDND(ctx, &sessionsForSync[idx])
+		if errDND != nil {
 			continue
 		}
+		// sessions with an urgent rule keep polling within DND so that urgent matches are not missed
+		urgentRule := b.getUrgentRule(ctx, &sessionsForSync[idx])
+		delivery := deliverNow
+		if isWithinDND {
+			switch {
+			case sessionsForSync[idx].DNDQueue:
+				delivery = deliverHeld
+			case urgentRule != nil:
+				delivery = deliverUrgent
+			default:
+				continue
+			}
+		}
 		var forceSendMessage bool
 		if sessionsForSync[idx].SyncCountSinceLastChange <= nSessionsWithForcedMessageSending {
 			forceSendMessage = true
 		}
-		b.syncerIteration(ctx, &sessionsForSync[idx], forceSendMessage)
+		b.syncSession(ctx, &sessionsForSync[idx], forceSendMessage, delivery, urgentRule)
 	}
 }
 
-func (b *TelegramBot) syncerIteration(ctx context.Context, session *sessions.Session, forceSendMessage bool) {
-	searchQuery, err := b.searchQueriesService.GetSearchQuery(ctx, session.UserID)
+func (b *TelegramBot) syncSession(ctx context.Context, session *sessions.Session, forceSendMessage bool, delivery delivery, urgentRule *urgent_rules.Rule) {
+	searchQueries, err := b.searchQueriesService.MGetSearchQueryByUserID(ctx, session.UserID)
 	if err != nil {
-		b.log.Error().Err(err).Str("userID", session.UserID).Msg("failed to get search query for sync")
-		msgTxt := fmt.Sprintf("📅Updated at %s\n💥failed to get listings updates", time.Now().Format(time.RFC3339))
-		b.sendMessage(session.ChatID, session.UserID, msgTxt, false)
+		b.log.Error().Err(err).Str("userID", session.UserID).Msg("failed to get search queries for sync")
+		msgTxt := fmt.Sprintf("📅Updated at %s\n💥failed to get listings updates", time.Now().In(session.Location()).Format(time.RFC3339))
+		b.notify(ctx, session, msgTxt, delivery)
 		return
 	}
 
 	err = b.sessionsService.UpdateLastSyncedAt(ctx, session.UserID, time.Now())
 	if err != nil {
 		b.log.Error().Err(err).Str("userID", session.UserID).Msg("failed to update last sync timestamp")
-		msgTxt := fmt.Sprintf("📅Updated at %s\n💥failed to update last sync timestamp", time.Now().Format(time.RFC3339))
-		b.sendMessage(session.ChatID, session.UserID, msgTxt, false)
+		msgTxt := fmt.Sprintf("📅Updated at %s\n💥failed to update last sync timestamp", time.Now().In(session.Location()).Format(time.RFC3339))
+		b.notify(ctx, session, msgTxt, delivery)
+		return
+	}
+
+	// watched listings and favorites are tracked independently of search queries, even when all of them are paused,
+	// their changes are not urgent and are not stored until reported, so they are checked once DND ends in skip mode
+	if delivery != deliverUrgent {
+		b.syncWatchedListings(ctx, session, delivery)
+		b.syncFavoriteListings(ctx, session, delivery)
+	}
+
+	activeSearchQueries := searchQueries.Active()
+	if len(activeSearchQueries) == 0 {
+		b.log.Info().Str("userID", session.UserID).Msg("no active search queries to sync")
+		return
+	}
+
+	// a broken blacklist must not stop the sync, listings are then reported unfiltered
+	filter, err := b.blacklistService.GetFilter(ctx, session.UserID)
+	if err != nil {
+		b.log.Error().Err(err).Str("userID", session.UserID).Msg("failed to get blacklist for sync")
+	}
+
+	addedListings := make(listings.Listings, 0, len(activeSearchQueries))
+	messages := make([]string, 0, len(activeSearchQueries))
+	for idx := range activeSearchQueries {
+		queryAddedListings, msgTxt := b.syncerIteration(ctx, session, &activeSearchQueries[idx], filter, urgentRule, forceSendMessage, delivery)
+		addedListings = append(addedListings, queryAddedListings...)
+		if msgTxt != "" {
+			messages = append(messages, msgTxt)
+		}
+	}
+	// unseen listings are counted once all queries are synced and the count goes to the last sync message
+	if len(messages) != 0 {
+		messages[len(messages)-1] += b.commands.UnseenCounter(ctx, session)
+	}
+	for idx := range messages {
+		b.notify(ctx, session, messages[idx], delivery)
+	}
+
+	if urgentRule != nil {
+		b.sendUrgentAlerts(ctx, session, urgentRule, addedListings.UniqueByObjectID())
+	}
+}
+
+func (b *TelegramBot) remindViewings(ctx context.Context, session *sessions.Session) {
+	dueViewings, err := b.viewingsService.MGetDueViewing(ctx, session.UserID)
+	if err != nil {
+		b.log.Error().Err(err).Str("userID", session.UserID).Msg("failed to get viewings to remind of")
+		return
+	}
+	// a viewing is marked as reminded only once its reminder is sent, otherwise it is retried upon the next sync
+	for idx := range dueViewings {
+		msgTxt := "⏰Viewing reminder\n" + commands.FormatViewing(&dueViewings[idx], session.Location())
+		if err = b.sendMessage(session.ChatID, session.UserID, msgTxt, false); err != nil {
+			continue
+		}
+		_ = b.viewingsService.MarkReminded(ctx, &dueViewings[idx])
+	}
+}
+
+func (b *TelegramBot) syncFavoriteListings(ctx context.Context, session *sessions.Session, delivery delivery) {
+	changes, err := b.listingsService.CheckFavoriteListings(ctx, session.UserID)
+	if err != nil {
+		b.log.Error().Err(err).Str("userID", session.UserID).Msg("failed to check favorite listings")
 		return
 	}
+	for idx := range changes {
+		b.notify(ctx, session, commands.FormatListingChange(&changes[idx]), delivery)
+	}
+}
 
-	addedListings, removedListings, _, err := b.listingsService.UpdateAndCompareListings(ctx, session.UserID, searchQuery)
+func (b *TelegramBot) syncWatchedListings(ctx context.Context, session *sessions.Session, delivery delivery) {
+	changes, err := b.watchedListingsService.CheckWatchedListings(ctx, session.UserID)
 	if err != nil {
-		b.log.Error().Err(err).Str("userID", session.UserID).Msg("failed to compare and update listings within sync iteration")
-		msgTxt := fmt.Sprintf("📅Updated at %s\n💥failed to get listings updates", time.Now().Format(time.RFC3339))
-		b.sendMessage(session.ChatID, session.UserID, msgTxt, false)
+		b.log.Error().Err(err).Str("userID", session.UserID).Msg("failed to check watched listings")
 		return
 	}
+	for idx := range changes {
+		b.notify(ctx, session, commands.FormatListingChange(&changes[idx]), delivery)
+	}
+}
 
-	filteredAddedListings := addedListings.FilterByRegionsAndCities(session.Regions, session.Cities)
-	filteredRemovedListings := removedListings.FilterByRegionsAndCities(session.Regions, session.Cities)
-	if len(filteredAddedListings) != 0 || forceSendMessage {
-		msgTxt := fmt.Sprintf("📅Updated at %s\n➕Added listings count: %d\n➖Removed listings count: %d", time.Now().Format(time.RFC3339), len(filteredAddedListings), len(filteredRemovedListings))
-		b.sendMessage(session.ChatID, session.UserID, msgTxt, false)
+// syncerIteration syncs a single search query and returns newly added listings which passed all filters along with the
+// sync message to send, which is empty when there is nothing to tell.
+func (b *TelegramBot) syncerIteration(ctx context.Context, session *sessions.Session, searchQuery *search_queries.SearchQuery, filter *blacklist.Filter, urgentRule *urgent_rules.Rule, forceSendMessage bool, delivery delivery) (listings.Listings, string) {
+	update, err := b.listingsService.UpdateAndCompareListings(ctx, session.UserID, searchQuery.Name, searchQuery.Version, searchQuery.URL)
+	if err != nil {
+		b.log.Error().Err(err).Str("userID", session.UserID).Str("queryName", searchQuery.Name).Msg("failed to compare and update listings within sync iteration")
+		msgTxt := fmt.Sprintf("📅Updated at %s\n🔎Query: %s\n💥failed to get listings updates", time.Now().In(session.Location()).Format(time.RFC3339), searchQuery.Name)
+		b.notify(ctx, session, msgTxt, delivery)
+		return nil, ""
 	}
+
+	filteredAddedListings := update.Added.FilterByRegionsAndCities(searchQuery.Regions, searchQuery.Cities)
+	filteredAddedListings = filteredAddedListings.FilterByRegionsAndCities(session.Regions, session.Cities)
+	filteredRemovedListings := update.Removed.FilterByRegionsAndCities(searchQuery.Regions, searchQuery.Cities)
+	filteredRemovedListings = filteredRemovedListings.FilterByRegionsAndCities(session.Regions, session.Cities)
+	filteredAddedListings = filter.Apply(filteredAddedListings)
+	filteredRemovedListings = filter.Apply(filteredRemovedListings)
+	filteredAddedListings, suppressed := b.commands.SuppressUnlikely(ctx, session, filteredAddedListings, urgentRule)
+	// forced messages are not worth holding, they carry no news, while suppressed listings are reported even when none
+	// are left to notify about
+	var msgTxt string
+	if len(filteredAddedListings) != 0 || suppressed != 0 || (forceSendMessage && delivery == deliverNow) {
+		msgTxt = fmt.Sprintf("📅Updated at %s\n🔎Query: %s\n➕Added listings count: %d\n➖Removed listings count: %d", time.Now().In(session.Location()).Format(time.RFC3339), searchQuery.Name, len(filteredAddedListings), len(filteredRemovedListings))
+		if update.IsTruncated {
+			msgTxt += "\n" + commands.TooBroadWarning(update.TotalCount, update.TrackedCount, searchQuery.URL)
+		}
+		msgTxt += commands.SuppressedCounter(suppressed)
+		msgTxt += b.commands.ScoreSummary(ctx, session, filteredAddedListings)
+	}
+
+	return filteredAddedListings, msgTxt
 }
 
 func escapeMarkdownV2(text string) string {
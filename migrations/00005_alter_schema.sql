@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+
+ALTER TABLE sessions ADD COLUMN timezone TEXT NOT NULL DEFAULT 'UTC';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+
+ALTER TABLE sessions DROP column timezone;
+-- +goose StatementEnd
@@ -0,0 +1,35 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+
+CREATE TABLE dnd_windows
+(
+    user_id             TEXT            NOT NULL,
+    name                TEXT            NOT NULL,
+    weekdays            TEXT            NOT NULL DEFAULT '0,1,2,3,4,5,6',
+    start_minutes       INTEGER         NOT NULL DEFAULT 0,
+    end_minutes         INTEGER         NOT NULL DEFAULT 0
+);
+CREATE UNIQUE INDEX dnd_windows_unique_user_id_name_idx ON dnd_windows(user_id, name);
+
+INSERT INTO dnd_windows (user_id, name, weekdays, start_minutes, end_minutes)
+SELECT user_id, 'default', '0,1,2,3,4,5,6', dnd_start, dnd_end FROM sessions WHERE dnd_start != dnd_end;
+
+ALTER TABLE sessions DROP column dnd_start;
+ALTER TABLE sessions DROP column dnd_end;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+
+ALTER TABLE sessions ADD column dnd_start INTEGER DEFAULT 0;
+ALTER TABLE sessions ADD column dnd_end INTEGER DEFAULT 0;
+
+UPDATE sessions SET
+    dnd_start = (SELECT start_minutes FROM dnd_windows WHERE dnd_windows.user_id = sessions.user_id AND dnd_windows.name = 'default'),
+    dnd_end = (SELECT end_minutes FROM dnd_windows WHERE dnd_windows.user_id = sessions.user_id AND dnd_windows.name = 'default')
+WHERE EXISTS (SELECT 1 FROM dnd_windows WHERE dnd_windows.user_id = sessions.user_id AND dnd_windows.name = 'default');
+
+DROP TABLE dnd_windows;
+-- +goose StatementEnd
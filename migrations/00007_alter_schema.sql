@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+
+ALTER TABLE sessions ADD COLUMN dnd_queue BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE outbox
+(
+    id                  INTEGER         PRIMARY KEY AUTOINCREMENT,
+    user_id             TEXT            NOT NULL,
+    message             TEXT            NOT NULL,
+    created_at          TIMESTAMP       NOT NULL
+);
+CREATE INDEX outbox_user_id_idx ON outbox(user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+
+DROP TABLE outbox;
+ALTER TABLE sessions DROP column dnd_queue;
+-- +goose StatementEnd
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+
+CREATE TABLE urgent_rules
+(
+    user_id             TEXT            NOT NULL PRIMARY KEY,
+    regions             TEXT            NOT NULL DEFAULT '',
+    cities              TEXT            NOT NULL DEFAULT '',
+    max_price           REAL            NOT NULL DEFAULT 0,
+    daily_cap           INTEGER         NOT NULL DEFAULT 3,
+    sent_count          INTEGER         NOT NULL DEFAULT 0,
+    sent_on             TEXT            NOT NULL DEFAULT ''
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+
+DROP TABLE urgent_rules;
+-- +goose StatementEnd
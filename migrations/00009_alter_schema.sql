@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+
+ALTER TABLE search_queries ADD column name TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE search_queries ADD column is_active BOOLEAN NOT NULL DEFAULT TRUE;
+ALTER TABLE search_queries ADD column regions TEXT NOT NULL DEFAULT '';
+ALTER TABLE search_queries ADD column cities TEXT NOT NULL DEFAULT '';
+DROP INDEX search_queries_unique_user_id_idx;
+CREATE UNIQUE INDEX search_queries_unique_user_id_name_idx ON search_queries(user_id, name);
+
+ALTER TABLE listings ADD column query_name TEXT NOT NULL DEFAULT 'default';
+DROP INDEX listings_unique_user_id_url_idx;
+CREATE UNIQUE INDEX listings_unique_user_id_query_name_url_idx ON listings(user_id, query_name, url);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+
+DELETE FROM listings WHERE query_name != 'default';
+DROP INDEX listings_unique_user_id_query_name_url_idx;
+CREATE UNIQUE INDEX listings_unique_user_id_url_idx ON listings(user_id, url);
+ALTER TABLE listings DROP column query_name;
+
+DELETE FROM search_queries WHERE name != 'default';
+DROP INDEX search_queries_unique_user_id_name_idx;
+CREATE UNIQUE INDEX search_queries_unique_user_id_idx ON search_queries(user_id);
+ALTER TABLE search_queries DROP column cities;
+ALTER TABLE search_queries DROP column regions;
+ALTER TABLE search_queries DROP column is_active;
+ALTER TABLE search_queries DROP column name;
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+
+ALTER TABLE listings ADD column object_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE favorites ADD column object_id TEXT NOT NULL DEFAULT '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+
+ALTER TABLE favorites DROP column object_id;
+ALTER TABLE listings DROP column object_id;
+-- +goose StatementEnd
@@ -0,0 +1,138 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"fundaNotifier/internal/pkg/funda"
+
+	"github.com/pressly/goose/v3"
+)
+
+// rekeyed rows are canonicalized in Go since SQLite cannot extract object IDs from URLs on its own
+func init() {
+	goose.AddMigrationContext(upRekeyListings, downRekeyListings)
+}
+
+type rekeyedRow struct {
+	rowID int64
+	url   string
+}
+
+func upRekeyListings(ctx context.Context, tx *sql.Tx) error {
+	statements := []string{
+		"DROP INDEX listings_unique_user_id_query_name_url_idx;",
+		"DROP INDEX favorites_unique_user_id_url_idx;",
+	}
+	if err := execStatements(ctx, tx, statements); err != nil {
+		return err
+	}
+
+	for _, table := range []string{"listings", "favorites"} {
+		if err := rekeyTable(ctx, tx, table); err != nil {
+			return err
+		}
+	}
+	if err := canonicalizeSearchQueries(ctx, tx); err != nil {
+		return err
+	}
+
+	// listings which turned out to be the same object are collapsed, keeping the oldest row
+	statements = []string{
+		"DELETE FROM listings WHERE rowid NOT IN (SELECT MIN(rowid) FROM listings GROUP BY user_id, query_name, object_id);",
+		"DELETE FROM favorites WHERE rowid NOT IN (SELECT MIN(rowid) FROM favorites GROUP BY user_id, object_id);",
+		"CREATE UNIQUE INDEX listings_unique_user_id_query_name_object_id_idx ON listings(user_id, query_name, object_id);",
+		"CREATE UNIQUE INDEX favorites_unique_user_id_object_id_idx ON favorites(user_id, object_id);",
+	}
+	return execStatements(ctx, tx, statements)
+}
+
+func downRekeyListings(ctx context.Context, tx *sql.Tx) error {
+	statements := []string{
+		"DROP INDEX listings_unique_user_id_query_name_object_id_idx;",
+		"DROP INDEX favorites_unique_user_id_object_id_idx;",
+		"DELETE FROM listings WHERE rowid NOT IN (SELECT MIN(rowid) FROM listings GROUP BY user_id, query_name, url);",
+		"DELETE FROM favorites WHERE rowid NOT IN (SELECT MIN(rowid) FROM favorites GROUP BY user_id, url);",
+		"CREATE UNIQUE INDEX listings_unique_user_id_query_name_url_idx ON listings(user_id, query_name, url);",
+		"CREATE UNIQUE INDEX favorites_unique_user_id_url_idx ON favorites(user_id, url);",
+	}
+	return execStatements(ctx, tx, statements)
+}
+
+func rekeyTable(ctx context.Context, tx *sql.Tx, table string) error {
+	rows, err := selectRows(ctx, tx, "SELECT rowid, url FROM "+table+";")
+	if err != nil {
+		return err
+	}
+
+	stmt, err := tx.PrepareContext(ctx, "UPDATE "+table+" SET url = ?, object_id = ? WHERE rowid = ?;")
+	if err != nil {
+		return fmt.Errorf("failed to prepare statement for %s: %w", table, err)
+	}
+	defer stmt.Close()
+
+	for idx := range rows {
+		canonicalURL, err := funda.CanonicalListingURL(rows[idx].url)
+		if err != nil {
+			canonicalURL = rows[idx].url
+		}
+		objectID, err := funda.ObjectID(canonicalURL)
+		if err != nil {
+			objectID = canonicalURL
+		}
+		if _, err = stmt.ExecContext(ctx, canonicalURL, objectID, rows[idx].rowID); err != nil {
+			return fmt.Errorf("failed to rekey %s: %w", table, err)
+		}
+	}
+	return nil
+}
+
+func canonicalizeSearchQueries(ctx context.Context, tx *sql.Tx) error {
+	rows, err := selectRows(ctx, tx, "SELECT rowid, search_query FROM search_queries;")
+	if err != nil {
+		return err
+	}
+
+	for idx := range rows {
+		canonicalURL, err := funda.CanonicalSearchURL(rows[idx].url)
+		if err != nil {
+			// keep queries the canonicalizer does not understand as they are
+			continue
+		}
+		if _, err = tx.ExecContext(ctx, "UPDATE search_queries SET search_query = ? WHERE rowid = ?;", canonicalURL, rows[idx].rowID); err != nil {
+			return fmt.Errorf("failed to canonicalize search query: %w", err)
+		}
+	}
+	return nil
+}
+
+func selectRows(ctx context.Context, tx *sql.Tx, query string) ([]rekeyedRow, error) {
+	rows, err := tx.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+
+	// iterate over rows
+	result := make([]rekeyedRow, 0)
+	for rows.Next() {
+		var row rekeyedRow
+		if err = rows.Scan(&row.rowID, &row.url); err != nil {
+			return nil, fmt.Errorf("failed to scan a row: %w", err)
+		}
+		result = append(result, row)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over rows: %w", err)
+	}
+	return result, nil
+}
+
+func execStatements(ctx context.Context, tx *sql.Tx, statements []string) error {
+	for idx := range statements {
+		if _, err := tx.ExecContext(ctx, statements[idx]); err != nil {
+			return fmt.Errorf("failed to execute %q: %w", statements[idx], err)
+		}
+	}
+	return nil
+}
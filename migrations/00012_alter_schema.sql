@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+
+CREATE TABLE search_query_versions
+(
+    user_id             TEXT            NOT NULL,
+    query_name          TEXT            NOT NULL,
+    version             INTEGER         NOT NULL,
+    search_query        TEXT            NOT NULL,
+    created_at          TIMESTAMP       NOT NULL
+);
+CREATE UNIQUE INDEX search_query_versions_unique_user_id_query_name_version_idx ON search_query_versions(user_id, query_name, version);
+INSERT INTO search_query_versions (user_id, query_name, version, search_query, created_at) SELECT user_id, name, 1, search_query, CURRENT_TIMESTAMP FROM search_queries;
+
+ALTER TABLE search_queries ADD column version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE listings ADD column query_version INTEGER NOT NULL DEFAULT 1;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+
+ALTER TABLE listings DROP column query_version;
+ALTER TABLE search_queries DROP column version;
+DROP TABLE search_query_versions;
+-- +goose StatementEnd
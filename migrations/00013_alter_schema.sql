@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+
+CREATE TABLE watched_listings
+(
+    user_id             TEXT            NOT NULL,
+    object_id           TEXT            NOT NULL,
+    url                 TEXT            NOT NULL,
+    name                TEXT            NOT NULL,
+    city                TEXT            NOT NULL,
+    currency            TEXT            NOT NULL,
+    price               NUMERIC         NOT NULL,
+    status              TEXT            NOT NULL,
+    is_gone             BOOLEAN         NOT NULL,
+    created_at          TIMESTAMP       NOT NULL,
+    checked_at          TIMESTAMP       NOT NULL
+);
+CREATE UNIQUE INDEX watched_listings_unique_user_id_object_id_idx ON watched_listings(user_id, object_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+
+DROP TABLE watched_listings;
+-- +goose StatementEnd
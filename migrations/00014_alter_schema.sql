@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+
+ALTER TABLE favorites ADD column note TEXT NOT NULL DEFAULT '';
+ALTER TABLE favorites ADD column rank INTEGER NOT NULL DEFAULT 0;
+UPDATE favorites SET rank = (SELECT COUNT(*) FROM favorites AS f WHERE f.user_id = favorites.user_id AND f.rowid <= favorites.rowid);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+
+ALTER TABLE favorites DROP column rank;
+ALTER TABLE favorites DROP column note;
+-- +goose StatementEnd
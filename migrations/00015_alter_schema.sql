@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+
+ALTER TABLE favorites ADD column status TEXT NOT NULL DEFAULT '';
+ALTER TABLE favorites ADD column is_gone BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE favorites ADD column checked_at TIMESTAMP NOT NULL DEFAULT '0001-01-01 00:00:00+00:00';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+
+ALTER TABLE favorites DROP column checked_at;
+ALTER TABLE favorites DROP column is_gone;
+ALTER TABLE favorites DROP column status;
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+
+CREATE TABLE price_history
+(
+    user_id             TEXT            NOT NULL,
+    object_id           TEXT            NOT NULL,
+    currency            TEXT            NOT NULL,
+    price               NUMERIC         NOT NULL,
+    recorded_at         TIMESTAMP       NOT NULL
+);
+CREATE INDEX price_history_user_id_object_id_recorded_at_idx ON price_history(user_id, object_id, recorded_at);
+INSERT INTO price_history (user_id, object_id, currency, price, recorded_at) SELECT user_id, object_id, currency, price, MIN(created_at) FROM listings GROUP BY user_id, object_id;
+INSERT INTO price_history (user_id, object_id, currency, price, recorded_at) SELECT f.user_id, f.object_id, f.currency, f.price, CURRENT_TIMESTAMP FROM favorites AS f WHERE NOT EXISTS (SELECT 1 FROM price_history AS p WHERE p.user_id = f.user_id AND p.object_id = f.object_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+
+DROP TABLE price_history;
+-- +goose StatementEnd
@@ -0,0 +1,36 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+
+ALTER TABLE listings ADD column address_postal_code TEXT NOT NULL DEFAULT '';
+ALTER TABLE listings ADD column agent TEXT NOT NULL DEFAULT '';
+
+CREATE TABLE hidden_listings
+(
+    user_id             TEXT            NOT NULL,
+    object_id           TEXT            NOT NULL,
+    name                TEXT            NOT NULL,
+    url                 TEXT            NOT NULL,
+    created_at          TIMESTAMP       NOT NULL
+);
+CREATE UNIQUE INDEX hidden_listings_unique_user_id_object_id_idx ON hidden_listings(user_id, object_id);
+
+CREATE TABLE blacklist_rules
+(
+    user_id             TEXT            NOT NULL,
+    kind                TEXT            NOT NULL,
+    value               TEXT            NOT NULL,
+    created_at          TIMESTAMP       NOT NULL
+);
+CREATE UNIQUE INDEX blacklist_rules_unique_user_id_kind_value_idx ON blacklist_rules(user_id, kind, value);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+
+DROP TABLE blacklist_rules;
+DROP TABLE hidden_listings;
+ALTER TABLE listings DROP column agent;
+ALTER TABLE listings DROP column address_postal_code;
+-- +goose StatementEnd
@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+
+ALTER TABLE favorites ADD column stage TEXT NOT NULL DEFAULT 'interested';
+ALTER TABLE favorites ADD column stage_changed_at TIMESTAMP NOT NULL DEFAULT '0001-01-01 00:00:00+00:00';
+UPDATE favorites SET stage_changed_at = CURRENT_TIMESTAMP;
+
+CREATE TABLE pipeline_events
+(
+    user_id             TEXT            NOT NULL,
+    object_id           TEXT            NOT NULL,
+    stage               TEXT            NOT NULL,
+    created_at          TIMESTAMP       NOT NULL
+);
+CREATE INDEX pipeline_events_user_id_object_id_created_at_idx ON pipeline_events(user_id, object_id, created_at);
+INSERT INTO pipeline_events (user_id, object_id, stage, created_at) SELECT user_id, object_id, stage, stage_changed_at FROM favorites;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+
+DROP TABLE pipeline_events;
+ALTER TABLE favorites DROP column stage_changed_at;
+ALTER TABLE favorites DROP column stage;
+-- +goose StatementEnd
@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+
+CREATE TABLE viewings
+(
+    user_id             TEXT            NOT NULL,
+    object_id           TEXT            NOT NULL,
+    name                TEXT            NOT NULL,
+    address             TEXT            NOT NULL,
+    url                 TEXT            NOT NULL,
+    starts_at           TIMESTAMP       NOT NULL,
+    remind_at           TIMESTAMP       NOT NULL,
+    is_reminded         BOOLEAN         NOT NULL,
+    created_at          TIMESTAMP       NOT NULL
+);
+CREATE UNIQUE INDEX viewings_unique_user_id_object_id_idx ON viewings(user_id, object_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+
+DROP TABLE viewings;
+-- +goose StatementEnd
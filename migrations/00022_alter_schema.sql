@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+
+-- requires SQLite built with FTS5, i.e. binaries built with the sqlite_fts5 tag
+CREATE VIRTUAL TABLE listings_search USING fts5
+(
+    user_id UNINDEXED,
+    query_name UNINDEXED,
+    object_id UNINDEXED,
+    name,
+    description,
+    address,
+    tokenize = 'unicode61 remove_diacritics 2'
+);
+INSERT INTO listings_search (user_id, query_name, object_id, name, description, address)
+SELECT user_id, query_name, object_id, name, description, address_street || ' ' || address_postal_code || ' ' || address_locality || ' ' || address_region FROM listings;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+
+DROP TABLE listings_search;
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+
+CREATE TABLE seen_listings
+(
+    user_id             TEXT            NOT NULL,
+    object_id           TEXT            NOT NULL,
+    seen_at             TIMESTAMP       NOT NULL
+);
+CREATE UNIQUE INDEX seen_listings_unique_user_id_object_id_idx ON seen_listings(user_id, object_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+
+DROP TABLE seen_listings;
+-- +goose StatementEnd
@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+
+ALTER TABLE listings ADD column rooms INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE listings ADD column energy_label TEXT NOT NULL DEFAULT '';
+ALTER TABLE listings ADD column latitude REAL NOT NULL DEFAULT 0;
+ALTER TABLE listings ADD column longitude REAL NOT NULL DEFAULT 0;
+
+CREATE TABLE scoring_profiles
+(
+    user_id             TEXT            NOT NULL,
+    weights             TEXT            NOT NULL,
+    keywords            TEXT            NOT NULL,
+    points              TEXT            NOT NULL
+);
+CREATE UNIQUE INDEX scoring_profiles_unique_user_id_idx ON scoring_profiles(user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+
+DROP TABLE scoring_profiles;
+ALTER TABLE listings DROP column longitude;
+ALTER TABLE listings DROP column latitude;
+ALTER TABLE listings DROP column energy_label;
+ALTER TABLE listings DROP column rooms;
+-- +goose StatementEnd
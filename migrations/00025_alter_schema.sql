@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+
+CREATE TABLE listing_feedback
+(
+    user_id             TEXT            NOT NULL,
+    object_id           TEXT            NOT NULL,
+    liked               BOOLEAN         NOT NULL,
+    features            TEXT            NOT NULL,
+    created_at          TIMESTAMP       NOT NULL
+);
+CREATE UNIQUE INDEX listing_feedback_unique_user_id_object_id_idx ON listing_feedback(user_id, object_id);
+
+CREATE TABLE preference_settings
+(
+    user_id             TEXT            NOT NULL,
+    threshold           INTEGER         NOT NULL
+);
+CREATE UNIQUE INDEX preference_settings_unique_user_id_idx ON preference_settings(user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+
+DROP TABLE preference_settings;
+DROP TABLE listing_feedback;
+-- +goose StatementEnd
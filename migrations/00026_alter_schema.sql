@@ -0,0 +1,54 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+
+-- search rows share the rowid of their listing and are kept in sync by triggers, so that they are found without
+-- scanning the whole index
+DROP TABLE listings_search;
+CREATE VIRTUAL TABLE listings_search USING fts5
+(
+    name,
+    description,
+    address,
+    tokenize = 'unicode61 remove_diacritics 2'
+);
+INSERT INTO listings_search (rowid, name, description, address)
+SELECT rowid, name, description, address_street || ' ' || address_postal_code || ' ' || address_locality || ' ' || address_region FROM listings;
+
+CREATE TRIGGER listings_search_insert AFTER INSERT ON listings
+BEGIN
+    INSERT INTO listings_search (rowid, name, description, address)
+    VALUES (new.rowid, new.name, new.description, new.address_street || ' ' || new.address_postal_code || ' ' || new.address_locality || ' ' || new.address_region);
+END;
+CREATE TRIGGER listings_search_update AFTER UPDATE OF name, description, address_street, address_postal_code, address_locality, address_region ON listings
+BEGIN
+    UPDATE listings_search SET name = new.name, description = new.description, address = new.address_street || ' ' || new.address_postal_code || ' ' || new.address_locality || ' ' || new.address_region
+    WHERE rowid = new.rowid;
+END;
+CREATE TRIGGER listings_search_delete AFTER DELETE ON listings
+BEGIN
+    DELETE FROM listings_search WHERE rowid = old.rowid;
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+
+DROP TRIGGER listings_search_insert;
+DROP TRIGGER listings_search_update;
+DROP TRIGGER listings_search_delete;
+DROP TABLE listings_search;
+CREATE VIRTUAL TABLE listings_search USING fts5
+(
+    user_id UNINDEXED,
+    query_name UNINDEXED,
+    object_id UNINDEXED,
+    name,
+    description,
+    address,
+    tokenize = 'unicode61 remove_diacritics 2'
+);
+INSERT INTO listings_search (user_id, query_name, object_id, name, description, address)
+SELECT user_id, query_name, object_id, name, description, address_street || ' ' || address_postal_code || ' ' || address_locality || ' ' || address_region FROM listings;
+-- +goose StatementEnd
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+
+-- listings stored before seen tracking was introduced were already notified about, so only the ones added by the
+-- latest sync are left unseen
+INSERT OR IGNORE INTO seen_listings (user_id, object_id, seen_at)
+SELECT user_id, object_id, MIN(created_at) FROM listings WHERE is_new IS FALSE GROUP BY user_id, object_id;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+
+-- seeded rows cannot be told apart from listings marked as seen by users, so they are kept
+-- +goose StatementEnd